@@ -192,7 +192,7 @@ func (m *Portctl) SnapshotTest(ctx context.Context, src *dagger.Directory) (stri
 
 // +dagger:call=generateManifest
 // --- Generate Manifest Step ---
-// GenerateManifest creates the MCP manifest from the actual tool definitions in code
+// GenerateManifest creates the MCP manifest by asking the binary for its registered tools
 func (m *Portctl) GenerateManifest(ctx context.Context, src *dagger.Directory) (string, error) {
 	fmt.Println("[Dagger] Starting generateManifest step...")
 	goModCache := m.goModCache()
@@ -201,75 +201,9 @@ func (m *Portctl) GenerateManifest(ctx context.Context, src *dagger.Directory) (
 		WithMountedDirectory("/src", src).
 		WithWorkdir("/src").
 		WithMountedCache("/go/pkg/mod", goModCache).
-		WithExec([]string{"sh", "-c", `
-cat > /tmp/gen-manifest.go << 'GENEOF'
-package main
-import (
-	"encoding/json"
-	"os"
-)
-func main() {
-	manifest := map[string]interface{}{
-		"@context": "https://www.w3.org/ns/activitystreams",
-		"type": "Service",
-		"name": "portctl",
-		"version": "1.0.0",
-		"description": "Secure, cross-platform CLI for managing processes on ports",
-		"homepage": "https://github.com/ckodex-labs/portctl",
-		"documentation": "https://ckodex-labs.github.io/portctl",
-		"protocol": "mcp",
-		"capabilities": map[string]bool{"tools": true, "resources": true, "logging": true},
-		"tools": []map[string]interface{}{
-			{
-				"name": "list_processes",
-				"description": "List running processes, optionally filtered by port or service",
-				"inputSchema": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"port": map[string]string{"type": "number", "description": "Specific port to check"},
-						"service": map[string]string{"type": "string", "description": "Filter by service name"},
-					},
-				},
-			},
-			{
-				"name": "kill_process",
-				"description": "Kill a process by PID or Port",
-				"inputSchema": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"pid": map[string]string{"type": "number", "description": "Process ID to kill"},
-						"port": map[string]string{"type": "number", "description": "Port number to kill processes on"},
-						"force": map[string]string{"type": "boolean", "description": "Force kill (SIGKILL)"},
-					},
-				},
-			},
-			{
-				"name": "scan_ports",
-				"description": "Scan for open ports on a host",
-				"inputSchema": map[string]interface{}{
-					"type": "object",
-					"properties": map[string]interface{}{
-						"host": map[string]string{"type": "string", "description": "Host to scan (default: localhost)"},
-						"start_port": map[string]string{"type": "number", "description": "Start of port range"},
-						"end_port": map[string]string{"type": "number", "description": "End of port range"},
-					},
-				},
-			},
-			{
-				"name": "get_system_stats",
-				"description": "Get system resource usage and statistics",
-				"inputSchema": map[string]interface{}{"type": "object", "properties": map[string]interface{}{}},
-			},
-		},
-		"integration": map[string]string{"command": "portctl mcp", "transport": "stdio", "format": "json-rpc"},
-	}
-	data, _ := json.MarshalIndent(manifest, "", "  ")
-	os.WriteFile(".well-known/mcp-manifest.jsonld", data, 0644)
-}
-GENEOF
-go run /tmp/gen-manifest.go
-cat .well-known/mcp-manifest.jsonld
-`}).
+		WithExec([]string{"go", "build", "-o", "/tmp/portctl", "./cmd/portctl"}).
+		WithExec([]string{"/tmp/portctl", "mcp", "manifest", "--output", ".well-known/mcp-manifest.jsonld"}).
+		WithExec([]string{"cat", ".well-known/mcp-manifest.jsonld"}).
 		Stdout(ctx)
 
 	if err != nil {
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/fatih/color"
+	tablepretty "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var (
+	auditJSON        bool
+	auditFailOn      string
+	auditMaxFindings int
+)
+
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Flag sensitive services that are reachable from other hosts",
+	Long: `Audit listening ports for sensitive services bound to all interfaces.
+
+Databases, admin panels and debug ports (such as the Node.js inspector on
+9229) are reported with a severity when they accept connections on every
+interface instead of loopback only.
+
+The command exits with code 3 when more than --max-findings findings are at
+or above the --fail-on severity, so it can gate CI pipelines.
+
+Examples:
+  portctl audit                          # Human-readable findings report
+  portctl audit --json                   # Machine-readable report
+  portctl audit --fail-on critical       # Only fail on critical findings
+  portctl audit --max-findings 2         # Tolerate up to two findings`,
+	Args: cobra.NoArgs,
+	Run:  runAudit,
+}
+
+// auditReport is the result of a security audit
+type auditReport struct {
+	Findings  []process.AuditFinding `json:"findings"`
+	Total     int                    `json:"total"`
+	FailOn    process.Severity       `json:"fail_on"`
+	Threshold int                    `json:"threshold"`
+	Failing   int                    `json:"failing"`
+	Passed    bool                   `json:"passed"`
+}
+
+func runAudit(cmd *cobra.Command, args []string) {
+	failOn, err := process.ParseSeverity(auditFailOn)
+	if err != nil {
+		exitWithError(auditJSON, exitCodeUsage, "%v", err)
+	}
+	if auditMaxFindings < 0 {
+		exitWithError(auditJSON, exitCodeUsage, "--max-findings must not be negative")
+	}
+
+	pm := newProcessManager()
+	ctx := cmd.Context()
+
+	processes, err := pm.GetAllProcesses(ctx)
+	if err != nil {
+		exitWithError(auditJSON, exitCodeError, "Error getting processes: %v", err)
+	}
+	hint := pm.PrivilegeHint(ctx, processes, 0)
+
+	findings := process.Audit(processes, process.DefaultAuditRules)
+	if findings == nil {
+		findings = []process.AuditFinding{}
+	}
+	failing := process.CountFindings(findings, failOn)
+	report := auditReport{
+		Findings:  findings,
+		Total:     len(findings),
+		FailOn:    failOn,
+		Threshold: auditMaxFindings,
+		Failing:   failing,
+		Passed:    failing <= auditMaxFindings,
+	}
+
+	if auditJSON {
+		writeJSON(report)
+	} else {
+		displayAuditReport(report)
+		printPrivilegeHint(hint)
+	}
+
+	if !report.Passed {
+		os.Exit(exitCodeAudit)
+	}
+}
+
+func displayAuditReport(report auditReport) {
+	statusf(color.Cyan, "🔒 Security Audit\n")
+
+	if report.Total == 0 {
+		color.Green("✅ No sensitive services are reachable from other hosts")
+		return
+	}
+
+	t := tablepretty.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tablepretty.StyleColoredBright)
+	t.AppendHeader(tablepretty.Row{"Severity", "Port", "Service", "Category", "PID", "Command", "Address"})
+	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
+	t.SetColumnConfigs([]tablepretty.ColumnConfig{
+		{Number: 1, Align: text.AlignCenter},
+		{Number: 2, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}},
+		{Number: 5, Align: text.AlignRight},
+	})
+
+	for _, f := range report.Findings {
+		t.AppendRow(tablepretty.Row{
+			severityColor(f.Severity).Sprint(f.Severity),
+			f.Port,
+			f.Service,
+			f.Category,
+			f.PID,
+			f.Command,
+			f.LocalAddr,
+		})
+	}
+	t.Render()
+
+	statusf(color.Yellow, "\n%d finding(s), %d at or above %s (threshold %d)",
+		report.Total, report.Failing, report.FailOn, report.Threshold)
+	statusf(printfln, "Bind these services to 127.0.0.1 or firewall them to limit exposure")
+}
+
+// severityColor returns the display color for a finding severity
+func severityColor(s process.Severity) *color.Color {
+	switch s {
+	case process.SeverityCritical:
+		return color.New(color.FgRed, color.Bold)
+	case process.SeverityHigh:
+		return color.New(color.FgRed)
+	case process.SeverityMedium:
+		return color.New(color.FgYellow)
+	default:
+		return color.New(color.FgWhite)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	auditCmd.Flags().BoolVarP(&auditJSON, "json", "j", false,
+		"Output in JSON format")
+	auditCmd.Flags().StringVar(&auditFailOn, "fail-on", "high",
+		"Minimum severity counted against --max-findings (low, medium, high, critical)")
+	auditCmd.Flags().IntVar(&auditMaxFindings, "max-findings", 0,
+		"Exit with code 3 when more findings than this are at or above --fail-on")
+}
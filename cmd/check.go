@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var (
+	checkFree    bool
+	checkVerbose bool
+	checkNone    string
+	checkAll     string
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check <port>",
+	Short: "Exit 0 if a port is in use, 1 if it is free",
+	Long: `Check whether a port is in use, reporting the answer only through the
+exit code, for shell conditionals. Unlike "wait", it never blocks.
+
+--none and --all assert on a list of ports instead, for CI: --none fails if
+any of the ports is in use (e.g. after teardown), --all fails if any of them
+is free (e.g. after startup). Both accept lists and ranges like
+"3000,3001,8000-8010", may be given together, and print each offending port.
+
+Exit codes:
+  0  The port is in use (with --free: the port is free); with --none/--all,
+     every port is as asserted
+  1  The port is free (with --free: the port is in use); with --none/--all,
+     at least one port is not
+  2  Invalid arguments, or a port could not be checked
+
+Examples:
+  if portctl check 8080; then echo "8080 is taken"; fi
+  portctl check 5432 --free || echo "Postgres port busy"
+  portctl check 3000 -v                # Also print who owns the port
+  portctl check --none 3000,3001,5432  # After teardown: nothing listens
+  portctl check --all 8080 --none 9090 # After startup: 8080 up, 9090 not`,
+	// Argument counts are checked in runCheck, so mistakes exit 2 and not 1
+	Args: cobra.ArbitraryArgs,
+	Run:  runCheck,
+}
+
+func runCheck(cmd *cobra.Command, args []string) {
+	if checkNone != "" || checkAll != "" {
+		if len(args) > 0 {
+			exitWithError(false, exitCodeUsage, "A port argument cannot be combined with --none or --all")
+		}
+		runCheckAssert(cmd.Context())
+		return
+	}
+	if len(args) != 1 {
+		exitWithError(false, exitCodeUsage, "Specify exactly one port, or use --none or --all")
+	}
+
+	port, err := strconv.Atoi(args[0])
+	if err != nil || port < process.MinPort || port > process.MaxPort {
+		exitWithError(false, exitCodeUsage, "Invalid port number: %s", args[0])
+	}
+
+	processes, err := newProcessManager().GetProcessesOnPort(cmd.Context(), port)
+	if err != nil {
+		// 1 would be read as an answer, so a failed check uses the usage code
+		exitWithError(false, exitCodeUsage, "Error checking port %d: %v", port, err)
+	}
+
+	if checkVerbose {
+		printCheckResult(port, processes)
+	}
+	os.Exit(checkExitCode(len(processes) > 0, checkFree))
+}
+
+// checkExitCode maps the state of a port to check's exit code: 0 when the
+// port is in use, or free with --free; 1 otherwise
+func checkExitCode(inUse, wantFree bool) int {
+	if inUse != wantFree {
+		return 0
+	}
+	return exitCodeError
+}
+
+// runCheckAssert checks the --none and --all port lists and exits 1 after
+// printing every port that is not as asserted (with -v, every port)
+func runCheckAssert(ctx context.Context) {
+	if checkFree {
+		exitWithError(false, exitCodeUsage, "--free cannot be combined with --none or --all")
+	}
+
+	var wantFree, wantUsed []int
+	for _, spec := range []struct {
+		flag  string
+		value string
+		ports *[]int
+	}{
+		{"--none", checkNone, &wantFree},
+		{"--all", checkAll, &wantUsed},
+	} {
+		if spec.value == "" {
+			continue
+		}
+		ports, err := process.ParsePorts(spec.value)
+		if err != nil {
+			exitWithError(false, exitCodeUsage, "Invalid %s ports: %v", spec.flag, err)
+		}
+		*spec.ports = ports
+	}
+
+	// One enumeration covers every port, however many are asserted
+	processes, err := newProcessManager().GetAllProcesses(ctx)
+	if err != nil {
+		exitWithError(false, exitCodeUsage, "Error getting processes: %v", err)
+	}
+	owners := make(map[int][]process.Process)
+	for _, proc := range processes {
+		owners[proc.Port] = append(owners[proc.Port], proc)
+	}
+
+	busy, free := checkOffenders(owners, wantFree, wantUsed)
+	shown := append(append([]int{}, busy...), free...)
+	if checkVerbose {
+		// Every port, not only the offenders
+		shown = append(append([]int{}, wantFree...), wantUsed...)
+	}
+	for _, port := range shown {
+		printCheckResult(port, owners[port])
+	}
+	if len(busy) > 0 || len(free) > 0 {
+		os.Exit(exitCodeError)
+	}
+	os.Exit(0)
+}
+
+// checkOffenders returns the ports of wantFree that have an owner in owners
+// and the ports of wantUsed that have none, each in the order given
+func checkOffenders(owners map[int][]process.Process, wantFree, wantUsed []int) (busy, free []int) {
+	for _, port := range wantFree {
+		if len(owners[port]) > 0 {
+			busy = append(busy, port)
+		}
+	}
+	for _, port := range wantUsed {
+		if len(owners[port]) == 0 {
+			free = append(free, port)
+		}
+	}
+	return busy, free
+}
+
+// printCheckResult prints "8080 in use by node (PID 123)" or "8080 free"
+func printCheckResult(port int, processes []process.Process) {
+	if len(processes) == 0 {
+		fmt.Printf("%d free\n", port)
+		return
+	}
+	for _, proc := range removeDuplicateProcesses(processes) {
+		fmt.Printf("%d in use by %s (PID %d)\n", port, proc.Command, proc.PID)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+
+	checkCmd.Flags().BoolVar(&checkFree, "free", false,
+		"Succeed when the port is free instead of in use")
+	checkCmd.Flags().BoolVarP(&checkVerbose, "verbose", "v", false,
+		"Print whether the port is free or which command owns it")
+	checkCmd.Flags().StringVar(&checkNone, "none", "",
+		"Fail, listing the offenders, if any of these ports is in use (e.g. '3000,3001,5432')")
+	checkCmd.Flags().StringVar(&checkAll, "all", "",
+		"Fail, listing the offenders, if any of these ports is free")
+}
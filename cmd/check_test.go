@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	process "dagger/portctl/pkg"
+)
+
+func TestCheckExitCode(t *testing.T) {
+	tests := []struct {
+		inUse, wantFree bool
+		want            int
+	}{
+		{true, false, 0},
+		{false, false, 1},
+		{false, true, 0},
+		{true, true, 1},
+	}
+	for _, tt := range tests {
+		if got := checkExitCode(tt.inUse, tt.wantFree); got != tt.want {
+			t.Errorf("checkExitCode(inUse=%v, free=%v) = %d, want %d", tt.inUse, tt.wantFree, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOffenders(t *testing.T) {
+	owners := map[int][]process.Process{
+		3000: {{PID: 10, Port: 3000}},
+		5432: {{PID: 11, Port: 5432}},
+		8080: nil,
+	}
+
+	busy, free := checkOffenders(owners, []int{3000, 3001, 5432}, []int{8080, 5432})
+	if !reflect.DeepEqual(busy, []int{3000, 5432}) {
+		t.Errorf("busy = %v, want [3000 5432]", busy)
+	}
+	if !reflect.DeepEqual(free, []int{8080}) {
+		t.Errorf("free = %v, want [8080]", free)
+	}
+
+	busy, free = checkOffenders(owners, []int{3001}, []int{3000})
+	if busy != nil || free != nil {
+		t.Errorf("Expected no offenders, got busy %v and free %v", busy, free)
+	}
+}
+
+// TestCheckHelper runs portctl with the arguments in PORTCTL_TEST_ARGS when
+// re-executed by runPortctl; otherwise it does nothing
+func TestCheckHelper(t *testing.T) {
+	args := os.Getenv("PORTCTL_TEST_ARGS")
+	if args == "" {
+		return
+	}
+	rootCmd.SetArgs(strings.Fields(args))
+	Execute()
+	os.Exit(0)
+}
+
+// runPortctl runs portctl in a child process and returns its exit code
+func runPortctl(t *testing.T, args ...string) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCheckHelper$")
+	cmd.Env = append(os.Environ(), "PORTCTL_TEST_ARGS="+strings.Join(args, " "))
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatalf("running portctl %v: %v", args, err)
+	}
+	return 0
+}
+
+func TestCheckCommandExitCodes(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	used := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+
+	// A port that was just released is almost certainly still free
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	freePort := strconv.Itoa(free.Addr().(*net.TCPAddr).Port)
+	_ = free.Close()
+
+	// The listener belongs to this test process, which lsof or /proc must see
+	if runPortctl(t, "check", used) != 0 {
+		t.Skip("this test process's own listener is not visible to port enumeration here")
+	}
+
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{[]string{"check", freePort}, 1},
+		{[]string{"check", used, "--free"}, 1},
+		{[]string{"check", freePort, "--free"}, 0},
+		{[]string{"check", "not-a-port"}, exitCodeUsage},
+		{[]string{"check", "70000"}, exitCodeUsage},
+		{[]string{"check", "--none", freePort}, 0},
+		{[]string{"check", "--none", freePort + "," + used}, 1},
+		{[]string{"check", "--all", used}, 0},
+		{[]string{"check", "--all", used + "," + freePort}, 1},
+		{[]string{"check", "--all", used, "--none", freePort}, 0},
+		{[]string{"check", "--none", "3000-abc"}, exitCodeUsage},
+		{[]string{"check", "--none", freePort, "--free"}, exitCodeUsage},
+		{[]string{"check", used, "--all", used}, exitCodeUsage},
+		{[]string{"check"}, exitCodeUsage},
+	}
+	for _, tt := range tests {
+		if got := runPortctl(t, tt.args...); got != tt.want {
+			t.Errorf("portctl %s exited %d, want %d", strings.Join(tt.args, " "), got, tt.want)
+		}
+	}
+}
@@ -2,14 +2,23 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/yaml.v3"
+
+	process "dagger/portctl/pkg"
 )
 
 var configCmd = &cobra.Command{
@@ -33,7 +42,9 @@ Examples:
   portctl config set notifications.enabled true
   portctl config get watch.interval
   portctl config list
-  portctl config reset`,
+  portctl config reset
+  portctl config export > portctl.yaml
+  portctl config import portctl.yaml`,
 }
 
 var configSetCmd = &cobra.Command{
@@ -48,9 +59,23 @@ Available configuration keys:
   output.colors          - Enable colored output (true/false)
   scan.timeout           - Default scan timeout (e.g., "3s", "1m")
   scan.concurrent        - Default concurrent scans (number)
+  scan.cache-ttl         - How long scan reuses cached results per host and port (e.g., "10m"; "0s" disables)
   kill.confirm           - Require confirmation before killing (true/false)
-  list.sort              - Default sort field (port/pid/cpu/memory/command)
+  kill.max-batch         - Max processes killed at once without --confirm-batch (number, 0 = no limit)
+  list.sort              - Default sort fields, comma-separated (port/pid/cpu/memory/command/service/user)
   dev.ports              - Custom development port range (e.g., "3000-8999")
+  service.definitions    - YAML file of custom port and command service names
+  display.cpu-warn       - CPU% at which list and watch color a cell yellow; red at twice this (0 = off)
+  display.mem-warn       - Memory in MB at which list and watch color a cell yellow; red at twice this (0 = off)
+  server.cache-ttl       - How long grpc and mcp reuse a process listing (e.g., "2s"; "0s" disables)
+  server.metrics         - Enrich grpc and mcp listings with CPU, memory and user (true/false; false is faster)
+  server.request-timeout - Longest a single grpc or mcp call other than a port scan may run (e.g., "30s"; "0s" disables)
+  enum.timeout           - Longest a single lsof/netstat enumeration may run, see --enum-timeout (e.g., "10s"; "0s" disables)
+
+Any key can be overridden for one invocation with a PORTCTL_ environment
+variable, with dots and dashes replaced by underscores (scan.concurrent is
+PORTCTL_SCAN_CONCURRENT). Command-line flags beat environment variables, which
+beat the config file, which beats the defaults.
 
 Examples:
   portctl config set watch.interval 1s
@@ -75,10 +100,16 @@ Examples:
 var configListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configuration values",
-	Long: `Display all current configuration values and their sources.
+	Long: `Display the effective value of every configuration key and its source.
+
+The source is "flag" for a value given with a global flag bound to the key
+(such as --enum-timeout), "file" for values from the config file, "env" for a
+PORTCTL_ environment variable, "default" for built-in defaults, or "unset" for
+keys with no default. Flags of other commands override these per command.
 
-This shows your current settings, default values, and where each
-setting is being loaded from (config file, environment, or default).`,
+Examples:
+  portctl config list
+  portctl config list --enum-timeout 30s  # Shows enum.timeout from the flag`,
 	Run: runConfigList,
 }
 
@@ -109,23 +140,68 @@ You can also set the EDITOR environment variable to use a specific editor.`,
 	Run: runConfigEdit,
 }
 
+var configImportReplace bool
+
+var configExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the effective configuration as YAML",
+	Long: `Print every configuration key with its effective value (from the config
+file, or the default) as YAML, ready for "portctl config import" on another
+machine.
+
+Examples:
+  portctl config export > portctl.yaml`,
+	Args: cobra.NoArgs,
+	Run:  runConfigExport,
+}
+
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Load configuration from a YAML file",
+	Long: `Load configuration values from a YAML file such as one written by
+"portctl config export". Keys may be nested (watch: {interval: 2s}) or dotted
+(watch.interval: 2s).
+
+Every key and value is validated before anything is written, so a file with
+one bad entry changes nothing. By default the imported values are merged into
+the config file, keeping the keys it already sets; with --replace, keys not in
+the imported file revert to their defaults. Use "-" to read from stdin.
+
+Examples:
+  portctl config import team-defaults.yaml
+  portctl config import portctl.yaml --replace`,
+	Args: cobra.ExactArgs(1),
+	Run:  runConfigImport,
+}
+
+// validKeys maps every supported configuration key to its value type.
+// "duration0" is a duration where zero is also allowed, to turn a feature off.
+var validKeys = map[string]string{
+	"watch.interval":         "duration",
+	"watch.notifications":    "bool",
+	"output.format":          "string",
+	"output.colors":          "bool",
+	"scan.timeout":           "duration",
+	"scan.concurrent":        "int",
+	"scan.cache-ttl":         "duration0",
+	"kill.confirm":           "bool",
+	"kill.max-batch":         "int",
+	"list.sort":              "string",
+	"dev.ports":              "string",
+	"service.definitions":    "string",
+	"display.cpu-warn":       "float",
+	"display.mem-warn":       "float",
+	"server.cache-ttl":       "duration0",
+	"server.metrics":         "bool",
+	"server.request-timeout": "duration0",
+	"enum.timeout":           "duration0",
+}
+
 func runConfigSet(cmd *cobra.Command, args []string) {
 	key := args[0]
 	value := args[1]
 
 	// Validate the key
-	validKeys := map[string]string{
-		"watch.interval":      "duration",
-		"watch.notifications": "bool",
-		"output.format":       "string",
-		"output.colors":       "bool",
-		"scan.timeout":        "duration",
-		"scan.concurrent":     "int",
-		"kill.confirm":        "bool",
-		"list.sort":           "string",
-		"dev.ports":           "string",
-	}
-
 	valueType, exists := validKeys[key]
 	if !exists {
 		color.Red("Unknown configuration key: %s", key)
@@ -142,11 +218,17 @@ func runConfigSet(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	// Set the value
-	viper.Set(key, value)
+	// Set the value on top of what the file already holds, so defaults and
+	// environment overrides are not persisted with it
+	settings, err := fileConfig()
+	if err != nil {
+		color.Red("Error reading config: %v", err)
+		os.Exit(1)
+	}
+	settings.Set(key, value)
 
 	// Write config file
-	if err := writeConfig(); err != nil {
+	if err := writeConfigFrom(settings); err != nil {
 		color.Red("Error writing config: %v", err)
 		os.Exit(1)
 	}
@@ -169,23 +251,53 @@ func runConfigList(cmd *cobra.Command, args []string) {
 	color.Cyan("📋 portctl Configuration")
 	fmt.Println()
 
-	settings := viper.AllSettings()
-	if len(settings) == 0 {
-		color.Yellow("No configuration values set (using defaults)")
-		return
-	}
-
-	for key, value := range settings {
-		color.Green("  %s = %v", key, value)
+	fmt.Printf("  %-20s %-20s %s\n", "KEY", "VALUE", "SOURCE")
+	for _, key := range sortedConfigKeys() {
+		value := viper.GetString(key)
+		if value == "" {
+			value = "-"
+		}
+		source := configSource(cmd, key)
+		if flagValue, ok := changedConfigFlag(cmd, key); ok {
+			value = flagValue
+		}
+		line := fmt.Sprintf("  %-20s %-20s %s", key, value, source)
+		switch source {
+		case "default", "unset":
+			fmt.Println(line)
+		default:
+			color.Green("%s", line)
+		}
 	}
 
 	fmt.Println()
 	configFile := viper.ConfigFileUsed()
 	if configFile != "" {
 		color.Cyan("📁 Config file: %s", configFile)
+	} else {
+		color.Cyan("📁 No config file found; changes will be written to %s", getConfigFile())
 	}
 }
 
+// configSource reports where the effective value of key comes from: "flag"
+// when a flag of cmd bound to key was given, "env" for a PORTCTL_ variable,
+// "file" for the config file, "default", or "unset" when the key has no value
+func configSource(cmd *cobra.Command, key string) string {
+	if _, ok := changedConfigFlag(cmd, key); ok {
+		return "flag"
+	}
+	if _, ok := os.LookupEnv(configEnvVar(key)); ok {
+		return "env (" + configEnvVar(key) + ")"
+	}
+	if viper.InConfig(key) {
+		return "file"
+	}
+	if viper.IsSet(key) {
+		return "default"
+	}
+	return "unset"
+}
+
 func runConfigReset(cmd *cobra.Command, args []string) {
 	if len(args) == 0 {
 		// Reset all
@@ -203,12 +315,8 @@ func runConfigReset(cmd *cobra.Command, args []string) {
 			return
 		}
 
-		// Clear all settings
-		for key := range viper.AllSettings() {
-			viper.Set(key, nil)
-		}
-
-		if err := writeConfig(); err != nil {
+		// An empty file leaves every key at its default
+		if err := writeConfigFrom(viper.New()); err != nil {
 			color.Red("Error writing config: %v", err)
 			os.Exit(1)
 		}
@@ -217,9 +325,13 @@ func runConfigReset(cmd *cobra.Command, args []string) {
 	} else {
 		// Reset specific key
 		key := args[0]
-		viper.Set(key, nil)
+		settings, err := fileConfig(key)
+		if err != nil {
+			color.Red("Error reading config: %v", err)
+			os.Exit(1)
+		}
 
-		if err := writeConfig(); err != nil {
+		if err := writeConfigFrom(settings); err != nil {
 			color.Red("Error writing config: %v", err)
 			os.Exit(1)
 		}
@@ -240,7 +352,7 @@ func runConfigEdit(cmd *cobra.Command, args []string) {
 
 	// Create config file if it doesn't exist
 	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		if err := writeConfig(); err != nil {
+		if err := writeConfigFrom(viper.New()); err != nil {
 			color.Red("Error creating config file: %v", err)
 			os.Exit(1)
 		}
@@ -259,20 +371,174 @@ func runConfigEdit(cmd *cobra.Command, args []string) {
 	fmt.Printf("Config file: %s\n", configFile)
 }
 
+func runConfigExport(cmd *cobra.Command, args []string) {
+	out, err := exportConfig()
+	if err != nil {
+		color.Red("Error encoding config: %v", err)
+		os.Exit(1)
+	}
+	fmt.Print(string(out))
+}
+
+// exportConfig encodes the effective value of every set configuration key as
+// nested YAML
+func exportConfig() ([]byte, error) {
+	settings := make(map[string]interface{})
+	for _, key := range sortedConfigKeys() {
+		value := viper.Get(key)
+		if value == nil {
+			continue
+		}
+
+		// Nest "watch.interval" as watch: {interval: ...}
+		parts := strings.Split(key, ".")
+		section := settings
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := section[part].(map[string]interface{})
+			if !ok {
+				child = make(map[string]interface{})
+				section[part] = child
+			}
+			section = child
+		}
+		section[parts[len(parts)-1]] = value
+	}
+
+	return yaml.Marshal(settings)
+}
+
+func runConfigImport(cmd *cobra.Command, args []string) {
+	var data []byte
+	var err error
+	if args[0] == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(args[0])
+	}
+	if err != nil {
+		color.Red("Error reading %s: %v", args[0], err)
+		os.Exit(1)
+	}
+
+	values, err := parseConfigImport(data)
+	if err != nil {
+		color.Red("Error importing %s: %v", args[0], err)
+		os.Exit(1)
+	}
+
+	// Merging starts from the keys already in the file, never the defaults or
+	// environment; a fresh instance holds only the imported keys, so with
+	// --replace the rest revert to their defaults
+	target := viper.New()
+	if !configImportReplace {
+		if target, err = fileConfig(); err != nil {
+			color.Red("Error reading config: %v", err)
+			os.Exit(1)
+		}
+	}
+	for _, key := range sortedKeys(values) {
+		target.Set(key, values[key])
+	}
+
+	if err := writeConfigFrom(target); err != nil {
+		color.Red("Error writing config: %v", err)
+		os.Exit(1)
+	}
+
+	mode := "Merged"
+	if configImportReplace {
+		mode = "Replaced configuration with"
+	}
+	color.Green("✅ %s %d setting(s) from %s", mode, len(values), args[0])
+}
+
+// parseConfigImport flattens a YAML document into dotted keys and validates
+// every key and value, returning the values as strings ready for viper.Set.
+// All problems are reported together.
+func parseConfigImport(data []byte) (map[string]string, error) {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %v", err)
+	}
+
+	flat := make(map[string]interface{})
+	flattenConfig("", doc, flat)
+
+	values := make(map[string]string, len(flat))
+	var problems []string
+	for _, key := range sortedKeys(flat) {
+		valueType, ok := validKeys[key]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown key %s", key))
+			continue
+		}
+		value := fmt.Sprint(flat[key])
+		if err := validateValue(value, valueType, key); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", key, err))
+			continue
+		}
+		values[key] = value
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return values, nil
+}
+
+// flattenConfig copies nested maps into flat using dotted keys
+func flattenConfig(prefix string, nested map[string]interface{}, flat map[string]interface{}) {
+	for key, value := range nested {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if child, ok := value.(map[string]interface{}); ok {
+			flattenConfig(key, child, flat)
+			continue
+		}
+		flat[strings.ToLower(key)] = value
+	}
+}
+
+// sortedConfigKeys returns every supported configuration key in order
+func sortedConfigKeys() []string {
+	return sortedKeys(validKeys)
+}
+
+// sortedKeys returns the keys of m in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func validateValue(value, valueType, key string) error {
 	switch valueType {
 	case "bool":
-		if value != "true" && value != "false" {
+		if _, err := strconv.ParseBool(value); err != nil {
 			return fmt.Errorf("must be 'true' or 'false'")
 		}
 	case "int":
 		if _, err := strconv.Atoi(value); err != nil {
 			return fmt.Errorf("must be a number")
 		}
+	case "float":
+		if f, err := strconv.ParseFloat(value, 64); err != nil || f < 0 {
+			return fmt.Errorf("must be a non-negative number")
+		}
+	case "duration0":
+		if d, err := time.ParseDuration(strings.TrimSpace(value)); err == nil && d == 0 {
+			return nil
+		}
+		if _, err := process.ParseDuration(value); err != nil {
+			return err
+		}
 	case "duration":
-		// Simple duration validation
-		if !strings.HasSuffix(value, "s") && !strings.HasSuffix(value, "m") && !strings.HasSuffix(value, "ms") {
-			return fmt.Errorf("must be a duration (e.g., '2s', '500ms', '1m')")
+		if _, err := process.ParseDuration(value); err != nil {
+			return err
 		}
 	case "string":
 		// Additional validation for specific string keys
@@ -285,20 +551,179 @@ func validateValue(value, valueType, key string) error {
 			}
 			return fmt.Errorf("must be one of: %v", valid)
 		}
+		if key == "dev.ports" {
+			if _, err := process.ParsePortRange(value); err != nil {
+				return fmt.Errorf("must be a port range (e.g., '3000-8999'): %v", err)
+			}
+			return nil
+		}
+		if key == "service.definitions" {
+			if _, err := process.LoadServiceDefinitions(expandHome(value)); err != nil {
+				return err
+			}
+			return nil
+		}
 		if key == "list.sort" {
-			valid := []string{"port", "pid", "cpu", "memory", "command", "service", "user"}
-			for _, v := range valid {
-				if value == v {
-					return nil
-				}
+			if _, err := process.ParseSortKeys(value, ""); err != nil {
+				return err
 			}
-			return fmt.Errorf("must be one of: %v", valid)
+			return nil
+		}
+	}
+	return nil
+}
+
+// defaultDevPorts is the development port range used when dev.ports is unset
+var defaultDevPorts = process.PortRange{Start: 3000, End: 9999}
+
+// devPortRange returns the configured dev.ports range, falling back to the
+// default with a warning if the stored value is malformed
+func devPortRange() process.PortRange {
+	spec := viper.GetString("dev.ports")
+	if spec == "" {
+		return defaultDevPorts
+	}
+	r, err := process.ParsePortRange(spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring dev.ports %q: %v\n", spec, err)
+		return defaultDevPorts
+	}
+	return r
+}
+
+// Default display.cpu-warn and display.mem-warn thresholds
+const (
+	defaultCPUWarn   = 50.0  // percent
+	defaultMemWarnMB = 512.0 // megabytes
+)
+
+// usageThresholds returns the display.cpu-warn and display.mem-warn levels
+// used to color CPU and memory cells
+func usageThresholds() (cpuWarn, memWarnMB float64) {
+	return viper.GetFloat64("display.cpu-warn"), viper.GetFloat64("display.mem-warn")
+}
+
+// newServerProcessManager returns the process manager shared by the requests
+// of a long-running server, configured from --cache-ttl and server.metrics
+func newServerProcessManager() *process.ProcessManager {
+	return newProcessManager().WithCache(serverCacheTTL).WithMetrics(viper.GetBool("server.metrics"))
+}
+
+// configReloadDelay lets a burst of writes to the config file settle (editors
+// often truncate and then write) before it is read again
+const configReloadDelay = 200 * time.Millisecond
+
+// watchServerConfig reloads the config file whenever it is saved and applies
+// server.cache-ttl (unless --cache-ttl was given) and server.metrics to pm,
+// which is safe to reconfigure while serving. Other settings keep their
+// startup values. A file that does not validate is reported and ignored, so
+// the previous settings stay in effect. Messages go to stderr, since the MCP
+// server speaks on stdout.
+func watchServerConfig(cmd *cobra.Command, pm *process.ProcessManager) error {
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		return fmt.Errorf("no config file to watch; create one with \"portctl config set\" or name one with --config")
+	}
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("cannot watch %s: %v", path, err)
+	}
+
+	ttlFromFlag := cmd.Flags().Changed("cache-ttl")
+	var mu sync.Mutex
+	reload := func() {
+		mu.Lock()
+		defer mu.Unlock()
+
+		// The file is parsed here rather than read back through viper, whose
+		// watcher may be updating it concurrently
+		data, err := os.ReadFile(path)
+		var values map[string]string
+		if err == nil {
+			values, err = parseConfigImport(data)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, color.YellowString("⚠️  Ignoring change to %s: %v", path, err))
+			return
 		}
+
+		if !ttlFromFlag {
+			ttl, _ := time.ParseDuration(strings.TrimSpace(serverSetting(values, "server.cache-ttl", defaultServerCacheTTL.String())))
+			pm.WithCache(ttl)
+		}
+		metrics, _ := strconv.ParseBool(serverSetting(values, "server.metrics", "true"))
+		pm.WithMetrics(metrics)
+		fmt.Fprintln(os.Stderr, color.CyanString("🔄 Reloaded %s: %s", path, describeServerSettings(pm)))
 	}
+
+	timer := time.AfterFunc(time.Hour, reload)
+	timer.Stop()
+	viper.OnConfigChange(func(fsnotify.Event) {
+		timer.Reset(configReloadDelay)
+	})
+	viper.WatchConfig()
 	return nil
 }
 
+// serverSetting returns the value of key from its environment variable, the
+// reloaded file values, or def, in that order of precedence. Environment
+// values were validated at startup.
+func serverSetting(values map[string]string, key, def string) string {
+	if value, ok := os.LookupEnv(configEnvVar(key)); ok {
+		return value
+	}
+	if value, ok := values[key]; ok {
+		return value
+	}
+	return def
+}
+
+// describeServerSettings summarizes the reloadable settings of pm
+func describeServerSettings(pm *process.ProcessManager) string {
+	cache := "cache off"
+	if ttl := pm.CacheTTL(); ttl > 0 {
+		cache = "cache TTL " + ttl.String()
+	}
+	metrics := "metrics on"
+	if !pm.MetricsEnabled() {
+		metrics = "metrics off"
+	}
+	return cache + ", " + metrics
+}
+
+// expandHome replaces a leading "~" in path with the user's home directory
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(homeDir, path[1:])
+}
+
+// loadServiceDefinitions installs the custom service definitions named by the
+// service.definitions config key. A broken file is reported but not fatal.
+func loadServiceDefinitions() {
+	path := viper.GetString("service.definitions")
+	if path == "" {
+		return
+	}
+
+	defs, err := process.LoadServiceDefinitions(expandHome(path))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, color.YellowString("⚠️  Ignoring service definitions %s: %v", path, err))
+		return
+	}
+	process.SetServiceDefinitions(defs)
+}
+
+// getConfigFile returns the file config changes are written to: the --config
+// path if given, otherwise ~/.config/portctl/config.yaml
 func getConfigFile() string {
+	if configPath != "" {
+		return expandHome(configPath)
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "./portctl-config.yaml"
@@ -306,7 +731,36 @@ func getConfigFile() string {
 	return filepath.Join(homeDir, ".config", "portctl", "config.yaml")
 }
 
-func writeConfig() error {
+// fileConfig returns a viper instance holding only the keys stored in the
+// config file, without defaults or environment overrides, so that writing it
+// back persists just the explicitly set values. Keys in drop are left out.
+func fileConfig(drop ...string) (*viper.Viper, error) {
+	v := viper.New()
+	configFile := getConfigFile()
+	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return v, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML in %s: %v", configFile, err)
+	}
+	flat := make(map[string]interface{})
+	flattenConfig("", doc, flat)
+	for key, value := range flat {
+		if !slices.Contains(drop, key) {
+			v.Set(key, value)
+		}
+	}
+	return v, nil
+}
+
+// writeConfigFrom saves the settings held by v to the config file
+func writeConfigFrom(v *viper.Viper) error {
 	configFile := getConfigFile()
 	configDir := filepath.Dir(configFile)
 
@@ -315,7 +769,7 @@ func writeConfig() error {
 		return err
 	}
 
-	return viper.WriteConfigAs(configFile)
+	return v.WriteConfigAs(configFile)
 }
 
 func init() {
@@ -325,29 +779,141 @@ func init() {
 	configCmd.AddCommand(configListCmd)
 	configCmd.AddCommand(configResetCmd)
 	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configExportCmd)
+	configCmd.AddCommand(configImportCmd)
+
+	configImportCmd.Flags().BoolVar(&configImportReplace, "replace", false,
+		"Replace the whole configuration instead of merging into it")
+}
+
+// configEnvPrefix prefixes the environment variables that override config keys
+const configEnvPrefix = "PORTCTL"
+
+// configEnvReplacer maps a config key to its environment variable suffix
+var configEnvReplacer = strings.NewReplacer(".", "_", "-", "_")
+
+// configEnvVar returns the environment variable that overrides key, e.g.
+// PORTCTL_SCAN_CONCURRENT for scan.concurrent
+func configEnvVar(key string) string {
+	return configEnvPrefix + "_" + strings.ToUpper(configEnvReplacer.Replace(key))
+}
+
+// configFlagBinding ties a config key to the command flag it provides the
+// default for
+type configFlagBinding struct {
+	cmd  *cobra.Command
+	flag string
+	key  string
+}
+
+// configFlagBindings lists the flags whose defaults come from configuration
+var configFlagBindings = []configFlagBinding{
+	{scanCmd, "timeout", "scan.timeout"},
+	{scanCmd, "concurrent", "scan.concurrent"},
+	{scanCmd, "cache-ttl", "scan.cache-ttl"},
+	{watchCmd, "interval", "watch.interval"},
+	{watchCmd, "notify", "watch.notifications"},
+	{listCmd, "sort", "list.sort"},
+}
+
+// appliesTo reports whether the binding's flag is one of cmd's: a flag of cmd
+// itself, or a persistent flag of the root command, which every command inherits
+func (b configFlagBinding) appliesTo(cmd *cobra.Command) bool {
+	return b.cmd == cmd || b.cmd == cmd.Root()
+}
+
+// changedConfigFlag returns the value of the flag of cmd bound to key, and
+// whether that flag was given on the command line
+func changedConfigFlag(cmd *cobra.Command, key string) (string, bool) {
+	for _, b := range configFlagBindings {
+		if b.key != key || !b.appliesTo(cmd) {
+			continue
+		}
+		if f := cmd.Flags().Lookup(b.flag); f != nil && f.Changed {
+			return f.Value.String(), true
+		}
+	}
+	return "", false
+}
+
+// applyConfigToFlags fills in the config-backed flags of cmd that were not
+// given on the command line, so a flag beats env, file and default values
+func applyConfigToFlags(cmd *cobra.Command) error {
+	for _, b := range configFlagBindings {
+		if !b.appliesTo(cmd) {
+			continue
+		}
+		f := cmd.Flags().Lookup(b.flag)
+		if f == nil || f.Changed {
+			continue
+		}
+		value := viper.GetString(b.key)
+		if err := f.Value.Set(value); err != nil {
+			return fmt.Errorf("invalid %s value %q: %v", b.key, value, err)
+		}
+	}
+	return nil
+}
+
+// initConfig points viper at the config file and loads it. It runs from the
+// root command's PersistentPreRunE, after flag parsing, so flags such as
+// --config can influence loading. A missing file is fine and leaves the
+// defaults in place; a broken default-location file is reported but not fatal,
+// so "config reset" and "config edit" can still repair it. A broken file named
+// with --config is an error.
+func initConfig() error {
+	setConfigDefaults()
+
+	viper.SetEnvPrefix(configEnvPrefix)
+	viper.SetEnvKeyReplacer(configEnvReplacer)
+	viper.AutomaticEnv()
+	for _, key := range sortedConfigKeys() {
+		if value, ok := os.LookupEnv(configEnvVar(key)); ok {
+			if err := validateValue(value, validKeys[key], key); err != nil {
+				return fmt.Errorf("invalid %s: %v", configEnvVar(key), err)
+			}
+		}
+	}
+
+	if configPath != "" {
+		viper.SetConfigFile(expandHome(configPath))
+	} else {
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
+		viper.AddConfigPath("$HOME/.config/portctl")
+		viper.AddConfigPath(".")
+	}
 
-	// Initialize viper
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath("$HOME/.config/portctl")
-	viper.AddConfigPath(".")
+	if err := viper.ReadInConfig(); err != nil {
+		// An explicit --config file may not exist yet; "config set" creates it
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok || os.IsNotExist(err) {
+			return nil
+		}
+		if configPath != "" {
+			return fmt.Errorf("reading config %s: %v", configPath, err)
+		}
+		color.Red("Error reading config: %v", err)
+	}
+	return nil
+}
 
-	// Set defaults
+// setConfigDefaults registers the default value of every config key
+func setConfigDefaults() {
 	viper.SetDefault("watch.interval", "3s")
 	viper.SetDefault("watch.notifications", false)
 	viper.SetDefault("output.format", "table")
 	viper.SetDefault("output.colors", true)
 	viper.SetDefault("scan.timeout", "3s")
 	viper.SetDefault("scan.concurrent", 50)
+	viper.SetDefault("scan.cache-ttl", "0s")
 	viper.SetDefault("kill.confirm", true)
+	viper.SetDefault("kill.max-batch", 10)
 	viper.SetDefault("list.sort", "port")
-	viper.SetDefault("dev.ports", "3000-9999")
-
-	// Try to read config file
-	if err := viper.ReadInConfig(); err != nil {
-		// Config file not found is okay, we'll use defaults
-		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-			color.Red("Error reading config: %v", err)
-		}
-	}
+	viper.SetDefault("dev.ports", defaultDevPorts.String())
+	viper.SetDefault("display.cpu-warn", defaultCPUWarn)
+	viper.SetDefault("display.mem-warn", defaultMemWarnMB)
+	viper.SetDefault("server.cache-ttl", defaultServerCacheTTL.String())
+	viper.SetDefault("server.metrics", true)
+	viper.SetDefault("server.request-timeout", defaultRequestTimeout.String())
+	viper.SetDefault("enum.timeout", process.DefaultEnumerationTimeout.String())
 }
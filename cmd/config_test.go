@@ -0,0 +1,469 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+// withConfigPath runs initConfig against path with a fresh viper instance
+func withConfigPath(t *testing.T, path string) error {
+	t.Helper()
+	viper.Reset()
+	configPath = path
+	t.Cleanup(func() {
+		configPath = ""
+		viper.Reset()
+	})
+	return initConfig()
+}
+
+func TestInitConfigMissingFileUsesDefaults(t *testing.T) {
+	if err := withConfigPath(t, filepath.Join(t.TempDir(), "missing.yaml")); err != nil {
+		t.Fatalf("initConfig with a missing file: %v", err)
+	}
+
+	tests := map[string]string{
+		"watch.interval":  "3s",
+		"scan.timeout":    "3s",
+		"scan.concurrent": "50",
+		"kill.confirm":    "true",
+		"kill.max-batch":  "10",
+		"output.format":   "table",
+		"list.sort":       "port",
+		"dev.ports":       "3000-9999",
+	}
+	for key, want := range tests {
+		if got := viper.GetString(key); got != want {
+			t.Errorf("%s = %q, want default %q", key, got, want)
+		}
+	}
+	for key := range validKeys {
+		if key != "service.definitions" && !viper.IsSet(key) {
+			t.Errorf("no default registered for %s", key)
+		}
+	}
+}
+
+func TestInitConfigReadsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "portctl.yaml")
+	if err := os.WriteFile(path, []byte("scan:\n  concurrent: 7\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := withConfigPath(t, path); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+	if got := viper.GetInt("scan.concurrent"); got != 7 {
+		t.Errorf("scan.concurrent = %d, want 7 from the file", got)
+	}
+	if got := viper.GetString("watch.interval"); got != "3s" {
+		t.Errorf("watch.interval = %q, want the default for keys the file omits", got)
+	}
+}
+
+func TestInitConfigRejectsBrokenExplicitFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.yaml")
+	if err := os.WriteFile(path, []byte("scan: [unclosed\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := withConfigPath(t, path); err == nil {
+		t.Error("Expected an error for a malformed --config file")
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "portctl.yaml")
+	if err := os.WriteFile(path, []byte("scan:\n  concurrent: 7\n  timeout: 2s\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORTCTL_SCAN_CONCURRENT", "200")
+
+	if err := withConfigPath(t, path); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+
+	flag := scanCmd.Flags().Lookup("concurrent")
+	savedConcurrent, savedTimeout := scanConcurrent, scanTimeout
+	t.Cleanup(func() {
+		scanConcurrent, scanTimeout = savedConcurrent, savedTimeout
+		flag.Changed = false
+	})
+
+	// Env beats the file, the file beats the default
+	if err := applyConfigToFlags(scanCmd); err != nil {
+		t.Fatalf("applyConfigToFlags: %v", err)
+	}
+	if scanConcurrent != 200 {
+		t.Errorf("--concurrent = %d, want 200 from the environment", scanConcurrent)
+	}
+	if scanTimeout.String() != "2s" {
+		t.Errorf("--timeout = %v, want 2s from the file", scanTimeout)
+	}
+
+	// An explicit flag beats everything
+	if err := scanCmd.Flags().Set("concurrent", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := applyConfigToFlags(scanCmd); err != nil {
+		t.Fatalf("applyConfigToFlags: %v", err)
+	}
+	if scanConcurrent != 5 {
+		t.Errorf("--concurrent = %d, want 5 from the command line", scanConcurrent)
+	}
+}
+
+func TestConfigEnvVar(t *testing.T) {
+	tests := map[string]string{
+		"scan.concurrent": "PORTCTL_SCAN_CONCURRENT",
+		"kill.max-batch":  "PORTCTL_KILL_MAX_BATCH",
+	}
+	for key, want := range tests {
+		if got := configEnvVar(key); got != want {
+			t.Errorf("configEnvVar(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestConfigSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "portctl.yaml")
+	if err := os.WriteFile(path, []byte("scan:\n  timeout: 1s\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORTCTL_KILL_MAX_BATCH", "3")
+
+	if err := withConfigPath(t, path); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+
+	tests := map[string]string{
+		"scan.timeout":        "file",
+		"kill.max-batch":      "env (PORTCTL_KILL_MAX_BATCH)",
+		"watch.interval":      "default",
+		"service.definitions": "unset",
+	}
+	for key, want := range tests {
+		if got := configSource(configListCmd, key); got != want {
+			t.Errorf("configSource(%s) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestConfigSourceFlag(t *testing.T) {
+	if err := withConfigPath(t, filepath.Join(t.TempDir(), "missing.yaml")); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+	savedEnumTimeout, savedScanTimeout := enumTimeout, scanTimeout
+	t.Cleanup(func() {
+		enumTimeout, scanTimeout = savedEnumTimeout, savedScanTimeout
+		rootCmd.PersistentFlags().Lookup("enum-timeout").Changed = false
+		scanCmd.Flags().Lookup("timeout").Changed = false
+	})
+
+	// A global flag is reported on any command, including config list itself
+	if err := configListCmd.ParseFlags([]string{"--enum-timeout", "30s"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := configSource(configListCmd, "enum.timeout"); got != "flag" {
+		t.Errorf("configSource(enum.timeout) with --enum-timeout = %q, want flag", got)
+	}
+	if value, ok := changedConfigFlag(configListCmd, "enum.timeout"); !ok || value != "30s" {
+		t.Errorf("changedConfigFlag(enum.timeout) = %q, %v, want 30s from the flag", value, ok)
+	}
+
+	// A command's own flag counts only for that command
+	if err := scanCmd.Flags().Set("timeout", "5s"); err != nil {
+		t.Fatal(err)
+	}
+	if got := configSource(scanCmd, "scan.timeout"); got != "flag" {
+		t.Errorf("configSource(scan, scan.timeout) with --timeout = %q, want flag", got)
+	}
+	if got := configSource(configListCmd, "scan.timeout"); got != "default" {
+		t.Errorf("configSource(config list, scan.timeout) = %q, want default", got)
+	}
+}
+
+func TestConfigSetThenList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "portctl.yaml")
+	if err := withConfigPath(t, path); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+
+	runConfigSet(configSetCmd, []string{"scan.timeout", "5s"})
+	runConfigSet(configSetCmd, []string{"watch.interval", "1s"})
+
+	// The file holds only the keys that were set, not every default
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("config file not written: %v", err)
+	}
+	values, err := parseConfigImport(data)
+	if err != nil {
+		t.Fatalf("config file does not parse: %v", err)
+	}
+	want := map[string]string{"scan.timeout": "5s", "watch.interval": "1s"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("config file holds %v, want %v", values, want)
+	}
+
+	if err := withConfigPath(t, path); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+	sources := map[string]string{
+		"scan.timeout":    "file",
+		"watch.interval":  "file",
+		"scan.concurrent": "default",
+		"kill.max-batch":  "default",
+	}
+	for key, want := range sources {
+		if got := configSource(configListCmd, key); got != want {
+			t.Errorf("after config set, configSource(%s) = %q, want %q", key, got, want)
+		}
+	}
+	if got := viper.GetString("scan.timeout"); got != "5s" {
+		t.Errorf("scan.timeout = %q, want 5s", got)
+	}
+
+	runConfigReset(configResetCmd, []string{"scan.timeout"})
+	if err := withConfigPath(t, path); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+	if got := configSource(configListCmd, "scan.timeout"); got != "default" {
+		t.Errorf("after config reset, configSource(scan.timeout) = %q, want default", got)
+	}
+	if got := configSource(configListCmd, "watch.interval"); got != "file" {
+		t.Errorf("config reset of one key dropped watch.interval: source %q", got)
+	}
+}
+
+func TestServerCacheTTLAllowsZero(t *testing.T) {
+	for _, key := range []string{"scan.cache-ttl", "server.cache-ttl", "server.request-timeout"} {
+		if err := validateValue("0s", validKeys[key], key); err != nil {
+			t.Errorf("%s 0s rejected: %v", key, err)
+		}
+	}
+	if err := validateValue("0s", validKeys["scan.timeout"], "scan.timeout"); err == nil {
+		t.Error("scan.timeout 0s accepted, want an error")
+	}
+	if err := validateValue("-1s", validKeys["server.cache-ttl"], "server.cache-ttl"); err == nil {
+		t.Error("server.cache-ttl -1s accepted, want an error")
+	}
+}
+
+func TestValidateBoolAcceptsParseBoolForms(t *testing.T) {
+	for _, value := range []string{"true", "false", "1", "0", "TRUE", "False"} {
+		if err := validateValue(value, "bool", "server.metrics"); err != nil {
+			t.Errorf("server.metrics %q rejected: %v", value, err)
+		}
+	}
+	for _, value := range []string{"yes", "on", ""} {
+		if err := validateValue(value, "bool", "server.metrics"); err == nil {
+			t.Errorf("server.metrics %q accepted, want an error", value)
+		}
+	}
+}
+
+func TestServerSettingPrecedence(t *testing.T) {
+	values := map[string]string{"server.cache-ttl": "5s"}
+	if got := serverSetting(values, "server.cache-ttl", "2s"); got != "5s" {
+		t.Errorf("file value: got %q, want 5s", got)
+	}
+	if got := serverSetting(values, "server.metrics", "true"); got != "true" {
+		t.Errorf("default: got %q, want true", got)
+	}
+	t.Setenv("PORTCTL_SERVER_CACHE_TTL", "9s")
+	if got := serverSetting(values, "server.cache-ttl", "2s"); got != "9s" {
+		t.Errorf("env value: got %q, want 9s", got)
+	}
+}
+
+func TestParseConfigImport(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "nested",
+			yaml: "watch:\n  interval: 2s\nscan:\n  concurrent: 20\n",
+			want: map[string]string{"watch.interval": "2s", "scan.concurrent": "20"},
+		},
+		{
+			name: "dotted",
+			yaml: "kill.max-batch: 4\noutput.format: json\n",
+			want: map[string]string{"kill.max-batch": "4", "output.format": "json"},
+		},
+		{
+			name: "mixed case keys",
+			yaml: "Server:\n  Metrics: false\n",
+			want: map[string]string{"server.metrics": "false"},
+		},
+		{name: "empty", yaml: "", want: map[string]string{}},
+		{name: "unknown key", yaml: "watch:\n  color: red\n", wantErr: "unknown key watch.color"},
+		{name: "bad value", yaml: "scan:\n  concurrent: many\n", wantErr: "scan.concurrent: must be a number"},
+		{name: "invalid YAML", yaml: "scan: [unclosed\n", wantErr: "invalid YAML"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseConfigImport([]byte(tt.yaml))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("parseConfigImport() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseConfigImport() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConfigImport() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseConfigImportReportsAllProblems(t *testing.T) {
+	_, err := parseConfigImport([]byte("bogus: 1\nscan:\n  timeout: soon\n"))
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, want := range []string{"unknown key bogus", "scan.timeout"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestFlattenConfig(t *testing.T) {
+	nested := map[string]interface{}{
+		"watch": map[string]interface{}{
+			"interval":      "2s",
+			"Notifications": true,
+		},
+		"kill.max-batch": 3,
+		"a": map[string]interface{}{
+			"b": map[string]interface{}{"c": "deep"},
+		},
+	}
+	flat := make(map[string]interface{})
+	flattenConfig("", nested, flat)
+
+	want := map[string]interface{}{
+		"watch.interval":      "2s",
+		"watch.notifications": true,
+		"kill.max-batch":      3,
+		"a.b.c":               "deep",
+	}
+	if !reflect.DeepEqual(flat, want) {
+		t.Errorf("flattenConfig() = %v, want %v", flat, want)
+	}
+}
+
+func TestConfigExportImportRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "source.yaml")
+	if err := os.WriteFile(source, []byte("scan:\n  concurrent: 7\nlist.sort: cpu,port\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := withConfigPath(t, source); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+	exported, err := exportConfig()
+	if err != nil {
+		t.Fatalf("exportConfig: %v", err)
+	}
+	want := make(map[string]string)
+	for _, key := range sortedConfigKeys() {
+		if viper.IsSet(key) {
+			want[key] = viper.GetString(key)
+		}
+	}
+
+	values, err := parseConfigImport(exported)
+	if err != nil {
+		t.Fatalf("exported config does not import: %v\n%s", err, exported)
+	}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("round trip = %v, want %v", values, want)
+	}
+
+	exportFile := filepath.Join(dir, "export.yaml")
+	if err := os.WriteFile(exportFile, exported, 0600); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(dir, "target.yaml")
+	if err := withConfigPath(t, target); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+	runConfigImport(configImportCmd, []string{exportFile})
+
+	if err := withConfigPath(t, target); err != nil {
+		t.Fatalf("initConfig after import: %v", err)
+	}
+	for key, value := range want {
+		if got := viper.GetString(key); got != value {
+			t.Errorf("after import %s = %q, want %q", key, got, value)
+		}
+	}
+}
+
+func TestConfigImportMergeKeepsFileKeysOnly(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "portctl.yaml")
+	if err := os.WriteFile(path, []byte("scan:\n  timeout: 1s\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	imported := filepath.Join(dir, "import.yaml")
+	if err := os.WriteFile(imported, []byte("watch:\n  interval: 5s\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PORTCTL_KILL_MAX_BATCH", "3")
+	if err := withConfigPath(t, path); err != nil {
+		t.Fatalf("initConfig: %v", err)
+	}
+
+	runConfigImport(configImportCmd, []string{imported})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values, err := parseConfigImport(data)
+	if err != nil {
+		t.Fatalf("merged config does not parse: %v", err)
+	}
+	want := map[string]string{"scan.timeout": "1s", "watch.interval": "5s"}
+	if !reflect.DeepEqual(values, want) {
+		t.Errorf("merged config holds %v, want %v without defaults or env values", values, want)
+	}
+}
+
+func TestConfigImportRejectsInvalidKeyWithoutWriting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "portctl.yaml")
+	original := []byte("scan:\n  timeout: 1s\n")
+	if err := os.WriteFile(path, original, 0600); err != nil {
+		t.Fatal(err)
+	}
+	imported := filepath.Join(dir, "import.yaml")
+	if err := os.WriteFile(imported, []byte("watch:\n  interval: 5s\n  bogus: true\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := runPortctl(t, "--config", path, "config", "import", imported); got != exitCodeError {
+		t.Errorf("config import with an unknown key exited %d, want %d", got, exitCodeError)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(original) {
+		t.Errorf("config import with an unknown key changed the file to %q", data)
+	}
+}
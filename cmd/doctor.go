@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	process "dagger/portctl/pkg"
+)
+
+var doctorJSON bool
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Diagnose the portctl environment and configuration",
+	Long: `Check that portctl can work correctly on this machine.
+
+The doctor command reports:
+  • Platform and architecture
+  • Availability of the system tools used for enumeration (lsof, netstat, tasklist)
+  • Where the config file is loaded from and whether it is valid
+  • The current effective settings
+  • Permission caveats that may hide processes from the listing
+
+Examples:
+  portctl doctor          # Human-readable report
+  portctl doctor --json   # Machine-readable report`,
+	Run: runDoctor,
+}
+
+// doctorTool describes an external tool portctl relies on
+type doctorTool struct {
+	Name      string `json:"name"`
+	Available bool   `json:"available"`
+	Path      string `json:"path,omitempty"`
+}
+
+// doctorReport is the result of the environment diagnosis
+type doctorReport struct {
+	Platform     string                 `json:"platform"`
+	Arch         string                 `json:"arch"`
+	Tools        []doctorTool           `json:"tools"`
+	ConfigFile   string                 `json:"config_file,omitempty"`
+	ConfigValid  bool                   `json:"config_valid"`
+	ConfigErrors []string               `json:"config_errors,omitempty"`
+	Settings     map[string]interface{} `json:"settings"`
+	Privileged   bool                   `json:"privileged"`
+	Warnings     []string               `json:"warnings"`
+}
+
+func runDoctor(cmd *cobra.Command, args []string) {
+	report := diagnose()
+
+	if doctorJSON {
+		writeJSON(report)
+		return
+	}
+
+	color.Cyan("🩺 portctl Doctor")
+	fmt.Println()
+
+	fmt.Printf("Platform:      %s/%s\n", report.Platform, report.Arch)
+	fmt.Printf("Privileged:    %t\n", report.Privileged)
+	fmt.Println()
+
+	color.Cyan("🔧 Tools:")
+	for _, tool := range report.Tools {
+		if tool.Available {
+			color.Green("  ✅ %-10s %s", tool.Name, tool.Path)
+		} else {
+			color.Red("  ❌ %-10s not found", tool.Name)
+		}
+	}
+	fmt.Println()
+
+	color.Cyan("📁 Configuration:")
+	if report.ConfigFile != "" {
+		fmt.Printf("  File:        %s\n", report.ConfigFile)
+	} else {
+		fmt.Printf("  File:        none (using defaults)\n")
+	}
+	if report.ConfigValid {
+		color.Green("  ✅ Configuration is valid")
+	} else {
+		for _, e := range report.ConfigErrors {
+			color.Red("  ❌ %s", e)
+		}
+	}
+
+	keys := make([]string, 0, len(report.Settings))
+	for key := range report.Settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Printf("  %-22s %v\n", key, report.Settings[key])
+	}
+	fmt.Println()
+
+	if len(report.Warnings) == 0 {
+		color.Green("🎉 No problems found")
+		return
+	}
+
+	color.Yellow("⚠️  Warnings:")
+	for _, w := range report.Warnings {
+		color.Yellow("  • %s", w)
+	}
+}
+
+// diagnose gathers the environment, tool, config and permission checks
+func diagnose() doctorReport {
+	report := doctorReport{
+		Platform:    runtime.GOOS,
+		Arch:        runtime.GOARCH,
+		ConfigValid: true,
+		Settings:    make(map[string]interface{}),
+		Warnings:    []string{},
+	}
+
+	// Tools
+	toolNames := []string{"lsof", "netstat"}
+	if runtime.GOOS == "windows" {
+		toolNames = []string{"netstat", "tasklist"}
+	}
+	available := 0
+	for _, name := range toolNames {
+		tool := doctorTool{Name: name}
+		if path, err := exec.LookPath(name); err == nil {
+			tool.Available = true
+			tool.Path = path
+			available++
+		}
+		report.Tools = append(report.Tools, tool)
+	}
+	if available == 0 {
+		if runtime.GOOS == "linux" {
+			report.Warnings = append(report.Warnings,
+				"neither lsof nor netstat is installed; falling back to reading /proc directly")
+		} else {
+			report.Warnings = append(report.Warnings,
+				"no enumeration tools found; listing processes will fail")
+		}
+	}
+
+	// Configuration file
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok && !os.IsNotExist(err) {
+			report.ConfigValid = false
+			report.ConfigErrors = append(report.ConfigErrors, fmt.Sprintf("cannot read config: %v", err))
+		}
+	}
+	report.ConfigFile = viper.ConfigFileUsed()
+
+	// Effective settings, validated against the known keys
+	for _, key := range viper.AllKeys() {
+		value := viper.Get(key)
+		report.Settings[key] = value
+
+		valueType, known := validKeys[key]
+		if !known {
+			report.ConfigValid = false
+			report.ConfigErrors = append(report.ConfigErrors, fmt.Sprintf("unknown key: %s", key))
+			continue
+		}
+		if err := validateValue(fmt.Sprintf("%v", value), valueType, key); err != nil {
+			report.ConfigValid = false
+			report.ConfigErrors = append(report.ConfigErrors, fmt.Sprintf("invalid value for %s: %v", key, err))
+		}
+	}
+
+	// Permissions
+	if runtime.GOOS != "windows" {
+		report.Privileged = newProcessManager().PrivilegeLevel() == process.PrivilegeFull
+		if !report.Privileged {
+			report.Warnings = append(report.Warnings,
+				"running as non-root; processes owned by other users may be hidden (try sudo)")
+		}
+	} else {
+		report.Warnings = append(report.Warnings,
+			"run from an elevated prompt to see processes owned by other users")
+	}
+
+	return report
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().BoolVarP(&doctorJSON, "json", "j", false,
+		"Output the report in JSON format")
+}
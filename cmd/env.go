@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var (
+	envGrep        string
+	envShowSecrets bool
+	envJSON        bool
+)
+
+var envCmd = &cobra.Command{
+	Use:   "env <pid>",
+	Short: "Show the environment a process was started with",
+	Long: `Print the environment variables of a running process.
+
+Values of variables that look like credentials (names containing TOKEN,
+SECRET, PASSWORD, KEY, ... and passwords embedded in URLs) are masked unless
+--show-secrets is given. Reading another user's process usually requires root.
+The environment is read from /proc on Linux and is not available on every
+platform.
+
+Examples:
+  portctl env 12345                  # All variables, secrets masked
+  portctl env 12345 --grep database  # Only variables matching "database"
+  portctl env 12345 --json           # Machine-readable output`,
+	Args: cobra.ExactArgs(1),
+	Run:  runEnv,
+}
+
+func runEnv(cmd *cobra.Command, args []string) {
+	pid, err := strconv.Atoi(args[0])
+	if err != nil || pid <= 0 {
+		exitWithError(envJSON, exitCodeUsage, "Invalid PID: %s", args[0])
+	}
+
+	vars, err := newProcessManager().GetEnviron(cmd.Context(), pid)
+	if err != nil {
+		if errors.Is(err, process.ErrEnvironPermission) {
+			exitWithError(envJSON, exitCodeError, "%v", err)
+		}
+		exitWithError(envJSON, exitCodeError, "Error reading environment: %v", err)
+	}
+
+	if !envShowSecrets {
+		vars = process.MaskSecrets(vars)
+	}
+	vars = filterEnv(vars, envGrep)
+
+	if envJSON {
+		if vars == nil {
+			vars = []process.EnvVar{}
+		}
+		writeJSON(vars)
+		return
+	}
+
+	if len(vars) == 0 {
+		statusf(color.Yellow, "No matching environment variables for PID %d", pid)
+		return
+	}
+	for _, v := range vars {
+		fmt.Printf("%s=%s\n", v.Name, v.Value)
+	}
+}
+
+// filterEnv keeps variables whose name or value contains pattern, case-insensitively
+func filterEnv(vars []process.EnvVar, pattern string) []process.EnvVar {
+	if pattern == "" {
+		return vars
+	}
+
+	pattern = strings.ToLower(pattern)
+	var filtered []process.EnvVar
+	for _, v := range vars {
+		if strings.Contains(strings.ToLower(v.Name), pattern) || strings.Contains(strings.ToLower(v.Value), pattern) {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+
+	envCmd.Flags().StringVarP(&envGrep, "grep", "g", "",
+		"Show only variables whose name or value contains this text (case-insensitive)")
+	envCmd.Flags().BoolVar(&envShowSecrets, "show-secrets", false,
+		"Do not mask values that look like secrets")
+	envCmd.Flags().BoolVarP(&envJSON, "json", "j", false,
+		"Output in JSON format")
+}
@@ -2,23 +2,39 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	process "dagger/portctl/pkg"
 	pb "dagger/portctl/proto"
 )
 
+// defaultServerCacheTTL is how long the long-running servers reuse a process listing
+const defaultServerCacheTTL = 2 * time.Second
+
+// defaultRequestTimeout bounds a single gRPC call or MCP tool call
+const defaultRequestTimeout = 30 * time.Second
+
 var (
-	grpcPort string
+	grpcPort             string
+	grpcBind             string
+	grpcInterface        string
+	serverCacheTTL       time.Duration
+	serverWatchConfig    bool
+	serverRequestTimeout time.Duration
 )
 
 var grpcCmd = &cobra.Command{
@@ -26,37 +42,157 @@ var grpcCmd = &cobra.Command{
 	Short: "Start the gRPC API server",
 	Long: `Start a gRPC server to allow network-based access to portctl functionality.
 
-This command runs a gRPC server on localhost:57251 (by default) that exposes
+This command runs a gRPC server on port 57251 (by default) that exposes
 all portctl operations via a network API. Useful for automation, testing,
 and integration with other tools.
 
+The server listens on every interface unless --bind names one address or
+host name, or --interface names a network interface; with --interface it
+listens on each address of that interface.
+
+With --watch-file, saving the config file applies server.cache-ttl and
+server.metrics to the running server without a restart (a --cache-ttl given
+on the command line still wins). Other settings are read once at startup.
+
+Each call is cancelled after --request-timeout (server.request-timeout,
+default 30s; 0 disables) and fails with DEADLINE_EXCEEDED, so a stuck lsof
+cannot hold a server goroutine forever. ScanPorts is exempt: a scan is bounded
+by its per-port connection timeout and may take longer over a large range.
+
 Examples:
   portctl grpc                    # Start on default port 57251
-  portctl grpc --port 9090        # Start on custom port`,
+  portctl grpc --port 9090        # Start on custom port
+  portctl grpc --bind 127.0.0.1   # Only accept local connections
+  portctl grpc --interface eth0   # Listen on the addresses of eth0 only
+  portctl grpc --watch-file       # Apply config file changes while running
+  portctl grpc --request-timeout 5s  # Give up on calls that take longer than 5s`,
 	Run: runGRPC,
 }
 
 func init() {
 	rootCmd.AddCommand(grpcCmd)
 	grpcCmd.Flags().StringVarP(&grpcPort, "port", "p", "57251", "Port to listen on")
+	grpcCmd.Flags().StringVar(&grpcBind, "bind", "",
+		"Address or host name to listen on (default: all interfaces)")
+	grpcCmd.Flags().StringVar(&grpcInterface, "interface", "",
+		"Listen only on the addresses of this network interface")
+	grpcCmd.MarkFlagsMutuallyExclusive("bind", "interface")
+	grpcCmd.Flags().DurationVar(&serverCacheTTL, "cache-ttl", defaultServerCacheTTL,
+		"Reuse process listings for this long between requests; 0 disables caching")
+	grpcCmd.Flags().BoolVar(&serverWatchConfig, "watch-file", false,
+		"Reload server.cache-ttl and server.metrics when the config file changes")
+	grpcCmd.Flags().DurationVar(&serverRequestTimeout, "request-timeout", defaultRequestTimeout,
+		"Cancel any call still running after this long; 0 disables")
+
+	// Added here because listing mcpCmd in configFlagBindings would create an
+	// initialization cycle through rootCmd
+	configFlagBindings = append(configFlagBindings,
+		configFlagBinding{grpcCmd, "cache-ttl", "server.cache-ttl"},
+		configFlagBinding{mcpCmd, "cache-ttl", "server.cache-ttl"},
+		configFlagBinding{grpcCmd, "request-timeout", "server.request-timeout"},
+		configFlagBinding{mcpCmd, "request-timeout", "server.request-timeout"})
+}
+
+// withRequestTimeout bounds a request's context by timeout; 0 leaves it unbounded
+func withRequestTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// requestTimedOut reports whether ctx, derived from parent by
+// withRequestTimeout, expired because of the server's own timeout rather than
+// the caller cancelling or its deadline passing
+func requestTimedOut(parent, ctx context.Context) bool {
+	return parent.Err() == nil && errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// requestTimeoutStatus is returned for calls cut off by --request-timeout
+func requestTimeoutStatus(timeout time.Duration) error {
+	return status.Errorf(codes.DeadlineExceeded, "request exceeded the server's %s timeout", timeout)
+}
+
+// untimedGRPCMethods are exempt from the request timeout. A scan is bounded
+// by its per-port connection timeout, and a large range legitimately takes
+// longer than any enumeration; the caller can still cancel it.
+var untimedGRPCMethods = map[string]bool{
+	pb.PortctlService_ScanPorts_FullMethodName: true,
+}
+
+// timeoutUnaryInterceptor runs every unary call except those in
+// untimedGRPCMethods under the request timeout. A call that overran fails
+// with DEADLINE_EXCEEDED even if the handler returned partial results.
+func timeoutUnaryInterceptor(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if untimedGRPCMethods[info.FullMethod] {
+			return handler(ctx, req)
+		}
+		callCtx, cancel := withRequestTimeout(ctx, timeout)
+		defer cancel()
+
+		resp, err := handler(callCtx, req)
+		switch {
+		case requestTimedOut(ctx, callCtx):
+			return nil, requestTimeoutStatus(timeout)
+		case ctx.Err() != nil:
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return resp, err
+	}
+}
+
+// timeoutServerStream replaces a server stream's context with one bounded by
+// the request timeout
+type timeoutServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *timeoutServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// timeoutStreamInterceptor runs every streaming call under the request timeout
+func timeoutStreamInterceptor(timeout time.Duration) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := ss.Context()
+		callCtx, cancel := withRequestTimeout(ctx, timeout)
+		defer cancel()
+
+		err := handler(srv, &timeoutServerStream{ServerStream: ss, ctx: callCtx})
+		if requestTimedOut(ctx, callCtx) {
+			return requestTimeoutStatus(timeout)
+		}
+		return err
+	}
 }
 
 type portctlServer struct {
 	pb.UnimplementedPortctlServiceServer
 	startTime time.Time
+	pm        *process.ProcessManager
 }
 
 func newPortctlServer() *portctlServer {
 	return &portctlServer{
 		startTime: time.Now(),
+		pm:        newServerProcessManager(),
 	}
 }
 
 func (s *portctlServer) ListProcesses(ctx context.Context, req *pb.ListProcessesRequest) (*pb.ListProcessesResponse, error) {
-	pm := process.NewProcessManager()
+	if req.GetOffset() < 0 || req.GetLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "offset and limit must not be negative")
+	}
+	sortKeys, err := process.ParseSortKeys(req.GetSortBy(), "")
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	pm := s.pm
 
 	var processes []process.Process
-	var err error
 
 	if req.Port != nil && *req.Port > 0 {
 		processes, err = pm.GetProcessesOnPort(ctx, int(*req.Port))
@@ -69,39 +205,161 @@ func (s *portctlServer) ListProcesses(ctx context.Context, req *pb.ListProcesses
 	}
 
 	// Apply filters
-	if req.Service != nil || req.User != nil {
-		filterOpts := process.FilterOptions{}
-		if req.Service != nil {
-			filterOpts.Service = *req.Service
-		}
-		if req.User != nil {
-			filterOpts.User = *req.User
-		}
-		processes = pm.FilterProcesses(processes, filterOpts)
+	processes = pm.FilterProcesses(processes, listFilterOptions(req))
+	processes = pm.SortProcesses(processes, sortKeys)
+
+	// Paginate
+	total := len(processes)
+	start := int(req.GetOffset())
+	if start > total {
+		start = total
+	}
+	end := total
+	if req.Limit != nil && start+int(req.GetLimit()) < total {
+		end = start + int(req.GetLimit())
 	}
+	processes = processes[start:end]
 
-	// Convert to proto
+	// Convert to proto, keeping only the requested fields
+	include, ignored := protoFieldFilter(req.Fields)
 	pbProcesses := make([]*pb.Process, len(processes))
 	for i, p := range processes {
-		pbProcesses[i] = &pb.Process{
-			Pid:         int32(p.PID),
-			Port:        int32(p.Port),
-			Command:     p.Command,
-			ServiceType: p.ServiceType,
-			User:        p.User,
-			CpuPercent:  p.CPUPercent,
-			MemoryMb:    float64(p.MemoryMB),
-			StartTime:   p.StartTime.Unix(),
-		}
+		pbProcesses[i] = toProtoProcess(p, include)
 	}
 
 	return &pb.ListProcessesResponse{
-		Processes: pbProcesses,
+		Processes:     pbProcesses,
+		IgnoredFields: ignored,
+		Total:         int32(total),
 	}, nil
 }
 
+// listFilterOptions returns the filters of a ListProcesses request. Both the
+// buffered and the streaming call use it, so they select the same processes.
+func listFilterOptions(req *pb.ListProcessesRequest) process.FilterOptions {
+	return process.FilterOptions{
+		Service:     req.GetService(),
+		User:        req.GetUser(),
+		MemoryLimit: req.GetMemoryLimit(),
+		CPULimit:    req.GetCpuLimit(),
+	}
+}
+
+// ListProcessesStream sends each matching process as soon as it has been
+// enumerated and enhanced, instead of buffering the whole listing. Requested
+// fields that were not recognised are reported in the "ignored-fields" header.
+func (s *portctlServer) ListProcessesStream(req *pb.ListProcessesRequest, stream grpc.ServerStreamingServer[pb.Process]) error {
+	if req.GetSortBy() != "" || req.GetOffset() != 0 {
+		return status.Error(codes.InvalidArgument, "sort_by and offset are not supported when streaming; use ListProcesses")
+	}
+	if req.GetLimit() < 0 {
+		return status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	include, ignored := protoFieldFilter(req.Fields)
+	if len(ignored) > 0 {
+		if err := stream.SendHeader(metadata.Pairs("ignored-fields", strings.Join(ignored, ","))); err != nil {
+			return err
+		}
+	}
+
+	// An explicit zero limit asks for no processes, so don't enumerate any
+	if req.Limit != nil && req.GetLimit() == 0 {
+		return nil
+	}
+
+	filterOpts := listFilterOptions(req)
+	errLimitReached := errors.New("limit reached")
+	sent := 0
+	err := s.pm.StreamProcesses(stream.Context(), int(req.GetPort()), func(p process.Process) error {
+		if len(s.pm.FilterProcesses([]process.Process{p}, filterOpts)) == 0 {
+			return nil
+		}
+		if err := stream.Send(toProtoProcess(p, include)); err != nil {
+			return err
+		}
+		sent++
+		if req.Limit != nil && sent >= int(req.GetLimit()) {
+			return errLimitReached
+		}
+		return nil
+	})
+	switch {
+	case err == nil, errors.Is(err, errLimitReached):
+		return nil
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case status.Code(err) != codes.Unknown:
+		return err
+	default:
+		return status.Errorf(codes.Internal, "failed to get processes: %v", err)
+	}
+}
+
+// protoProcessFields are the Process fields that toProtoProcess fills. Other
+// JSON field names, such as local_addr, have no pb.Process counterpart.
+var protoProcessFields = map[string]bool{
+	"pid":          true,
+	"port":         true,
+	"command":      true,
+	"service_type": true,
+	"user":         true,
+	"cpu_percent":  true,
+	"memory_mb":    true,
+	"start_time":   true,
+}
+
+// protoFieldFilter reports which Process fields to populate for the requested
+// field names (all of them when none are given), plus the names that are not
+// fields of pb.Process, including JSON-only ones
+func protoFieldFilter(requested []string) (func(string) bool, []string) {
+	fields, ignored := process.SelectFields(requested)
+	selected := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		if protoProcessFields[f] {
+			selected[f] = true
+		} else {
+			ignored = append(ignored, f)
+		}
+	}
+	if len(selected) == 0 {
+		return func(string) bool { return true }, ignored
+	}
+	return func(name string) bool { return selected[name] }, ignored
+}
+
+// toProtoProcess converts p to its proto form, keeping only included fields
+func toProtoProcess(p process.Process, include func(string) bool) *pb.Process {
+	pbProc := &pb.Process{}
+	if include("pid") {
+		pbProc.Pid = int32(p.PID)
+	}
+	if include("port") {
+		pbProc.Port = int32(p.Port)
+	}
+	if include("command") {
+		pbProc.Command = p.Command
+	}
+	if include("service_type") {
+		pbProc.ServiceType = p.ServiceType
+	}
+	if include("user") {
+		pbProc.User = p.User
+	}
+	if include("cpu_percent") {
+		pbProc.CpuPercent = p.CPUPercent
+	}
+	if include("memory_mb") {
+		pbProc.MemoryMb = float64(p.MemoryMB)
+	}
+	if include("start_time") {
+		pbProc.StartTime = p.StartTime.Unix()
+	}
+	return pbProc
+}
+
 func (s *portctlServer) KillProcess(ctx context.Context, req *pb.KillProcessRequest) (*pb.KillProcessResponse, error) {
-	pm := process.NewProcessManager()
+	pm := s.pm
 
 	switch target := req.Target.(type) {
 	case *pb.KillProcessRequest_Pid:
@@ -119,6 +377,8 @@ func (s *portctlServer) KillProcess(ctx context.Context, req *pb.KillProcessRequ
 		}, nil
 
 	case *pb.KillProcessRequest_Port:
+		// Look the port up afresh, never in a cached snapshot, before signalling
+		pm.InvalidateCache()
 		processes, err := pm.GetProcessesOnPort(ctx, int(target.Port))
 		if err != nil {
 			return &pb.KillProcessResponse{
@@ -181,7 +441,7 @@ func (s *portctlServer) ScanPorts(ctx context.Context, req *pb.ScanPortsRequest)
 		ports = append(ports, p)
 	}
 
-	results := scanPorts(host, ports)
+	results := scanPorts(ctx, host, ports, nil)
 
 	pbResults := make([]*pb.PortScanResult, len(results))
 	for i, r := range results {
@@ -198,18 +458,33 @@ func (s *portctlServer) ScanPorts(ctx context.Context, req *pb.ScanPortsRequest)
 }
 
 func (s *portctlServer) GetSystemStats(ctx context.Context, req *pb.SystemStatsRequest) (*pb.SystemStatsResponse, error) {
-	pm := process.NewProcessManager()
-	stats, err := pm.GetSystemStats(ctx)
+	pm := s.pm
+	stats, err := pm.GetSystemStats(ctx, process.StatsOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get system stats: %w", err)
 	}
 
-	return &pb.SystemStatsResponse{
-		CpuPercent:     stats.CPUUsagePercent,
-		MemoryPercent:  (stats.MemoryUsageGB / (stats.MemoryUsageGB + stats.AvailableMemoryGB)) * 100,
-		TotalProcesses: int32(stats.TotalProcesses),
-		ListeningPorts: int32(stats.ListeningPorts),
-	}, nil
+	return toProtoSystemStats(stats, req.IncludePerCore), nil
+}
+
+// toProtoSystemStats converts stats for GetSystemStats, with per-core CPU
+// figures only if perCore is set
+func toProtoSystemStats(stats *process.SystemStats, perCore bool) *pb.SystemStatsResponse {
+	resp := &pb.SystemStatsResponse{
+		CpuPercent:             stats.CPUUsagePercent,
+		MemoryPercent:          memoryUsagePercent(stats),
+		TotalProcesses:         int32(stats.TotalProcesses),
+		ListeningPorts:         int32(stats.ListeningPorts),
+		BytesSent:              stats.BytesSent,
+		BytesRecv:              stats.BytesRecv,
+		SendRate:               stats.SendRate,
+		RecvRate:               stats.RecvRate,
+		EstablishedConnections: int32(stats.Established),
+	}
+	if perCore {
+		resp.PerCorePercent = stats.PerCorePercent
+	}
+	return resp
 }
 
 func (s *portctlServer) GetStatus(ctx context.Context, req *pb.StatusRequest) (*pb.StatusResponse, error) {
@@ -221,15 +496,59 @@ func (s *portctlServer) GetStatus(ctx context.Context, req *pb.StatusRequest) (*
 	}, nil
 }
 
+// grpcListenAddrs returns the addresses runGRPC listens on: one for --bind
+// or the default wildcard, one per address of the --interface interface
+func grpcListenAddrs(bind, iface, port string) ([]string, error) {
+	if iface == "" {
+		return []string{net.JoinHostPort(bind, port)}, nil
+	}
+
+	ips, err := process.InterfaceAddrs(iface)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		host := ip.String()
+		// Link-local IPv6 addresses are only unique together with their zone
+		if ip.To4() == nil && ip.IsLinkLocalUnicast() {
+			host += "%" + iface
+		}
+		addrs = append(addrs, net.JoinHostPort(host, port))
+	}
+	return addrs, nil
+}
+
 func runGRPC(cmd *cobra.Command, args []string) {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
+	addrs, err := grpcListenAddrs(grpcBind, grpcInterface, grpcPort)
 	if err != nil {
-		color.Red("Failed to listen on port %s: %v", grpcPort, err)
-		os.Exit(1)
+		color.Red("%v", err)
+		os.Exit(exitCodeUsage)
 	}
 
-	grpcServer := grpc.NewServer()
-	pb.RegisterPortctlServiceServer(grpcServer, newPortctlServer())
+	listeners := make([]net.Listener, 0, len(addrs))
+	for _, addr := range addrs {
+		lis, err := net.Listen("tcp", addr)
+		if err != nil {
+			color.Red("Failed to listen on %s: %v", addr, err)
+			os.Exit(1)
+		}
+		listeners = append(listeners, lis)
+	}
+
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(timeoutUnaryInterceptor(serverRequestTimeout)),
+		grpc.StreamInterceptor(timeoutStreamInterceptor(serverRequestTimeout)),
+	)
+	srv := newPortctlServer()
+	pb.RegisterPortctlServiceServer(grpcServer, srv)
+
+	if serverWatchConfig {
+		if err := watchServerConfig(cmd, srv.pm); err != nil {
+			color.Red("Cannot watch config: %v", err)
+			os.Exit(exitCodeError)
+		}
+	}
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
@@ -241,11 +560,26 @@ func runGRPC(cmd *cobra.Command, args []string) {
 		grpcServer.GracefulStop()
 	}()
 
-	color.Green("🚀 gRPC server listening on :%s", grpcPort)
-	color.Cyan("Test with: grpcurl -plaintext localhost:%s list", grpcPort)
-
-	if err := grpcServer.Serve(lis); err != nil {
-		color.Red("Server error: %v", err)
-		os.Exit(1)
+	for _, lis := range listeners {
+		color.Green("🚀 gRPC server listening on %s", lis.Addr())
+	}
+	testAddr := net.JoinHostPort("localhost", grpcPort)
+	if grpcBind != "" || grpcInterface != "" {
+		testAddr = listeners[0].Addr().String()
+	}
+	color.Cyan("Test with: grpcurl -plaintext %s list", testAddr)
+
+	// GracefulStop closes every listener, so each Serve returns nil on shutdown
+	errs := make(chan error, len(listeners))
+	for _, lis := range listeners {
+		go func(lis net.Listener) {
+			errs <- grpcServer.Serve(lis)
+		}(lis)
+	}
+	for range listeners {
+		if err := <-errs; err != nil {
+			color.Red("Server error: %v", err)
+			os.Exit(1)
+		}
 	}
 }
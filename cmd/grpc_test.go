@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	process "dagger/portctl/pkg"
+	pb "dagger/portctl/proto"
+)
+
+// blockingHandler waits for its context to end, like an enumeration stuck in
+// lsof, then returns a partial result
+func blockingHandler(ctx context.Context, req any) (any, error) {
+	<-ctx.Done()
+	return "partial", nil
+}
+
+func TestTimeoutUnaryInterceptor(t *testing.T) {
+	intercept := timeoutUnaryInterceptor(20 * time.Millisecond)
+
+	resp, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{}, blockingHandler)
+	if status.Code(err) != codes.DeadlineExceeded || resp != nil {
+		t.Errorf("stuck call = (%v, %v), want DeadlineExceeded and no response", resp, err)
+	}
+
+	resp, err = intercept(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Errorf("fast call = (%v, %v), want ok", resp, err)
+	}
+}
+
+func TestTimeoutUnaryInterceptorCallerCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := timeoutUnaryInterceptor(time.Minute)(ctx, nil, &grpc.UnaryServerInfo{}, blockingHandler)
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("cancelled call error = %v, want Canceled", err)
+	}
+}
+
+func TestTimeoutUnaryInterceptorDisabled(t *testing.T) {
+	_, err := timeoutUnaryInterceptor(0)(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("context has a deadline with the timeout disabled")
+		}
+		return nil, nil
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestGRPCListenAddrs(t *testing.T) {
+	addrs, err := grpcListenAddrs("", "", "57251")
+	if err != nil || len(addrs) != 1 || addrs[0] != ":57251" {
+		t.Errorf("default = %v, %v; want [:57251]", addrs, err)
+	}
+
+	addrs, err = grpcListenAddrs("::1", "", "9090")
+	if err != nil || len(addrs) != 1 || addrs[0] != "[::1]:9090" {
+		t.Errorf("--bind ::1 = %v, %v; want [[::1]:9090]", addrs, err)
+	}
+
+	addrs, err = grpcListenAddrs("", "lo", "9090")
+	if err != nil {
+		t.Skipf("no loopback interface named lo: %v", err)
+	}
+	found := false
+	for _, addr := range addrs {
+		if addr == "127.0.0.1:9090" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("--interface lo = %v, want 127.0.0.1:9090 among them", addrs)
+	}
+
+	if _, err := grpcListenAddrs("", "no-such-iface0", "9090"); err == nil {
+		t.Error("Expected an error for an unknown interface")
+	}
+}
+
+func TestProtoFieldFilterJSONOnlyFields(t *testing.T) {
+	include, ignored := protoFieldFilter([]string{"pid,local_addr", "bind_scope", "bogus"})
+	if !include("pid") || include("port") {
+		t.Error("Expected only pid to be included")
+	}
+	if want := []string{"bogus", "local_addr", "bind_scope"}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("ignored = %v, want %v", ignored, want)
+	}
+
+	// Only JSON-only fields: nothing selectable, so every proto field is sent
+	include, ignored = protoFieldFilter([]string{"protocol"})
+	if !include("pid") || !include("start_time") {
+		t.Error("Expected all fields when none of the requested ones exist in pb.Process")
+	}
+	if want := []string{"protocol"}; !reflect.DeepEqual(ignored, want) {
+		t.Errorf("ignored = %v, want %v", ignored, want)
+	}
+}
+
+func TestListProcessesReportsJSONOnlyFields(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := int32(ln.Addr().(*net.TCPAddr).Port)
+
+	resp, err := newPortctlServer().ListProcesses(context.Background(), &pb.ListProcessesRequest{
+		Port:   &port,
+		Fields: []string{"port", "local_addr"},
+	})
+	if err != nil {
+		t.Fatalf("ListProcesses failed: %v", err)
+	}
+	if want := []string{"local_addr"}; !reflect.DeepEqual(resp.IgnoredFields, want) {
+		t.Errorf("IgnoredFields = %v, want %v", resp.IgnoredFields, want)
+	}
+	for _, proc := range resp.Processes {
+		if proc.Port != port || proc.Pid != 0 {
+			t.Errorf("Expected only port %d to be set, got %+v", port, proc)
+		}
+	}
+}
+
+// recordingStream is a ListProcessesStream server stream that keeps what is sent
+type recordingStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pb.Process
+}
+
+func (s *recordingStream) Context() context.Context     { return s.ctx }
+func (s *recordingStream) SendHeader(metadata.MD) error { return nil }
+func (s *recordingStream) Send(p *pb.Process) error     { s.sent = append(s.sent, p); return nil }
+
+func TestListProcessesStreamZeroLimit(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := int32(ln.Addr().(*net.TCPAddr).Port)
+
+	limit := int32(0)
+	stream := &recordingStream{ctx: context.Background()}
+	if err := newPortctlServer().ListProcessesStream(&pb.ListProcessesRequest{Port: &port, Limit: &limit}, stream); err != nil {
+		t.Fatalf("ListProcessesStream failed: %v", err)
+	}
+	if len(stream.sent) != 0 {
+		t.Errorf("limit 0 streamed %d process(es), want none", len(stream.sent))
+	}
+}
+
+func TestListProcessesStreamMatchesListProcesses(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := int32(ln.Addr().(*net.TCPAddr).Port)
+
+	server := newPortctlServer()
+	req := &pb.ListProcessesRequest{Port: &port, Fields: []string{"pid", "port"}}
+	resp, err := server.ListProcesses(context.Background(), req)
+	if err != nil {
+		t.Fatalf("ListProcesses failed: %v", err)
+	}
+	if len(resp.Processes) == 0 {
+		t.Skip("this test process is not visible to port enumeration here")
+	}
+
+	stream := &recordingStream{ctx: context.Background()}
+	if err := server.ListProcessesStream(req, stream); err != nil {
+		t.Fatalf("ListProcessesStream failed: %v", err)
+	}
+	pids := func(procs []*pb.Process) map[int32]bool {
+		set := make(map[int32]bool)
+		for _, p := range procs {
+			set[p.Pid] = true
+		}
+		return set
+	}
+	if got, want := pids(stream.sent), pids(resp.Processes); !reflect.DeepEqual(got, want) {
+		t.Errorf("stream returned PIDs %v, ListProcesses returned %v", got, want)
+	}
+}
+
+func TestTimeoutUnaryInterceptorExemptsScans(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: pb.PortctlService_ScanPorts_FullMethodName}
+	resp, err := timeoutUnaryInterceptor(time.Millisecond)(context.Background(), nil, info, func(ctx context.Context, req any) (any, error) {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("ScanPorts ran under the request timeout")
+		}
+		return "done", nil
+	})
+	if err != nil || resp != "done" {
+		t.Errorf("ScanPorts = (%v, %v), want done", resp, err)
+	}
+}
+
+func TestKillProcessByPortBypassesCache(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	_ = ln.Close()
+
+	server := newPortctlServer()
+	server.pm.WithCache(time.Minute)
+
+	// Cache a listing taken before anything listens on the port
+	if _, err := server.ListProcesses(context.Background(), &pb.ListProcessesRequest{}); err != nil {
+		t.Fatalf("ListProcesses failed: %v", err)
+	}
+	startListener(t, port)
+	if runPortctl(t, "check", strconv.Itoa(port)) != 0 {
+		t.Skip("the listener process is not visible to port enumeration here")
+	}
+
+	resp, err := server.KillProcess(context.Background(), &pb.KillProcessRequest{
+		Target: &pb.KillProcessRequest_Port{Port: int32(port)},
+		Force:  true,
+	})
+	if err != nil {
+		t.Fatalf("KillProcess failed: %v", err)
+	}
+	if resp.KilledCount != 1 {
+		t.Fatalf("KillProcess by port killed %d process(es) (%s), want the listener started after the cached listing",
+			resp.KilledCount, resp.Message)
+	}
+}
+
+func TestSystemStatsMemoryPercentMatchesCLI(t *testing.T) {
+	// Used plus available is less than installed memory, as with a page cache
+	stats := &process.SystemStats{
+		MemoryUsageGB:     8,
+		AvailableMemoryGB: 6,
+		TotalMemoryGB:     16,
+		PerCorePercent:    []float64{10, 20},
+	}
+
+	resp := toProtoSystemStats(stats, false)
+	if resp.MemoryPercent != 50 {
+		t.Errorf("MemoryPercent = %v, want 50 (used over installed memory)", resp.MemoryPercent)
+	}
+	if cli := memoryUsagePercent(stats); resp.MemoryPercent != cli {
+		t.Errorf("gRPC MemoryPercent = %v, CLI stats report %v", resp.MemoryPercent, cli)
+	}
+	if want := "mem=8.0/16.0GB"; !strings.Contains(formatStatsLine(stats), want) {
+		t.Errorf("formatStatsLine() = %q, want it to contain %q", formatStatsLine(stats), want)
+	}
+	if resp.PerCorePercent != nil {
+		t.Errorf("PerCorePercent = %v without include_per_core", resp.PerCorePercent)
+	}
+	if resp := toProtoSystemStats(stats, true); len(resp.PerCorePercent) != 2 {
+		t.Errorf("PerCorePercent = %v with include_per_core, want 2 cores", resp.PerCorePercent)
+	}
+}
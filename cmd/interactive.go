@@ -3,16 +3,21 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/spf13/cobra"
 
 	process "dagger/portctl/pkg"
@@ -24,6 +29,8 @@ const (
 	stateLoading sessionState = iota
 	stateList
 	stateFilter
+	stateSearch
+	stateExport
 	stateDetails
 	stateKillConfirm
 	stateStats
@@ -34,16 +41,22 @@ type tuiModel struct {
 	processes     []process.Process
 	filteredProcs []process.Process
 	list          list.Model
+	delegate      highlightDelegate
 	spinner       spinner.Model
 	textInput     textinput.Model
+	searchInput   textinput.Model
 	selectedProc  process.Process
 	stats         *process.SystemStats
+	statsUpdated  time.Time
+	statsGen      int // identifies the current stats refresh loop
 	pm            *process.ProcessManager
 	err           error
 	width         int
 	height        int
 	filterQuery   string
+	searchQuery   string
 	showHelp      bool
+	notice        string
 	lastUpdate    time.Time
 	ctx           context.Context
 }
@@ -61,11 +74,91 @@ func (i processItem) Title() string {
 }
 
 func (i processItem) Description() string {
-	memStr := fmt.Sprintf("%.1fMB", i.MemoryMB)
+	memStr := process.FormatMemory(float64(i.MemoryMB))
 	cpuStr := fmt.Sprintf("%.1f%%", i.CPUPercent)
 	return fmt.Sprintf("%s • %s • %s • %s", i.Command, i.ServiceType, memStr, cpuStr)
 }
 
+// highlightDelegate renders list items like the default delegate, but with
+// every occurrence of query emphasized instead of hiding non-matching rows
+type highlightDelegate struct {
+	list.DefaultDelegate
+	query string
+}
+
+func (d highlightDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	i, ok := item.(processItem)
+	if d.query == "" || !ok || m.Width() <= 0 {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+
+	s := &d.Styles
+	titleStyle, descStyle := s.NormalTitle, s.NormalDesc
+	if index == m.Index() {
+		titleStyle, descStyle = s.SelectedTitle, s.SelectedDesc
+	}
+
+	textwidth := m.Width() - s.NormalTitle.GetPaddingLeft() - s.NormalTitle.GetPaddingRight()
+	title := ansi.Truncate(i.Title(), textwidth, "…")
+	desc := ansi.Truncate(i.Description(), textwidth, "…")
+
+	title = highlightMatches(title, d.query, titleStyle)
+	desc = highlightMatches(desc, d.query, descStyle)
+
+	if d.ShowDescription {
+		fmt.Fprintf(w, "%s\n%s", titleStyle.Render(title), descStyle.Render(desc))
+		return
+	}
+	fmt.Fprint(w, titleStyle.Render(title))
+}
+
+// highlightMatches styles the runes of s that match query, keeping the rest
+// in the row's own style so the highlight does not reset it
+func highlightMatches(s, query string, base lipgloss.Style) string {
+	indices := matchIndices(s, query)
+	if len(indices) == 0 {
+		return s
+	}
+	unmatched := base.Inline(true)
+	matched := searchMatchStyle.Inherit(unmatched)
+	return lipgloss.StyleRunes(s, indices, matched, unmatched)
+}
+
+// matchIndices returns the rune indices of every case-insensitive occurrence
+// of query in s
+func matchIndices(s, query string) []int {
+	text := []rune(s)
+	q := []rune(query)
+	if len(q) == 0 {
+		return nil
+	}
+
+	var indices []int
+	for start := 0; start+len(q) <= len(text); start++ {
+		matched := true
+		for j, r := range q {
+			if unicode.ToLower(text[start+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			for j := range q {
+				if len(indices) == 0 || indices[len(indices)-1] < start+j {
+					indices = append(indices, start+j)
+				}
+			}
+		}
+	}
+	return indices
+}
+
+// itemMatches reports whether the visible text of an item contains query
+func itemMatches(item processItem, query string) bool {
+	return len(matchIndices(item.Title(), query)) > 0 || len(matchIndices(item.Description(), query)) > 0
+}
+
 var (
 	// Styles
 	titleStyle = lipgloss.NewStyle().
@@ -91,6 +184,11 @@ var (
 
 	warningStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF8700"))
+
+	searchMatchStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFD700")).
+				Bold(true).
+				Underline(true)
 )
 
 var interactiveCmd = &cobra.Command{
@@ -109,9 +207,12 @@ Features:
 Navigation:
   ↑/↓     Navigate process list
   /       Enter filter mode
+  f       Highlight matches without filtering
+  n       Jump to the next highlighted match
+  e       Save the listed processes to a JSON file
   Enter   View process details
   k       Kill selected process
-  s       Show system statistics
+  s       Show live system statistics
   r       Refresh process list
   q       Quit`,
 	Aliases: []string{"tui", "ui", "i"},
@@ -119,7 +220,7 @@ Navigation:
 }
 
 func runInteractive(cmd *cobra.Command, args []string) {
-	pm := process.NewProcessManager()
+	pm := newProcessManager()
 	ctx := cmd.Context()
 
 	// Configure list delegate
@@ -132,7 +233,8 @@ func runInteractive(cmd *cobra.Command, args []string) {
 
 	// Initialize list with empty items
 	items := []list.Item{}
-	tuiList := list.New(items, delegate, 0, 0)
+	highlighter := highlightDelegate{DefaultDelegate: delegate}
+	tuiList := list.New(items, highlighter, 0, 0)
 	tuiList.Title = ""
 	tuiList.SetShowStatusBar(false)
 	tuiList.SetFilteringEnabled(false) // We'll handle filtering ourselves
@@ -142,6 +244,7 @@ func runInteractive(cmd *cobra.Command, args []string) {
 		state:      stateLoading,
 		pm:         pm,
 		list:       tuiList,
+		delegate:   highlighter,
 		lastUpdate: time.Now(),
 		ctx:        ctx,
 	}
@@ -156,6 +259,11 @@ func runInteractive(cmd *cobra.Command, args []string) {
 	m.textInput.Placeholder = "Filter processes..."
 	m.textInput.CharLimit = 50
 
+	// Initialize text input for highlighting
+	m.searchInput = textinput.New()
+	m.searchInput.Placeholder = "Highlight matches..."
+	m.searchInput.CharLimit = 50
+
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		log.Fatal(err)
@@ -191,6 +299,22 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.state = stateFilter
 				m.textInput.Focus()
 				return m, textinput.Blink
+			case "f":
+				m.state = stateSearch
+				m.searchInput.SetValue(m.searchQuery)
+				m.searchInput.Focus()
+				return m, textinput.Blink
+			case "n":
+				m.selectNextMatch()
+				return m, nil
+			case "e":
+				m.state = stateExport
+				m.notice = ""
+				m.textInput.Placeholder = "File to save to..."
+				m.textInput.SetValue(defaultExportFile)
+				m.textInput.CursorEnd()
+				m.textInput.Focus()
+				return m, textinput.Blink
 			case "enter":
 				if len(m.filteredProcs) > 0 {
 					m.selectedProc = m.filteredProcs[m.list.Index()]
@@ -204,8 +328,11 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			case "s":
+				// Starting a new generation orphans any tick left over
+				// from a previous visit, so only one refresh loop runs
 				m.state = stateStats
-				cmds = append(cmds, loadStats(m.ctx, m.pm))
+				m.statsGen++
+				cmds = append(cmds, loadStats(m.ctx, m.pm, m.statsGen))
 			case "r":
 				m.state = stateLoading
 				cmds = append(cmds, loadProcesses(m.ctx, m.pm))
@@ -230,6 +357,36 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, nil
 			}
 
+		case stateSearch:
+			switch msg.String() {
+			case "esc":
+				m.state = stateList
+				m.searchInput.Blur()
+				m.setSearchQuery("")
+				return m, nil
+			case "enter":
+				m.state = stateList
+				m.searchInput.Blur()
+				m.selectNextMatch()
+				return m, nil
+			}
+
+		case stateExport:
+			switch msg.String() {
+			case "esc":
+				m.state = stateList
+				m.resetTextInput()
+				return m, nil
+			case "enter":
+				path := strings.TrimSpace(m.textInput.Value())
+				if path == "" {
+					return m, nil
+				}
+				m.state = stateList
+				m.resetTextInput()
+				return m, exportProcesses(path, m.filteredProcs)
+			}
+
 		case stateDetails, stateKillConfirm, stateStats:
 			switch msg.String() {
 			case "esc", "q":
@@ -260,6 +417,23 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case statsLoadedMsg:
 		m.stats = msg.stats
 		m.err = msg.err
+		m.statsUpdated = time.Now()
+		if m.state == stateStats && msg.gen == m.statsGen {
+			cmds = append(cmds, statsTick(msg.gen))
+		}
+
+	case statsTickMsg:
+		// Refresh only while the stats view that started this loop is open
+		if m.state == stateStats && msg.gen == m.statsGen {
+			cmds = append(cmds, loadStats(m.ctx, m.pm, msg.gen))
+		}
+
+	case processesExportedMsg:
+		if msg.err != nil {
+			m.notice = errorStyle.Render(fmt.Sprintf("Export failed: %v", msg.err))
+		} else {
+			m.notice = infoStyle.Render(fmt.Sprintf("✅ Saved %d process(es) to %s", msg.count, msg.path))
+		}
 
 	case processKilledMsg:
 		// Process killed, reload list
@@ -272,11 +446,18 @@ func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	// Update list and text input
-	if m.state == stateFilter {
+	switch m.state {
+	case stateFilter, stateExport:
 		var cmd tea.Cmd
 		m.textInput, cmd = m.textInput.Update(msg)
 		cmds = append(cmds, cmd)
-	} else {
+	case stateSearch:
+		// Highlight incrementally as the query is typed
+		var cmd tea.Cmd
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		m.setSearchQuery(m.searchInput.Value())
+		cmds = append(cmds, cmd)
+	default:
 		var cmd tea.Cmd
 		m.list, cmd = m.list.Update(msg)
 		cmds = append(cmds, cmd)
@@ -300,6 +481,10 @@ func (m tuiModel) View() string {
 		header += statusStyle.Render(fmt.Sprintf(" • %d processes • Last updated: %s",
 			len(m.processes), m.lastUpdate.Format("15:04:05")))
 	}
+	if m.searchQuery != "" {
+		header += statusStyle.Render(fmt.Sprintf(" • Highlighting %q (%d matches)",
+			m.searchQuery, m.countMatches()))
+	}
 	content.WriteString(header + "\n\n")
 
 	// Handle error state
@@ -325,6 +510,16 @@ func (m tuiModel) View() string {
 		content.WriteString(m.textInput.View() + "\n\n")
 		content.WriteString(helpStyle.Render("Press Enter to apply filter, Esc to cancel"))
 
+	case stateExport:
+		content.WriteString(fmt.Sprintf("Save %d process(es) as JSON to:\n", len(m.filteredProcs)))
+		content.WriteString(m.textInput.View() + "\n\n")
+		content.WriteString(helpStyle.Render("Press Enter to save, Esc to cancel"))
+
+	case stateSearch:
+		content.WriteString(m.searchInput.View() + "\n")
+		content.WriteString(m.list.View() + "\n")
+		content.WriteString(helpStyle.Render("Press Enter to keep highlighting, Esc to clear"))
+
 	case stateDetails:
 		content.WriteString(m.renderProcessDetails())
 
@@ -336,7 +531,10 @@ func (m tuiModel) View() string {
 	}
 
 	// Footer with shortcuts (except in filter mode)
-	if m.state != stateFilter && m.state != stateLoading {
+	if m.state != stateFilter && m.state != stateSearch && m.state != stateExport && m.state != stateLoading {
+		if m.notice != "" && m.state == stateList {
+			content.WriteString("\n" + m.notice)
+		}
 		footer := "\n" + helpStyle.Render("Press 'h' for help, 'q' to quit")
 		content.WriteString(footer)
 	}
@@ -369,11 +567,56 @@ func (m *tuiModel) updateFilteredList() {
 	m.list.SetItems(items)
 }
 
+// resetTextInput returns the shared text input to filtering after it was
+// borrowed for another prompt
+func (m *tuiModel) resetTextInput() {
+	m.textInput.Blur()
+	m.textInput.Placeholder = "Filter processes..."
+	m.textInput.SetValue(m.filterQuery)
+}
+
+// setSearchQuery changes the highlighted text without touching the filter
+func (m *tuiModel) setSearchQuery(query string) {
+	m.searchQuery = query
+	m.delegate.query = query
+	m.list.SetDelegate(m.delegate)
+}
+
+// selectNextMatch moves the cursor to the next row after the current one
+// that contains the highlighted text, wrapping around at the end
+func (m *tuiModel) selectNextMatch() {
+	items := m.list.Items()
+	if m.searchQuery == "" || len(items) == 0 {
+		return
+	}
+	for offset := 1; offset <= len(items); offset++ {
+		i := (m.list.Index() + offset) % len(items)
+		if item, ok := items[i].(processItem); ok && itemMatches(item, m.searchQuery) {
+			m.list.Select(i)
+			return
+		}
+	}
+}
+
+// countMatches returns how many visible rows contain the highlighted text
+func (m tuiModel) countMatches() int {
+	count := 0
+	for _, it := range m.list.Items() {
+		if item, ok := it.(processItem); ok && itemMatches(item, m.searchQuery) {
+			count++
+		}
+	}
+	return count
+}
+
 func (m tuiModel) renderHelp() string {
 	var help strings.Builder
 	help.WriteString(highlightStyle.Render("Keyboard Shortcuts:") + "\n\n")
 	help.WriteString("  ↑/↓        Navigate process list\n")
 	help.WriteString("  /          Filter processes\n")
+	help.WriteString("  f          Highlight matches without filtering\n")
+	help.WriteString("  n          Jump to the next highlighted match\n")
+	help.WriteString("  e          Save the listed processes to a JSON file\n")
 	help.WriteString("  Enter      View process details\n")
 	help.WriteString("  k          Kill selected process\n")
 	help.WriteString("  s          Show system statistics\n")
@@ -397,13 +640,15 @@ func (m tuiModel) renderProcessDetails() string {
 	details.WriteString(fmt.Sprintf("User:         %s\n", proc.User))
 	details.WriteString(fmt.Sprintf("State:        %s\n", proc.State))
 	details.WriteString(fmt.Sprintf("Local Addr:   %s\n", proc.LocalAddr))
+	details.WriteString(fmt.Sprintf("Bind Scope:   %s\n", proc.BindScope))
 	details.WriteString(fmt.Sprintf("Remote Addr:  %s\n", proc.RemoteAddr))
 	details.WriteString(fmt.Sprintf("CPU Usage:    %.1f%%\n", proc.CPUPercent))
-	details.WriteString(fmt.Sprintf("Memory:       %.1f MB\n", proc.MemoryMB))
+	details.WriteString(fmt.Sprintf("Memory:       %s\n", process.FormatMemory(float64(proc.MemoryMB))))
+	details.WriteString(fmt.Sprintf("Nice:         %d\n", proc.Nice))
 
 	if !proc.StartTime.IsZero() {
-		details.WriteString(fmt.Sprintf("Started:      %s\n", proc.StartTime.Format("2006-01-02 15:04:05")))
-		details.WriteString(fmt.Sprintf("Uptime:       %s\n", time.Since(proc.StartTime).Round(time.Second)))
+		details.WriteString(fmt.Sprintf("Started:      %s\n", proc.StartTime.Format(process.StartTimeLayout)))
+		details.WriteString(fmt.Sprintf("Uptime:       %s\n", process.FormatSince(proc.StartTime)))
 	}
 
 	details.WriteString("\n" + helpStyle.Render("Press Esc to go back, 'k' to kill this process"))
@@ -441,21 +686,20 @@ func (m tuiModel) renderStats() string {
 	stats.WriteString(fmt.Sprintf("CPU Usage:          %s\n",
 		infoStyle.Render(fmt.Sprintf("%.1f%%", m.stats.CPUUsagePercent))))
 	stats.WriteString(fmt.Sprintf("Memory Used:        %s\n",
-		infoStyle.Render(fmt.Sprintf("%.1f GB", m.stats.MemoryUsageGB))))
+		infoStyle.Render(process.FormatMemory(m.stats.MemoryUsageGB*1024))))
 	stats.WriteString(fmt.Sprintf("Memory Available:   %s\n",
-		infoStyle.Render(fmt.Sprintf("%.1f GB", m.stats.AvailableMemoryGB))))
+		infoStyle.Render(process.FormatMemory(m.stats.AvailableMemoryGB*1024))))
 
 	if len(m.stats.TopPortUsers) > 0 {
 		stats.WriteString("\n" + highlightStyle.Render("Top Memory Users:") + "\n")
 		for i, proc := range m.stats.TopPortUsers {
-			if i >= 5 {
-				break
-			}
-			stats.WriteString(fmt.Sprintf("  %d. %s (Port %d) - %.1f MB\n",
-				i+1, proc.Command, proc.Port, proc.MemoryMB))
+			stats.WriteString(fmt.Sprintf("  %d. %s (Port %d) - %s\n",
+				i+1, proc.Command, proc.Port, process.FormatMemory(float64(proc.MemoryMB))))
 		}
 	}
 
+	stats.WriteString("\n" + statusStyle.Render(fmt.Sprintf("Updated %s • refreshing every %s",
+		m.statsUpdated.Format("15:04:05"), statsRefreshInterval)))
 	stats.WriteString("\n" + helpStyle.Render("Press Esc to go back"))
 	return stats.String()
 }
@@ -469,6 +713,18 @@ type processesLoadedMsg struct {
 type statsLoadedMsg struct {
 	stats *process.SystemStats
 	err   error
+	gen   int
+}
+
+// statsTickMsg asks for the next stats refresh of loop gen
+type statsTickMsg struct {
+	gen int
+}
+
+type processesExportedMsg struct {
+	path  string
+	count int
+	err   error
 }
 
 type processKilledMsg struct {
@@ -484,10 +740,45 @@ func loadProcesses(ctx context.Context, pm *process.ProcessManager) tea.Cmd {
 	}
 }
 
-func loadStats(ctx context.Context, pm *process.ProcessManager) tea.Cmd {
+func loadStats(ctx context.Context, pm *process.ProcessManager, gen int) tea.Cmd {
 	return func() tea.Msg {
-		stats, err := pm.GetSystemStats(ctx)
-		return statsLoadedMsg{stats: stats, err: err}
+		stats, err := pm.GetSystemStats(ctx, process.StatsOptions{})
+		return statsLoadedMsg{stats: stats, err: err, gen: gen}
+	}
+}
+
+// statsRefreshInterval is the pause between stats refreshes. The next tick
+// is only scheduled once a refresh has finished, so slow loads never overlap.
+const statsRefreshInterval = 2 * time.Second
+
+func statsTick(gen int) tea.Cmd {
+	return tea.Tick(statsRefreshInterval, func(time.Time) tea.Msg {
+		return statsTickMsg{gen: gen}
+	})
+}
+
+// defaultExportFile is the file name offered when saving the TUI view
+const defaultExportFile = "portctl-processes.json"
+
+// exportProcesses writes processes to path in the same envelope as
+// 'portctl list --json'
+func exportProcesses(path string, processes []process.Process) tea.Cmd {
+	if processes == nil {
+		processes = []process.Process{}
+	}
+	return func() tea.Msg {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return processesExportedMsg{path: path, err: err}
+		}
+		err = writeJSONTo(f, processes)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		return processesExportedMsg{path: path, count: len(processes), err: err}
 	}
 }
 
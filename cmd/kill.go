@@ -3,14 +3,18 @@ package cmd
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	process "dagger/portctl/pkg"
 )
@@ -21,8 +25,26 @@ var (
 	killYes     bool
 	killRange   string
 	killService string
+	killCommand string
 	killUser    string
 	killOlder   string
+	killPPID    int
+	killBatchOK bool
+	killSelf    bool
+	killFile    string
+	killRestart bool
+	killDetails bool
+	killSignal  string
+	killAll     bool
+	killVerify  bool
+	killInode   uint64
+	killJSON    bool
+
+	// killVerifyTimeout is how long --verify waits for each process to exit
+	killVerifyTimeout time.Duration
+
+	// killSig is the signal to send, resolved from --signal and --force
+	killSig syscall.Signal
 )
 
 var killCmd = &cobra.Command{
@@ -35,22 +57,69 @@ Examples:
   # Single operations
   portctl kill 8080                    # Kill processes on port 8080
   portctl kill --pid 12345             # Kill process with PID 12345
+  portctl kill --inode 4481923         # Kill the process(es) holding one socket (Linux)
   
   # Multiple ports
   portctl kill 8080 3000 5000          # Kill processes on multiple ports
   portctl kill --range "3000-3010"     # Kill processes in port range
+  portctl kill --range "3000,8080-8090" # Mix port lists and ranges
   
   # Filtering
   portctl kill --service node          # Kill all Node.js processes
+  portctl kill --command node          # Kill only commands named exactly 'node'
   portctl kill --user john             # Kill processes owned by user 'john'
   portctl kill --older "1h"            # Kill processes older than 1 hour
+  portctl kill --ppid 4242             # Kill listeners spawned by PID 4242
+  portctl kill --service node --all-users  # Include other users' processes
+  
+  # From a file or stdin (one port or pid:NNN per line, # comments allowed)
+  portctl kill --from-file targets.txt
+  cat targets.txt | portctl kill --from-file -
   
   # Options
   portctl kill 8080 --force            # Force kill (SIGKILL)
-  portctl kill 8080 --yes              # Skip confirmation prompt`,
+  portctl kill 8080 --signal HUP       # Send another signal (see 'portctl signals')
+  portctl kill 8080 --yes              # Skip confirmation prompt
+  portctl kill 8080 --verify           # Fail unless the process has actually exited
+  portctl kill --range 3000-3999 --yes --confirm-batch  # Allow large batch kills
+  portctl kill 8080 --restart          # Kill, then re-launch the same command
+  portctl kill --service node --details  # Also show child process counts
+  portctl kill 8080 --yes --json       # Report killed and failed PIDs as JSON
+
+Processes selected by --service, --command, --older, --ppid or --range are
+limited to your own (the invoking user under sudo) unless --all-users is
+given, so a broad filter cannot take down other people's servers on a shared
+host. Ports and PIDs named directly, --from-file targets and --user are not
+limited.
+
+--inode targets the processes holding one socket, as shown by
+'portctl list --sockets', so a single SO_REUSEPORT listener can be stopped
+without touching the others on the same port. A socket shared by several
+processes, such as pre-forked workers, kills all of them.
+
+--service matches any service type or command name containing the text, so
+"node" also matches nodemon; --command matches the command name exactly.
+
+Killing more than kill.max-batch processes (default 10) at once requires
+--confirm-batch, even with --yes, or a second interactive confirmation.
+
+Without --verify, a kill succeeds once the signal is delivered, even if the
+process handles or ignores it. With --verify, each process must also exit
+within --verify-timeout (default 3s), otherwise kill reports it and exits 1.
+--verify only accepts signals that end a process: TERM, INT, QUIT or KILL.
+
+--restart is best-effort: it re-runs the original command line in the original
+working directory once the old process has exited, but with portctl's own
+environment and terminal. Processes started by a supervisor, with environment
+set inline, or whose command line or directory cannot be read are not restarted.
+
+--json prints {"killed": [...], "failed": [{"pid": N, "error": "..."}],
+"total": N} inside the usual "data" envelope instead of the text summary, and
+exits 1 if any process failed. It requires --yes, as the confirmation prompt
+cannot be answered by a script, and cannot be combined with --restart.`,
 	Args: func(cmd *cobra.Command, args []string) error {
 		// Allow multiple ports or no args if using filters
-		if killPID != 0 || killRange != "" || killService != "" || killUser != "" || killOlder != "" {
+		if killPID != 0 || killRange != "" || killService != "" || killCommand != "" || killUser != "" || killOlder != "" || killPPID != 0 || killFile != "" || killInode != 0 {
 			return nil
 		}
 		if len(args) == 0 {
@@ -62,9 +131,32 @@ Examples:
 }
 
 func runKill(cmd *cobra.Command, args []string) {
-	pm := process.NewProcessManager()
+	pm := newProcessManager()
 	ctx := cmd.Context()
 
+	killSig = syscall.SIGTERM
+	if killForce {
+		killSig = syscall.SIGKILL
+	}
+	if killSignal != "" {
+		sig, err := process.ParseSignal(killSignal)
+		if err != nil {
+			exitWithError(killJSON, exitCodeUsage, "Invalid --signal: %v", err)
+		}
+		killSig = sig
+	}
+
+	if killVerify && killVerifyTimeout <= 0 {
+		exitWithError(killJSON, exitCodeUsage, "--verify-timeout must be positive")
+	}
+	if killVerify && !process.IsTerminatingSignal(killSig) {
+		exitWithError(killJSON, exitCodeUsage, "--verify needs a signal that ends the process (TERM, INT, QUIT or KILL), not %s",
+			process.SignalName(killSig))
+	}
+	if killJSON && !killYes {
+		exitWithError(true, exitCodeUsage, "--json requires --yes, since a script cannot answer the confirmation prompt")
+	}
+
 	// Handle single PID kill
 	if killPID != 0 {
 		killProcessByPID(ctx, pm, killPID)
@@ -76,22 +168,45 @@ func runKill(cmd *cobra.Command, args []string) {
 	var err error
 
 	// Handle filtering options
-	if killService != "" || killUser != "" || killOlder != "" {
+	if killService != "" || killCommand != "" || killUser != "" || killOlder != "" || killPPID != 0 {
 		targetProcesses, err = getFilteredProcesses(ctx, pm)
 		if err != nil {
-			color.Red("Error filtering processes: %v", err)
-			os.Exit(1)
+			exitWithError(killJSON, exitCodeError, "Error filtering processes: %v", err)
+		}
+		// An explicit --user already says whose processes to target
+		if killUser == "" {
+			targetProcesses = scopeKillTargets(targetProcesses)
+		}
+	}
+
+	// Handle a socket named by inode
+	if killInode != 0 {
+		inodeProcesses, err := getProcessesByInode(ctx, pm, killInode)
+		if err != nil {
+			exitWithError(killJSON, exitCodeError, "Error finding socket %d: %v", killInode, err)
 		}
+		targetProcesses = append(targetProcesses, inodeProcesses...)
 	}
 
 	// Handle port range
 	if killRange != "" {
 		rangeProcesses, err := getProcessesInRange(ctx, pm, killRange)
 		if err != nil {
-			color.Red("Error parsing port range: %v", err)
-			os.Exit(1)
+			exitWithError(killJSON, exitCodeError, "Error parsing port range: %v", err)
 		}
-		targetProcesses = append(targetProcesses, rangeProcesses...)
+		targetProcesses = append(targetProcesses, scopeKillTargets(rangeProcesses)...)
+	}
+
+	// Handle targets listed in a file or stdin
+	if killFile != "" {
+		if killFile == "-" && !killYes {
+			exitWithError(killJSON, exitCodeUsage, "Reading targets from stdin requires --yes, since stdin cannot also answer the confirmation prompt")
+		}
+		fileProcesses, err := getProcessesFromFile(ctx, pm, killFile)
+		if err != nil {
+			exitWithError(killJSON, exitCodeError, "Error reading targets from %s: %v", killFile, err)
+		}
+		targetProcesses = append(targetProcesses, fileProcesses...)
 	}
 
 	// Handle individual ports
@@ -99,13 +214,12 @@ func runKill(cmd *cobra.Command, args []string) {
 		for _, portStr := range args {
 			port, err := strconv.Atoi(portStr)
 			if err != nil {
-				color.Red("Invalid port number: %s", portStr)
-				os.Exit(1)
+				exitWithError(killJSON, exitCodeUsage, "Invalid port number: %s", portStr)
 			}
 
 			processes, err := pm.GetProcessesOnPort(ctx, port)
 			if err != nil {
-				color.Red("Error getting processes on port %d: %v", port, err)
+				fmt.Fprintln(os.Stderr, color.RedString("Error getting processes on port %d: %v", port, err))
 				continue
 			}
 			targetProcesses = append(targetProcesses, processes...)
@@ -113,18 +227,43 @@ func runKill(cmd *cobra.Command, args []string) {
 	}
 
 	if len(targetProcesses) == 0 {
-		color.Yellow("No matching processes found")
+		reportNoTargets()
+		// The requested ports may be held by another user's processes
+		var hint process.PrivilegeHint
+		for _, arg := range args {
+			if port, err := strconv.Atoi(arg); err == nil {
+				portHint := pm.PrivilegeHint(ctx, nil, port)
+				hint.Level = portHint.Level
+				hint.HiddenPorts = append(hint.HiddenPorts, portHint.HiddenPorts...)
+			}
+		}
+		printPrivilegeHint(hint)
 		return
 	}
 
 	// Remove duplicates
 	targetProcesses = removeDuplicateProcesses(targetProcesses)
 
+	// Never kill portctl itself or the shell that launched it
+	if !killSelf {
+		targetProcesses = excludeSelf(targetProcesses)
+		if len(targetProcesses) == 0 {
+			reportNoTargets()
+			return
+		}
+	}
+
 	// Kill multiple processes
 	killMultipleProcesses(ctx, pm, targetProcesses)
 }
 
 func killProcessByPID(ctx context.Context, pm *process.ProcessManager, pid int) {
+	if !killSelf && isSelfPID(pid) {
+		fmt.Fprintln(os.Stderr, color.YellowString("Note: PID %d is portctl or its parent shell; use --include-self to kill it anyway", pid))
+		reportNoTargets()
+		return
+	}
+
 	if !killYes {
 		if !confirmKill(fmt.Sprintf("process with PID %d", pid)) {
 			color.Yellow("Operation cancelled")
@@ -132,21 +271,95 @@ func killProcessByPID(ctx context.Context, pm *process.ProcessManager, pid int)
 		}
 	}
 
-	color.Yellow("Killing process %d...", pid)
-	err := pm.KillProcess(ctx, pid, killForce)
+	specs := captureLaunchSpecs(ctx, pm, []int{pid})
+
+	if killSignal != "" {
+		statusf(color.Yellow, "Sending %s to process %d...", process.SignalName(killSig), pid)
+	} else {
+		statusf(color.Yellow, "Killing process %d...", pid)
+	}
+	err := pm.SignalProcess(ctx, pid, killSig)
+	if err == nil && killVerify {
+		err = pm.VerifyExited(ctx, pid, killVerifyTimeout)
+	}
+	report := newKillReport([]int{pid}, map[int]error{pid: err})
+	if killJSON {
+		writeJSON(report)
+		if len(report.Failed) > 0 {
+			os.Exit(exitCodeError)
+		}
+		return
+	}
+
 	if err != nil {
 		color.Red("Failed to kill process %d: %v", pid, err)
-		os.Exit(1)
+		if errors.Is(err, process.ErrStillRunning) && !killForce {
+			statusf(color.Yellow, "Tip: Try using --force")
+		}
+		os.Exit(exitCodeError)
 	}
 
-	color.Green("Successfully killed process %d", pid)
+	if killSignal != "" {
+		color.Green("Sent %s to process %d", process.SignalName(killSig), pid)
+	} else {
+		color.Green("Successfully killed process %d", pid)
+	}
+	restartProcesses(ctx, specs, []int{pid})
+}
+
+// killReport is the outcome of a kill, built before it is rendered as text or JSON
+type killReport struct {
+	Killed []int         `json:"killed"`
+	Failed []killFailure `json:"failed"`
+	Total  int           `json:"total"`
+}
+
+// newKillReport summarizes the per-PID results of signalling pids. It walks
+// pids rather than the map so the report order is stable.
+func newKillReport(pids []int, results map[int]error) killReport {
+	report := killReport{Killed: []int{}, Failed: []killFailure{}}
+	seen := make(map[int]bool)
+	for _, pid := range pids {
+		if seen[pid] {
+			continue
+		}
+		seen[pid] = true
+
+		if err := results[pid]; err != nil {
+			report.Failed = append(report.Failed, killFailure{PID: pid, Error: err.Error()})
+		} else {
+			report.Killed = append(report.Killed, pid)
+		}
+	}
+	report.Total = len(seen)
+	return report
+}
+
+// failedPIDs lists the PIDs of the failed processes
+func (r killReport) failedPIDs() []int {
+	pids := make([]int, len(r.Failed))
+	for i, f := range r.Failed {
+		pids[i] = f.PID
+	}
+	return pids
+}
+
+// reportNoTargets says that nothing matched; with --json that is an empty report
+func reportNoTargets() {
+	if killJSON {
+		writeJSON(newKillReport(nil, nil))
+		return
+	}
+	color.Yellow("No matching processes found")
 }
 
 func confirmKill(target string) bool {
 	reader := bufio.NewReader(os.Stdin)
 
 	var prompt string
-	if killForce {
+	if killSignal != "" {
+		prompt = color.YellowString("Are you sure you want to send %s to %s? [y/N]: ", process.SignalName(killSig), target)
+	} else if killForce {
 		prompt = color.YellowString("Are you sure you want to FORCE KILL %s? [y/N]: ", target)
 	} else {
 		prompt = color.YellowString("Are you sure you want to kill %s? [y/N]: ", target)
@@ -162,6 +375,19 @@ func confirmKill(target string) bool {
 	return response == "y" || response == "yes"
 }
 
+// confirmBatch asks the user to type the number of processes to confirm a large batch kill
+func confirmBatch(count int) bool {
+	reader := bufio.NewReader(os.Stdin)
+
+	fmt.Print(color.YellowString("Type %d to confirm: ", count))
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	return strings.TrimSpace(response) == strconv.Itoa(count)
+}
+
 func getFilteredProcesses(ctx context.Context, pm *process.ProcessManager) ([]process.Process, error) {
 	allProcesses, err := pm.GetAllProcesses(ctx)
 	if err != nil {
@@ -181,6 +407,11 @@ func getFilteredProcesses(ctx context.Context, pm *process.ProcessManager) ([]pr
 			}
 		}
 
+		// Filter by exact command name
+		if killCommand != "" && !process.MatchesCommand(proc, killCommand) {
+			match = false
+		}
+
 		// Filter by user
 		if killUser != "" {
 			if !strings.Contains(strings.ToLower(proc.User), strings.ToLower(killUser)) {
@@ -188,6 +419,11 @@ func getFilteredProcesses(ctx context.Context, pm *process.ProcessManager) ([]pr
 			}
 		}
 
+		// Filter by parent process
+		if killPPID != 0 && proc.PPID != killPPID {
+			match = false
+		}
+
 		// Filter by age
 		if killOlder != "" {
 			duration, err := time.ParseDuration(killOlder)
@@ -207,35 +443,142 @@ func getFilteredProcesses(ctx context.Context, pm *process.ProcessManager) ([]pr
 	return filtered, nil
 }
 
+// killMatchDescription explains how the name filters picked their targets,
+// so a substring --service match is not mistaken for an exact one.
+func killMatchDescription() string {
+	var parts []string
+	if killCommand != "" {
+		parts = append(parts, fmt.Sprintf("exact command name %q", killCommand))
+	}
+	if killService != "" {
+		parts = append(parts, fmt.Sprintf("service or command containing %q", killService))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " (matched by " + strings.Join(parts, " and ") + ")"
+}
+
 func getProcessesInRange(ctx context.Context, pm *process.ProcessManager, rangeStr string) ([]process.Process, error) {
-	parts := strings.Split(rangeStr, "-")
-	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid range format, use 'start-end' (e.g., '3000-3010')")
+	ports, err := process.ParsePorts(rangeStr)
+	if err != nil {
+		return nil, err
 	}
 
-	start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	wanted := make(map[int]bool, len(ports))
+	for _, port := range ports {
+		wanted[port] = true
+	}
+
+	// Enumerate once and filter rather than querying each port separately
+	all, err := pm.GetAllProcesses(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("invalid start port: %s", parts[0])
+		return nil, err
 	}
 
-	end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	var processes []process.Process
+	for _, proc := range all {
+		if wanted[proc.Port] {
+			processes = append(processes, proc)
+		}
+	}
+
+	return processes, nil
+}
+
+// getProcessesByInode returns the processes holding the socket with inode
+func getProcessesByInode(ctx context.Context, pm *process.ProcessManager, inode uint64) ([]process.Process, error) {
+	sockets, err := pm.GetSockets(ctx, 0)
 	if err != nil {
-		return nil, fmt.Errorf("invalid end port: %s", parts[1])
+		return nil, err
+	}
+
+	var processes []process.Process
+	for _, sock := range sockets {
+		if sock.Inode == inode {
+			processes = append(processes, sock)
+		}
+	}
+	return processes, nil
+}
+
+// killTargets holds the ports and PIDs listed in a targets file
+type killTargets struct {
+	ports []int
+	pids  []int
+}
+
+// parseKillTargets reads one target per line: a port, a port list or range
+// ("3000-3010"), or "pid:NNN". Blank lines and "#" comments are ignored.
+func parseKillTargets(r io.Reader) (*killTargets, error) {
+	targets := &killTargets{}
+	scanner := bufio.NewScanner(r)
+
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if pidStr, ok := strings.CutPrefix(strings.ToLower(line), "pid:"); ok {
+			pid, err := strconv.Atoi(strings.TrimSpace(pidStr))
+			if err != nil || pid <= 0 {
+				return nil, fmt.Errorf("line %d: invalid PID %q", lineNo, line)
+			}
+			targets.pids = append(targets.pids, pid)
+			continue
+		}
+
+		ports, err := process.ParsePorts(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNo, err)
+		}
+		targets.ports = append(targets.ports, ports...)
+	}
+
+	return targets, scanner.Err()
+}
+
+// getProcessesFromFile resolves the targets listed in path ("-" for stdin) to processes
+func getProcessesFromFile(ctx context.Context, pm *process.ProcessManager, path string) ([]process.Process, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = file.Close() }()
+		r = file
 	}
 
-	if start >= end {
-		return nil, fmt.Errorf("start port must be less than end port")
+	targets, err := parseKillTargets(r)
+	if err != nil {
+		return nil, err
 	}
 
 	var processes []process.Process
-	for port := start; port <= end; port++ {
+	for _, port := range targets.ports {
 		procs, err := pm.GetProcessesOnPort(ctx, port)
 		if err != nil {
-			continue // Skip errors for individual ports
+			fmt.Fprintln(os.Stderr, color.RedString("Error getting processes on port %d: %v", port, err))
+			continue
 		}
 		processes = append(processes, procs...)
 	}
 
+	for _, pid := range targets.pids {
+		proc, err := pm.GetProcessByPID(ctx, pid)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, color.YellowString("Skipping PID %d: %v", pid, err))
+			continue
+		}
+		processes = append(processes, *proc)
+	}
+
 	return processes, nil
 }
 
@@ -253,84 +596,285 @@ func removeDuplicateProcesses(processes []process.Process) []process.Process {
 	return unique
 }
 
+// isSelfPID reports whether pid is this portctl process or its parent
+func isSelfPID(pid int) bool {
+	return pid == os.Getpid() || pid == os.Getppid()
+}
+
+// excludeSelf drops portctl's own process and its parent from a kill target set
+func excludeSelf(processes []process.Process) []process.Process {
+	var kept []process.Process
+
+	for _, proc := range processes {
+		if isSelfPID(proc.PID) {
+			fmt.Fprintln(os.Stderr, color.YellowString("Note: skipping PID %d (%s), which is portctl or its parent shell; use --include-self to override",
+				proc.PID, proc.Command))
+			continue
+		}
+		kept = append(kept, proc)
+	}
+
+	return kept
+}
+
+// scopeKillTargets drops processes owned by other users unless --all-users
+// is set, noting how many were skipped
+func scopeKillTargets(processes []process.Process) []process.Process {
+	if killAll {
+		return processes
+	}
+	owned, skipped, err := scopeToCurrentUser(processes)
+	if err != nil {
+		exitWithError(killJSON, exitCodeError, "%v; use --all-users to target every user's processes", err)
+	}
+	if skipped > 0 {
+		fmt.Fprintln(os.Stderr, color.YellowString("Note: skipped %d process(es) owned by other users; use --all-users to include them", skipped))
+	}
+	return owned
+}
+
+// scopeToCurrentUser keeps the processes owned by the invoking user and
+// reports how many belonged to someone else
+func scopeToCurrentUser(processes []process.Process) ([]process.Process, int, error) {
+	username, err := process.CurrentUsername()
+	if err != nil {
+		return nil, 0, err
+	}
+	owned, others := process.FilterByOwner(processes, username)
+	return owned, len(others), nil
+}
+
+// exceedsBatchLimit returns the kill.max-batch limit and whether killing
+// count processes goes over it without --confirm-batch. Every command that
+// kills a computed set of processes, including the quick actions, checks it.
+func exceedsBatchLimit(count int, confirmed bool) (limit int, over bool) {
+	limit = viper.GetInt("kill.max-batch")
+	return limit, limit > 0 && count > limit && !confirmed
+}
+
 func killMultipleProcesses(ctx context.Context, pm *process.ProcessManager, processes []process.Process) {
 	if len(processes) == 0 {
-		color.Yellow("No processes to kill")
+		reportNoTargets()
 		return
 	}
 
-	// Show what will be killed
-	color.Cyan("Found %d process(es) to kill:", len(processes))
-	for i, proc := range processes {
-		uptime := ""
-		if !proc.StartTime.IsZero() {
-			uptime = fmt.Sprintf(" (uptime: %s)", time.Since(proc.StartTime).Round(time.Second))
-		}
-		fmt.Printf("  %d. PID %d: %s on port %d [%s]%s\n",
-			i+1, proc.PID, proc.Command, proc.Port, proc.ServiceType, uptime)
+	// Show what will be killed; with --json the report lists the PIDs
+	if !killJSON {
+		printKillTargets(ctx, pm, processes)
 	}
-	fmt.Println()
 
-	if !killYes {
-		prompt := fmt.Sprintf("Are you sure you want to kill %d process(es)?", len(processes))
-		if killForce {
-			prompt = fmt.Sprintf("Are you sure you want to FORCE KILL %d process(es)?", len(processes))
-		}
+	// Guard against unexpectedly large batches
+	maxBatch, overBatch := exceedsBatchLimit(len(processes), killBatchOK)
+	if overBatch && killYes {
+		statusf(color.Yellow, "Tip: Re-run with --confirm-batch to allow large batch kills")
+		exitWithError(killJSON, exitCodeUsage, "Refusing to kill %d processes: exceeds kill.max-batch limit of %d", len(processes), maxBatch)
+	}
 
-		if !confirmKill(prompt) {
+	if !killYes {
+		if !confirmKill(fmt.Sprintf("%d process(es)", len(processes))) {
 			color.Yellow("Operation cancelled")
 			return
 		}
+
+		if overBatch {
+			color.Red("⚠️  This will kill %d processes, more than the kill.max-batch limit of %d.", len(processes), maxBatch)
+			if !confirmBatch(len(processes)) {
+				color.Yellow("Operation cancelled")
+				return
+			}
+		}
 	}
 
 	// Kill processes
-	color.Yellow("Killing %d process(es)...", len(processes))
+	if killSignal != "" {
+		statusf(color.Yellow, "Sending %s to %d process(es)...", process.SignalName(killSig), len(processes))
+	} else {
+		statusf(color.Yellow, "Killing %d process(es)...", len(processes))
+	}
 
 	pids := make([]int, len(processes))
 	for i, proc := range processes {
 		pids[i] = proc.PID
 	}
 
-	results := pm.KillProcesses(ctx, pids, killForce)
+	specs := captureLaunchSpecs(ctx, pm, pids)
+	var results map[int]error
+	if killVerify {
+		results = pm.SignalProcessesVerified(ctx, pids, killSig, killVerifyTimeout)
+	} else {
+		results = pm.SignalProcesses(ctx, pids, killSig)
+	}
 
 	// Report results
-	var succeeded, failed []int
-	for pid, err := range results {
-		if err == nil {
-			succeeded = append(succeeded, pid)
-		} else {
-			failed = append(failed, pid)
-			color.Red("  Failed to kill PID %d: %v", pid, err)
+	report := newKillReport(pids, results)
+	if killJSON {
+		writeJSON(report)
+		if len(report.Failed) > 0 {
+			os.Exit(exitCodeError)
 		}
+		return
+	}
+
+	for _, f := range report.Failed {
+		color.Red("  Failed to kill PID %d: %s", f.PID, f.Error)
 	}
 
 	// Summary
-	if len(succeeded) > 0 {
-		color.Green("✅ Successfully killed %d process(es): %v", len(succeeded), succeeded)
+	if len(report.Killed) > 0 {
+		color.Green("✅ Successfully killed %d process(es): %v", len(report.Killed), report.Killed)
+		restartProcesses(ctx, specs, report.Killed)
+	}
+
+	if len(report.Failed) > 0 {
+		color.Red("❌ Failed to kill %d process(es): %v", len(report.Failed), report.failedPIDs())
+		statusf(color.Yellow, "Tip: Try using --force or run with elevated privileges")
+		os.Exit(exitCodeError)
+	}
+}
+
+// printKillTargets lists the processes about to be killed with their resource
+// use and, with --details, how many children each would leave behind
+func printKillTargets(ctx context.Context, pm *process.ProcessManager, processes []process.Process) {
+	var children map[int][]int
+	if killDetails {
+		pids := make([]int, len(processes))
+		for i, proc := range processes {
+			pids[i] = proc.PID
+		}
+		var err error
+		if children, err = pm.GetChildren(ctx, pids...); err != nil {
+			fmt.Fprintln(os.Stderr, color.YellowString("⚠️  Could not count child processes: %v", err))
+		}
+	}
+
+	statusf(color.Cyan, "Found %d process(es) to kill%s:", len(processes), killMatchDescription())
+	for i, proc := range processes {
+		uptime := ""
+		if !proc.StartTime.IsZero() {
+			uptime = fmt.Sprintf(" (uptime: %s)", process.FormatSince(proc.StartTime))
+		}
+		fmt.Printf("  %d. PID %d: %s on port %d [%s]%s\n",
+			i+1, proc.PID, proc.Command, proc.Port, proc.ServiceType, uptime)
+
+		impact := fmt.Sprintf("CPU %.1f%%, memory %s", proc.CPUPercent, process.FormatMemory(float64(proc.MemoryMB)))
+		if children != nil {
+			if kids := children[proc.PID]; len(kids) > 0 {
+				impact += color.YellowString(", %d child process(es) may be orphaned", len(kids))
+			} else {
+				impact += ", no child processes"
+			}
+		}
+		fmt.Printf("     %s\n", impact)
 	}
+	statusf(printfln, "")
+}
+
+// restartExitTimeout bounds how long --restart waits for a killed process to exit
+const restartExitTimeout = 5 * time.Second
 
-	if len(failed) > 0 {
-		color.Red("❌ Failed to kill %d process(es): %v", len(failed), failed)
-		color.Yellow("Tip: Try using --force or run with elevated privileges")
-		os.Exit(1)
+// captureLaunchSpecs records how each process was started when --restart is set.
+// It must run before the kill, while the command line and directory are readable.
+func captureLaunchSpecs(ctx context.Context, pm *process.ProcessManager, pids []int) map[int]*process.LaunchSpec {
+	if !killRestart {
+		return nil
+	}
+
+	specs := make(map[int]*process.LaunchSpec)
+	for _, pid := range pids {
+		spec, err := pm.CaptureLaunchSpec(ctx, pid)
+		if err != nil {
+			color.Yellow("⚠️  PID %d will not be restarted: %v", pid, err)
+			continue
+		}
+		specs[pid] = spec
+	}
+	return specs
+}
+
+// restartProcesses re-launches the captured commands of the killed PIDs once
+// they have exited. Identical command lines are started only once.
+func restartProcesses(ctx context.Context, specs map[int]*process.LaunchSpec, killed []int) {
+	started := make(map[string]bool)
+
+	for _, pid := range killed {
+		spec, ok := specs[pid]
+		if !ok {
+			continue
+		}
+		key := spec.Dir + "\x00" + spec.String()
+		if started[key] {
+			continue
+		}
+
+		waitCtx, cancel := context.WithTimeout(ctx, restartExitTimeout)
+		err := process.WaitForExit(waitCtx, pid, 100*time.Millisecond)
+		cancel()
+		if err != nil {
+			color.Red("❌ Not restarting PID %d: %v (try --force)", pid, err)
+			continue
+		}
+
+		proc, err := spec.Start()
+		if err != nil {
+			color.Red("❌ Failed to restart %s: %v", spec, err)
+			continue
+		}
+		started[key] = true
+
+		color.Green("🔄 Restarted PID %d as PID %d: %s", pid, proc.Pid, spec)
+		if spec.Dir != "" {
+			fmt.Printf("   in %s\n", spec.Dir)
+		}
+		_ = proc.Release()
 	}
 }
 
 func init() {
 	rootCmd.AddCommand(killCmd)
 
+	killCmd.Flags().Uint64Var(&killInode, "inode", 0,
+		"Kill the processes holding the socket with this inode (see list --sockets, Linux)")
 	killCmd.Flags().IntVarP(&killPID, "pid", "p", 0,
 		"Kill process by PID instead of port")
 	killCmd.Flags().BoolVarP(&killForce, "force", "f", false,
 		"Force kill (SIGKILL on Unix, /F on Windows)")
+	killCmd.Flags().StringVar(&killSignal, "signal", "",
+		"Signal to send instead of SIGTERM, by name or number (e.g. HUP, USR1, 9)")
 	killCmd.Flags().BoolVarP(&killYes, "yes", "y", false,
 		"Skip confirmation prompt")
 	killCmd.Flags().StringVarP(&killRange, "range", "r", "",
-		"Kill processes in port range (e.g., '3000-3010')")
+		"Kill processes in port range (e.g., '3000-3010' or '3000,8080-8090')")
+	killCmd.Flags().BoolVar(&killRestart, "restart", false,
+		"Re-launch each killed command in its original directory (best-effort)")
+	killCmd.Flags().StringVar(&killFile, "from-file", "",
+		"Read target ports and pid:NNN entries from a file, or '-' for stdin")
 	killCmd.Flags().StringVarP(&killService, "service", "s", "",
-		"Kill processes by service type or command name")
+		"Kill processes whose service type or command name contains this text")
+	killCmd.Flags().StringVar(&killCommand, "command", "",
+		"Kill processes whose command name is exactly this (e.g. 'node', not 'nodemon')")
 	killCmd.Flags().StringVarP(&killUser, "user", "u", "",
 		"Kill processes owned by specific user")
 	killCmd.Flags().StringVar(&killOlder, "older", "",
 		"Kill processes older than duration (e.g., '1h', '30m', '2h30m')")
+	killCmd.Flags().IntVar(&killPPID, "ppid", 0,
+		"Kill processes whose parent has this PID, e.g. servers started by one build")
+	killCmd.Flags().BoolVar(&killBatchOK, "confirm-batch", false,
+		"Allow killing more processes than the kill.max-batch limit")
+	killCmd.Flags().BoolVar(&killVerify, "verify", false,
+		"Only report success once each process has exited, not just been signalled")
+	killCmd.Flags().DurationVar(&killVerifyTimeout, "verify-timeout", 3*time.Second,
+		"How long --verify waits for each process to exit")
+	killCmd.Flags().BoolVar(&killAll, "all-users", false,
+		"Let filters and ranges match other users' processes, not just your own")
+	killCmd.Flags().BoolVar(&killSelf, "include-self", false,
+		"Allow killing portctl's own process and its parent shell")
+	killCmd.Flags().BoolVarP(&killDetails, "details", "d", false,
+		"Show how many child processes each target has before confirming (slower)")
+
+	killCmd.MarkFlagsMutuallyExclusive("force", "signal")
+	killCmd.Flags().BoolVarP(&killJSON, "json", "j", false,
+		"Output the killed and failed PIDs in JSON format (requires --yes)")
+
+	killCmd.MarkFlagsMutuallyExclusive("restart", "signal")
+	killCmd.MarkFlagsMutuallyExclusive("restart", "json")
 }
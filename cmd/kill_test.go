@@ -0,0 +1,184 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+func TestNewKillReport(t *testing.T) {
+	results := map[int]error{
+		30: nil,
+		10: errors.New("operation not permitted"),
+		20: nil,
+	}
+	report := newKillReport([]int{30, 10, 20, 30}, results)
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"killed":[30,20],"failed":[{"pid":10,"error":"operation not permitted"}],"total":3}`
+	if string(data) != want {
+		t.Errorf("report = %s, want %s", data, want)
+	}
+}
+
+func TestNewKillReportEmpty(t *testing.T) {
+	data, err := json.Marshal(newKillReport(nil, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"killed":[],"failed":[],"total":0}`; string(data) != want {
+		t.Errorf("report = %s, want %s", data, want)
+	}
+}
+
+func TestExceedsBatchLimit(t *testing.T) {
+	viper.Set("kill.max-batch", 2)
+	t.Cleanup(viper.Reset)
+
+	tests := []struct {
+		count     int
+		confirmed bool
+		want      bool
+	}{
+		{2, false, false},
+		{3, false, true},
+		{3, true, false},
+	}
+	for _, tt := range tests {
+		if _, got := exceedsBatchLimit(tt.count, tt.confirmed); got != tt.want {
+			t.Errorf("exceedsBatchLimit(%d, %v) = %v, want %v", tt.count, tt.confirmed, got, tt.want)
+		}
+	}
+
+	viper.Set("kill.max-batch", 0)
+	if _, over := exceedsBatchLimit(1000, false); over {
+		t.Error("Expected kill.max-batch 0 to disable the limit")
+	}
+}
+
+// TestListenHelper holds a listener on the port in PORTCTL_TEST_LISTEN when
+// re-executed by startListener; otherwise it does nothing
+func TestListenHelper(t *testing.T) {
+	port := os.Getenv("PORTCTL_TEST_LISTEN")
+	if port == "" {
+		return
+	}
+	ln, err := net.Listen("tcp", "127.0.0.1:"+port)
+	if err != nil {
+		os.Exit(1)
+	}
+	defer func() { _ = ln.Close() }()
+	time.Sleep(30 * time.Second)
+	os.Exit(0)
+}
+
+// startListener runs TestListenHelper in a child process holding port and
+// waits until it accepts connections
+func startListener(t *testing.T, port int) {
+	t.Helper()
+	holder := exec.Command(os.Args[0], "-test.run=^TestListenHelper$")
+	holder.Env = append(os.Environ(), "PORTCTL_TEST_LISTEN="+strconv.Itoa(port))
+	if err := holder.Start(); err != nil {
+		t.Skipf("cannot start listener process: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = holder.Process.Kill()
+		_ = holder.Wait()
+	})
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if conn, err := net.Dial("tcp", "127.0.0.1:"+strconv.Itoa(port)); err == nil {
+			_ = conn.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Skip("listener process did not start")
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// consecutiveFreePorts finds two adjacent free ports, so dev.ports can cover
+// exactly them and no other process
+func consecutiveFreePorts(t *testing.T) (int, int) {
+	t.Helper()
+	for i := 0; i < 20; i++ {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Skipf("cannot listen: %v", err)
+		}
+		port := ln.Addr().(*net.TCPAddr).Port
+		next, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port+1))
+		_ = ln.Close()
+		if err == nil {
+			_ = next.Close()
+			return port, port + 1
+		}
+	}
+	t.Skip("no two adjacent free ports found")
+	return 0, 0
+}
+
+func TestQuickRefusesOverCapBatch(t *testing.T) {
+	first, second := consecutiveFreePorts(t)
+
+	// The listeners live in child processes, so a broken guard kills only them
+	startListener(t, first)
+	startListener(t, second)
+
+	if runPortctl(t, "check", strconv.Itoa(first)) != 0 {
+		t.Skip("the listener process is not visible to port enumeration here")
+	}
+
+	t.Setenv("PORTCTL_DEV_PORTS", strconv.Itoa(first)+"-"+strconv.Itoa(second))
+	t.Setenv("PORTCTL_KILL_MAX_BATCH", "1")
+	if got := runPortctl(t, "quick", "kill-dev", "--json"); got != exitCodeUsage {
+		t.Fatalf("quick kill-dev over kill.max-batch exited %d, want %d", got, exitCodeUsage)
+	}
+	for _, port := range []int{first, second} {
+		conn, err := net.Dial("tcp", "127.0.0.1:"+strconv.Itoa(port))
+		if err != nil {
+			t.Fatalf("quick kill-dev killed the over-cap batch: port %d: %v", port, err)
+		}
+		_ = conn.Close()
+	}
+}
+
+func TestQuickKillSkipsParent(t *testing.T) {
+	// The child portctl is started by this test process, which holds the dev port
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := strconv.Itoa(ln.Addr().(*net.TCPAddr).Port)
+
+	if runPortctl(t, "check", port) != 0 {
+		t.Skip("the listener is not visible to port enumeration here")
+	}
+
+	t.Setenv("PORTCTL_DEV_PORTS", port+"-"+port)
+	if got := runPortctl(t, "quick", "kill-dev", "--json"); got != 0 {
+		t.Fatalf("quick kill-dev exited %d, want 0", got)
+	}
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatalf("quick kill-dev signalled its parent: %v", err)
+	}
+	_ = conn.Close()
+}
+
+func TestKillInvalidPortIsUsageError(t *testing.T) {
+	if got := runPortctl(t, "kill", "not-a-port"); got != exitCodeUsage {
+		t.Errorf("kill with an invalid port exited %d, want %d", got, exitCodeUsage)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -16,17 +17,51 @@ import (
 )
 
 var (
-	listJSON     bool
-	listAll      bool
-	listService  string
-	listUser     string
-	listSort     string
-	listTree     bool
-	listDetails  bool
-	listMemLimit float64
-	listCPULimit float64
+	listJSON           bool
+	listAll            bool
+	listService        string
+	listExclude        string
+	listUser           string
+	listSort           string
+	listOrder          string
+	listTree           bool
+	listDetails        bool
+	listMemLimit       float64
+	listCPULimit       float64
+	listBind           string
+	listInterface      string
+	listPPID           int
+	listFDLimit        int
+	listFast           bool
+	listPIDs           bool
+	listPorts          bool
+	listColumns        string
+	listResolve        bool
+	listASN            bool
+	listResolveTimeout time.Duration
+	listWide           bool
+	listMaxWidth       int
+	listConflicts      bool
+	listGroupBy        string
+	listSockets        bool
 )
 
+// listColumn is an optional column that --columns can add to the list table
+type listColumn struct {
+	name   string
+	header string
+	align  text.Align
+	value  func(process.Process) interface{}
+}
+
+// listExtraColumns are the columns --columns accepts, in display order
+var listExtraColumns = []listColumn{
+	{name: "nice", header: "Nice", align: text.AlignRight, value: func(p process.Process) interface{} { return p.Nice }},
+	{name: "ppid", header: "PPID", align: text.AlignRight, value: func(p process.Process) interface{} { return p.PPID }},
+	{name: "fds", header: "FDs", align: text.AlignRight, value: func(p process.Process) interface{} { return formatFDs(p) }},
+	{name: "remote", header: "Remote", align: text.AlignLeft, value: func(p process.Process) interface{} { return formatRemote(p) }},
+}
+
 var listCmd = &cobra.Command{
 	Use:   "list [port]",
 	Short: "List processes running on specific ports with advanced filtering",
@@ -40,117 +75,629 @@ Examples:
   
   # Filtering
   portctl list --service node    # Filter by service type
+  portctl list --exclude-service chrome  # Hide matching services
   portctl list --user john       # Filter by user
   portctl list --mem-limit 100   # Show processes using >100MB memory
   portctl list --cpu-limit 50    # Show processes using >50% CPU
+  portctl list --fd-limit 1000   # Show processes with >1000 open file descriptors
+  portctl list --bind-scope all  # Show listeners reachable from any interface
+  portctl list --interface eth0  # Show sockets reachable through eth0
+  portctl list --ppid 4242       # Show children of PID 4242 (e.g. a build or shell)
   
   # Output options
   portctl list --json            # Output in JSON format
   portctl list --details         # Show detailed information
-  portctl list --sort port       # Sort by port (port, pid, cpu, memory, command)
-  portctl list --tree            # Show process relationships`,
+  portctl list --markdown        # Markdown table for GitHub issues and docs
+  portctl list --sort port       # Sort by port (port, pid, cpu, memory, command, service, user)
+  portctl list --sort service,port     # Sort by service, then port
+  portctl list --sort cpu --sort-order asc  # Least CPU first
+  portctl list --tree            # Show process relationships
+  portctl list --group-by user   # Group by service, user, protocol or bind-scope
+  portctl list --columns nice,ppid  # Add optional columns to the table
+  portctl list --wide            # One table with every field, long values truncated
+  portctl list --wide --max-width 0  # ...without truncation
+  portctl list --conflicts       # Ports with more than one listener or owning PID
+  portctl list 8080 --sockets    # One row per socket with its inode (Linux), see kill --inode
+  portctl list --resolve         # Show host names of connected peers
+  portctl list --resolve-asn     # ...and the network (AS) that owns each peer
+  portctl list 8080 --pids-only | xargs kill   # Bare PIDs for shell pipelines
+  portctl list --service node --ports-only     # Bare port numbers
+
+  # Performance
+  portctl list --fast            # Skip CPU/memory/user lookups on busy hosts
+
+--sockets reads /proc directly and lists every socket separately with its
+inode, so processes sharing a port through SO_REUSEPORT, or several sockets in
+one process, can be told apart and one of them killed with
+'portctl kill --inode N'. A socket inherited by several processes, such as
+pre-forked workers, is listed once per process. Linux only.`,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runList,
 }
 
 func runList(cmd *cobra.Command, args []string) {
-	pm := process.NewProcessManager()
+	if listPIDs && listPorts {
+		exitWithError(listJSON, exitCodeUsage, "--pids-only cannot be combined with --ports-only")
+	}
+	if (listPIDs || listPorts) && (listJSON || listTree || listDetails) {
+		exitWithError(listJSON, exitCodeUsage, "--pids-only and --ports-only cannot be combined with --json, --tree or --details")
+	}
+
+	if listFast && (listUser != "" || listMemLimit > 0 || listCPULimit > 0 || listPPID != 0 || listFDLimit > 0) {
+		exitWithError(listJSON, exitCodeUsage, "--fast cannot be combined with --user, --ppid, --mem-limit, --cpu-limit or --fd-limit")
+	}
+
+	columns, err := parseListColumns(listColumns)
+	if err != nil {
+		exitWithError(listJSON, exitCodeUsage, "%v", err)
+	}
+	if listFast && len(columns) > 0 {
+		exitWithError(listJSON, exitCodeUsage, "--fast cannot be combined with --columns")
+	}
+	if listWide && (listFast || listDetails || listTree) {
+		exitWithError(listJSON, exitCodeUsage, "--wide cannot be combined with --fast, --details or --tree")
+	}
+	if listConflicts && (listDetails || listTree || listWide || listPIDs || listPorts) {
+		exitWithError(listJSON, exitCodeUsage, "--conflicts cannot be combined with --details, --tree, --wide, --pids-only or --ports-only")
+	}
+	// --json keeps its flat output with --tree, but not with an explicit --group-by
+	groupJSON := listGroupBy != ""
+	if listTree && listGroupBy == "" {
+		listGroupBy = "service"
+	}
+	if listGroupBy != "" {
+		if listDetails || listWide || listConflicts || listPIDs || listPorts {
+			exitWithError(listJSON, exitCodeUsage, "--group-by and --tree cannot be combined with --details, --wide, --conflicts, --pids-only or --ports-only")
+		}
+		if _, err := process.GroupProcesses(nil, listGroupBy); err != nil {
+			exitWithError(listJSON, exitCodeUsage, "%v", err)
+		}
+	}
+	if tableMarkdown {
+		if listJSON || listDetails || listGroupBy != "" || listConflicts || listPIDs || listPorts {
+			exitWithError(listJSON, exitCodeUsage, "--markdown cannot be combined with --json, --details, --tree, --group-by, --conflicts, --pids-only or --ports-only")
+		}
+		disableColors()
+	}
+	if listMaxWidth < 0 {
+		exitWithError(listJSON, exitCodeUsage, "--max-width must be 0 (no limit) or more")
+	}
+	if listSockets && (listDetails || listTree || listGroupBy != "" || listWide || listConflicts || len(columns) > 0) {
+		exitWithError(listJSON, exitCodeUsage, "--sockets cannot be combined with --details, --tree, --group-by, --wide, --conflicts or --columns")
+	}
+
+	if listASN {
+		listResolve = true
+	}
+	if listResolve && !listFast && !listDetails && !listJSON && !listWide && !hasListColumn(columns, "remote") {
+		remote, _ := parseListColumns("remote")
+		columns = append(columns, remote...)
+	}
+
+	pm := newProcessManager().WithMetrics(!listFast)
 	ctx := cmd.Context()
 
+	sortKeys, err := process.ParseSortKeys(listSort, listOrder)
+	if err != nil {
+		exitWithError(listJSON, exitCodeUsage, "%v", err)
+	}
+
+	if listBind != "" && !process.IsValidBindScope(listBind) {
+		exitWithError(listJSON, exitCodeUsage, "Invalid bind scope %q (must be one of: %s)",
+			listBind, strings.Join(process.BindScopes, ", "))
+	}
+
+	var ifaceAddrs []net.IP
+	if listInterface != "" {
+		ifaceAddrs, err = process.InterfaceAddrs(listInterface)
+		if err != nil {
+			exitWithError(listJSON, exitCodeUsage, "%v", err)
+		}
+	}
+
 	var processes []process.Process
-	var err error
+	port := 0
 
-	if len(args) == 0 || listAll {
+	if listSockets {
+		if len(args) > 0 && !listAll {
+			port, err = strconv.Atoi(args[0])
+			if err != nil {
+				exitWithError(listJSON, exitCodeUsage, "Invalid port number: %s", args[0])
+			}
+		}
+		processes, err = pm.GetSockets(ctx, port)
+		if err != nil {
+			exitWithError(listJSON, exitCodeError, "Error listing sockets: %v", err)
+		}
+	} else if len(args) == 0 || listAll {
 		// List all processes
 		processes, err = pm.GetAllProcesses(ctx)
 		if err != nil {
-			color.Red("Error getting processes: %v", err)
-			os.Exit(1)
+			exitWithError(listJSON, exitCodeError, "Error getting processes: %v", err)
 		}
 	} else {
 		// List processes on specific port
-		port, err := strconv.Atoi(args[0])
+		port, err = strconv.Atoi(args[0])
 		if err != nil {
-			color.Red("Invalid port number: %s", args[0])
-			os.Exit(1)
+			exitWithError(listJSON, exitCodeUsage, "Invalid port number: %s", args[0])
 		}
 
 		processes, err = pm.GetProcessesOnPort(ctx, port)
 		if err != nil {
-			color.Red("Error getting processes on port %d: %v", port, err)
-			os.Exit(1)
+			exitWithError(listJSON, exitCodeError, "Error getting processes on port %d: %v", port, err)
 		}
 	}
 
+	// Check for listeners hidden by missing privileges before filtering
+	hint := pm.PrivilegeHint(ctx, processes, port)
+
 	// Apply filters
 	filterOpts := process.FilterOptions{
-		Service:     listService,
-		User:        listUser,
-		MemoryLimit: listMemLimit,
-		CPULimit:    listCPULimit,
+		Service:        listService,
+		ExcludeService: listExclude,
+		User:           listUser,
+		MemoryLimit:    listMemLimit,
+		CPULimit:       listCPULimit,
+		BindScope:      listBind,
+		Addrs:          ifaceAddrs,
+		PPID:           listPPID,
+		FDLimit:        listFDLimit,
 	}
 	processes = pm.FilterProcesses(processes, filterOpts)
 
 	// Apply sorting
-	processes = pm.SortProcesses(processes, listSort)
+	processes = pm.SortProcesses(processes, sortKeys)
+
+	if listResolve {
+		process.NewResolver(listResolveTimeout).WithASN(listASN).ResolveProcesses(ctx, processes)
+	}
+
+	if listConflicts {
+		outputConflicts(process.FindPortConflicts(processes))
+		printPrivilegeHint(hint)
+		return
+	}
+
+	if listJSON {
+		if groupJSON {
+			groups, _ := process.GroupProcesses(processes, listGroupBy)
+			if groups == nil {
+				groups = []process.ProcessGroup{}
+			}
+			writeJSON(groups)
+			return
+		}
+		outputJSON(processes)
+		return
+	}
+
+	if listPIDs || listPorts {
+		outputValues(processes, listPorts)
+		return
+	}
 
 	if len(processes) == 0 {
 		if len(args) > 0 {
-			color.Yellow("No processes found on port %s matching filters", args[0])
+			statusf(color.Yellow, "No processes found on port %s matching filters", args[0])
 		} else {
-			color.Yellow("No processes found matching filters")
+			statusf(color.Yellow, "No processes found matching filters")
 		}
+		printPrivilegeHint(hint)
 		return
 	}
 
-	if listJSON {
-		outputJSON(processes)
+	if listSockets {
+		outputSocketsTable(processes)
 	} else if listDetails {
 		outputDetailed(processes)
-	} else if listTree {
-		outputTree(processes)
+	} else if listGroupBy != "" {
+		outputGroups(processes, listGroupBy)
+	} else if listFast {
+		outputFastTable(processes)
+	} else if listWide {
+		outputWideTable(processes, columns, listMaxWidth)
 	} else {
-		outputTable(processes)
+		outputTable(processes, columns)
+	}
+	printFDWarnings(processes)
+	printPrivilegeHint(hint)
+}
+
+// formatFDs shows open file descriptors as "120/1024", highlighted when near
+// the soft limit, or "-" when they could not be read
+func formatFDs(proc process.Process) string {
+	if proc.OpenFDs == 0 {
+		return "-"
+	}
+	if proc.FDLimit == 0 {
+		return strconv.Itoa(proc.OpenFDs)
+	}
+	s := fmt.Sprintf("%d/%d", proc.OpenFDs, proc.FDLimit)
+	if process.NearFDLimit(proc) {
+		return text.Colors{text.FgHiRed, text.Bold}.Sprint(s)
+	}
+	return s
+}
+
+// printFDWarnings notes each process close to its file descriptor limit,
+// which will soon fail to accept connections or open files
+func printFDWarnings(processes []process.Process) {
+	seen := make(map[int]bool)
+	for _, proc := range processes {
+		if seen[proc.PID] || !process.NearFDLimit(proc) {
+			continue
+		}
+		seen[proc.PID] = true
+		statusf(color.Yellow, "⚠️  PID %d (%s) has %d of %d file descriptors open",
+			proc.PID, proc.Command, proc.OpenFDs, proc.FDLimit)
+	}
+}
+
+// formatRemote shows a remote address with its resolved host and owner, if known
+func formatRemote(proc process.Process) string {
+	remote := proc.RemoteAddr
+	if proc.RemoteHost != "" {
+		remote += " (" + proc.RemoteHost + ")"
+	}
+	if proc.RemoteOwner != "" {
+		remote += " [" + proc.RemoteOwner + "]"
+	}
+	return remote
+}
+
+// hasListColumn reports whether columns includes the named column
+func hasListColumn(columns []listColumn, name string) bool {
+	for _, column := range columns {
+		if column.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseListColumns resolves a comma-separated --columns value
+func parseListColumns(spec string) ([]listColumn, error) {
+	var columns []listColumn
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, column := range listExtraColumns {
+			if column.name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			names := make([]string, len(listExtraColumns))
+			for i, column := range listExtraColumns {
+				names[i] = column.name
+			}
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(names, ", "))
+		}
 	}
+	return columns, nil
 }
 
-func outputTable(processes []process.Process) {
+func outputTable(processes []process.Process, extra []listColumn) {
 	t := tablepretty.NewWriter()
 	t.SetOutputMirror(os.Stdout)
 	t.SetStyle(tablepretty.StyleColoredBright)
 
 	// Set header and header color
-	t.AppendHeader(tablepretty.Row{"PID", "Port", "Protocol", "Service", "Command", "CPU%", "Mem(MB)", "User"})
+	header := tablepretty.Row{"PID", "Port", "Protocol", "Bind", "Service", "Command", "CPU%", "Memory", "User"}
+	for _, column := range extra {
+		header = append(header, column.header)
+	}
+	appendTableHeader(t, header)
 	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
 
 	// Set column configs for alignment and color
+	configs := []tablepretty.ColumnConfig{
+		{Number: 1, Align: text.AlignRight},                                              // PID
+		{Number: 2, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}}, // Port
+		{Number: 3, Align: text.AlignCenter},                                             // Protocol
+		{Number: 4, Align: text.AlignCenter},                                             // Bind
+		{Number: 5, Align: text.AlignCenter},                                             // Service
+		{Number: 6, Align: text.AlignLeft},                                               // Command
+		{Number: 7, Align: text.AlignRight},                                              // CPU%
+		{Number: 8, Align: text.AlignRight},                                              // Memory
+		{Number: 9, Align: text.AlignLeft},                                               // User
+	}
+	for i, column := range extra {
+		configs = append(configs, tablepretty.ColumnConfig{Number: len(header) - len(extra) + i + 1, Align: column.align})
+	}
+	t.SetColumnConfigs(configs)
+
+	cpuWarn, memWarn := usageThresholds()
+	for _, proc := range processes {
+		row := tablepretty.Row{
+			proc.PID,
+			proc.Port,
+			proc.Protocol,
+			proc.BindScope,
+			proc.ServiceType,
+			proc.Command,
+			usageCell(fmt.Sprintf("%.1f", proc.CPUPercent), proc.CPUPercent, cpuWarn),
+			usageCell(process.FormatMemory(float64(proc.MemoryMB)), float64(proc.MemoryMB), memWarn),
+			proc.User,
+		}
+		for _, column := range extra {
+			row = append(row, column.value(proc))
+		}
+		t.AppendRow(row)
+	}
+
+	renderTable(t)
+	tableStatusf(color.Green, "\nFound %d process(es)", len(processes))
+}
+
+// usageColors maps a usage grade to the colors of its table cell
+func usageColors(level process.UsageLevel) text.Colors {
+	switch level {
+	case process.UsageOK:
+		return text.Colors{text.FgGreen}
+	case process.UsageWarn:
+		return text.Colors{text.FgYellow}
+	case process.UsageCritical:
+		return text.Colors{text.FgHiRed, text.Bold}
+	}
+	return nil
+}
+
+// usageCell colors a CPU or memory cell by how value compares with warn
+func usageCell(s string, value, warn float64) string {
+	colors := usageColors(process.ClassifyUsage(value, warn))
+	if colors == nil {
+		return s
+	}
+	return colors.Sprint(s)
+}
+
+// outputFastTable renders only the fields parsed from lsof/netstat
+func outputFastTable(processes []process.Process) {
+	t := tablepretty.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tablepretty.StyleColoredBright)
+
+	appendTableHeader(t, tablepretty.Row{"PID", "Port", "Protocol", "Bind", "Service", "Command", "Local Addr", "Remote Addr"})
+	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
+
 	t.SetColumnConfigs([]tablepretty.ColumnConfig{
 		{Number: 1, Align: text.AlignRight},                                              // PID
 		{Number: 2, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}}, // Port
 		{Number: 3, Align: text.AlignCenter},                                             // Protocol
-		{Number: 4, Align: text.AlignCenter},                                             // Service
-		{Number: 5, Align: text.AlignLeft},                                               // Command
-		{Number: 6, Align: text.AlignRight},                                              // CPU%
-		{Number: 7, Align: text.AlignRight},                                              // Mem(MB)
-		{Number: 8, Align: text.AlignLeft},                                               // User
+		{Number: 4, Align: text.AlignCenter},                                             // Bind
+		{Number: 5, Align: text.AlignCenter},                                             // Service
 	})
 
 	for _, proc := range processes {
-		row := tablepretty.Row{
+		t.AppendRow(tablepretty.Row{
 			proc.PID,
 			proc.Port,
 			proc.Protocol,
+			proc.BindScope,
 			proc.ServiceType,
 			proc.Command,
-			fmt.Sprintf("%.1f", proc.CPUPercent),
-			fmt.Sprintf("%.1f", proc.MemoryMB),
+			proc.LocalAddr,
+			formatRemote(proc),
+		})
+	}
+
+	renderTable(t)
+	tableStatusf(color.Green, "\nFound %d process(es)", len(processes))
+}
+
+// outputSocketsTable renders one row per socket and notes the ports where
+// several sockets listen, which kill --inode can target individually
+func outputSocketsTable(processes []process.Process) {
+	t := tablepretty.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tablepretty.StyleColoredBright)
+
+	appendTableHeader(t, tablepretty.Row{"Inode", "PID", "Port", "Protocol", "State", "Local Addr", "Command", "User"})
+	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
+
+	t.SetColumnConfigs([]tablepretty.ColumnConfig{
+		{Number: 1, Align: text.AlignRight, Colors: text.Colors{text.FgYellow}},          // Inode
+		{Number: 2, Align: text.AlignRight},                                              // PID
+		{Number: 3, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}}, // Port
+		{Number: 4, Align: text.AlignCenter},                                             // Protocol
+		{Number: 5, Align: text.AlignCenter},                                             // State
+	})
+
+	// Listening sockets per port and protocol, to point out SO_REUSEPORT groups
+	type portKey struct {
+		port     int
+		protocol string
+	}
+	listeners := make(map[portKey]map[uint64]bool)
+	var shared []portKey
+	for _, proc := range processes {
+		t.AppendRow(tablepretty.Row{
+			proc.Inode,
+			proc.PID,
+			proc.Port,
+			proc.Protocol,
+			proc.State,
+			proc.LocalAddr,
+			proc.Command,
+			proc.User,
+		})
+
+		if proc.State != "LISTEN" && proc.State != "UNCONN" {
+			continue
+		}
+		key := portKey{proc.Port, proc.Protocol}
+		if listeners[key] == nil {
+			listeners[key] = make(map[uint64]bool)
+		}
+		listeners[key][proc.Inode] = true
+		if len(listeners[key]) == 2 {
+			shared = append(shared, key)
+		}
+	}
+
+	renderTable(t)
+	tableStatusf(color.Green, "\nFound %d socket(s)", len(processes))
+	for _, key := range shared {
+		statusf(color.Yellow, "Port %d/%s has %d listening sockets; kill one with 'portctl kill --inode <inode>'",
+			key.port, key.protocol, len(listeners[key]))
+	}
+}
+
+// outputWideTable renders every field in a single table, the middle ground
+// between the compact table and --details. Long values are cut to maxWidth
+// characters with an ellipsis; 0 disables truncation.
+func outputWideTable(processes []process.Process, extra []listColumn, maxWidth int) {
+	t := tablepretty.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tablepretty.StyleColoredBright)
+
+	// The wide table already shows the remote address in full
+	var columns []listColumn
+	for _, column := range extra {
+		if column.name != "remote" {
+			columns = append(columns, column)
+		}
+	}
+
+	header := tablepretty.Row{"PID", "Port", "Protocol", "Bind", "Service", "User", "CPU%", "Memory",
+		"Started", "Local Addr", "Remote Addr", "Full Command"}
+	for _, column := range columns {
+		header = append(header, column.header)
+	}
+	appendTableHeader(t, header)
+	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
+
+	configs := []tablepretty.ColumnConfig{
+		{Number: 1, Align: text.AlignRight},                                              // PID
+		{Number: 2, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}}, // Port
+		{Number: 3, Align: text.AlignCenter},                                             // Protocol
+		{Number: 4, Align: text.AlignCenter},                                             // Bind
+		{Number: 5, Align: text.AlignCenter},                                             // Service
+		{Number: 6, Align: text.AlignLeft},                                               // User
+		{Number: 7, Align: text.AlignRight},                                              // CPU%
+		{Number: 8, Align: text.AlignRight},                                              // Memory
+		{Number: 9, Align: text.AlignLeft},                                               // Started
+		{Number: 10, Align: text.AlignLeft},                                              // Local Addr
+		{Number: 11, Align: text.AlignLeft},                                              // Remote Addr
+		{Number: 12, Align: text.AlignLeft},                                              // Full Command
+	}
+	for i, column := range columns {
+		configs = append(configs, tablepretty.ColumnConfig{Number: len(header) - len(columns) + i + 1, Align: column.align})
+	}
+	t.SetColumnConfigs(configs)
+
+	truncate := func(s string) string {
+		if maxWidth == 0 {
+			return s
+		}
+		return text.Snip(s, maxWidth, "…")
+	}
+
+	for _, proc := range processes {
+		started := "-"
+		if !proc.StartTime.IsZero() {
+			started = proc.StartTime.Format(process.StartTimeLayout)
+		}
+		fullCommand := proc.FullCommand
+		if fullCommand == "" {
+			fullCommand = proc.Command
+		}
+
+		row := tablepretty.Row{
+			proc.PID,
+			proc.Port,
+			proc.Protocol,
+			proc.BindScope,
+			proc.ServiceType,
 			proc.User,
+			fmt.Sprintf("%.1f", proc.CPUPercent),
+			process.FormatMemory(float64(proc.MemoryMB)),
+			started,
+			truncate(proc.LocalAddr),
+			truncate(formatRemote(proc)),
+			truncate(fullCommand),
+		}
+		for _, column := range columns {
+			row = append(row, column.value(proc))
 		}
 		t.AppendRow(row)
 	}
 
-	t.Render()
-	color.Green("\nFound %d process(es)", len(processes))
+	renderTable(t)
+	tableStatusf(color.Green, "\nFound %d process(es)", len(processes))
+}
+
+// outputConflicts reports ports with more than one listener, those shared
+// between processes first
+func outputConflicts(conflicts []process.PortConflict) {
+	if listJSON {
+		if conflicts == nil {
+			conflicts = []process.PortConflict{}
+		}
+		writeJSON(conflicts)
+		return
+	}
+
+	if len(conflicts) == 0 {
+		statusf(color.Green, "✅ No port conflicts found")
+		return
+	}
+
+	for i, conflict := range conflicts {
+		if i > 0 {
+			fmt.Println()
+		}
+		if conflict.Kind == process.ConflictMultiplePIDs {
+			color.Red("⚠️  Port %d/%s: %d processes listening (PIDs %v)",
+				conflict.Port, conflict.Protocol, len(conflict.PIDs), conflict.PIDs)
+		} else {
+			color.Yellow("ℹ️  Port %d/%s: %d listeners in PID %d",
+				conflict.Port, conflict.Protocol, len(conflict.Listeners), conflict.PIDs[0])
+		}
+
+		t := tablepretty.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(tablepretty.StyleLight)
+		appendTableHeader(t, tablepretty.Row{"PID", "Command", "Local Addr", "Bind", "User"})
+		t.SetColumnConfigs([]tablepretty.ColumnConfig{
+			{Number: 1, Align: text.AlignRight}, // PID
+		})
+		for _, proc := range conflict.Listeners {
+			t.AppendRow(tablepretty.Row{proc.PID, proc.Command, proc.LocalAddr, proc.BindScope, proc.User})
+		}
+		t.Render()
+	}
+
+	shared := 0
+	for _, conflict := range conflicts {
+		if conflict.Kind == process.ConflictMultiplePIDs {
+			shared++
+		}
+	}
+	tableStatusf(color.Green, "\nFound %d port(s) with multiple listeners, %d shared between processes", len(conflicts), shared)
+}
+
+// outputValues prints each distinct PID (or port) on its own line with no
+// decoration, in the current sort order, for use with xargs and shell loops
+func outputValues(processes []process.Process, ports bool) {
+	seen := make(map[int]bool)
+	for _, proc := range processes {
+		value := proc.PID
+		if ports {
+			value = proc.Port
+		}
+		if seen[value] {
+			continue
+		}
+		seen[value] = true
+		fmt.Println(value)
+	}
 }
 
 func outputDetailed(processes []process.Process) {
@@ -161,6 +708,9 @@ func outputDetailed(processes []process.Process) {
 
 		color.Cyan("Process #%d", i+1)
 		fmt.Printf("  PID:           %d\n", proc.PID)
+		if proc.PPID != 0 {
+			fmt.Printf("  Parent PID:    %d\n", proc.PPID)
+		}
 		fmt.Printf("  Port:          %d (%s)\n", proc.Port, proc.Protocol)
 		fmt.Printf("  Command:       %s\n", proc.Command)
 		fmt.Printf("  Full Command:  %s\n", proc.FullCommand)
@@ -168,76 +718,64 @@ func outputDetailed(processes []process.Process) {
 		fmt.Printf("  User:          %s\n", proc.User)
 		fmt.Printf("  State:         %s\n", proc.State)
 		fmt.Printf("  Local Addr:    %s\n", proc.LocalAddr)
+		fmt.Printf("  Bind Scope:    %s\n", proc.BindScope)
 		fmt.Printf("  Remote Addr:   %s\n", proc.RemoteAddr)
+		if proc.RemoteHost != "" {
+			fmt.Printf("  Remote Host:   %s\n", proc.RemoteHost)
+		}
+		if proc.RemoteOwner != "" {
+			fmt.Printf("  Remote Owner:  %s\n", proc.RemoteOwner)
+		}
 		fmt.Printf("  CPU Usage:     %.1f%%\n", proc.CPUPercent)
-		fmt.Printf("  Memory:        %.1f MB\n", proc.MemoryMB)
+		fmt.Printf("  Memory:        %s\n", process.FormatMemory(float64(proc.MemoryMB)))
+		fmt.Printf("  Nice:          %d\n", proc.Nice)
+		if proc.OpenFDs != 0 {
+			fmt.Printf("  Open FDs:      %s\n", formatFDs(proc))
+		}
 
 		if !proc.StartTime.IsZero() {
-			fmt.Printf("  Started:       %s\n", proc.StartTime.Format("2006-01-02 15:04:05"))
-			fmt.Printf("  Uptime:        %s\n", time.Since(proc.StartTime).Round(time.Second))
+			fmt.Printf("  Started:       %s\n", proc.StartTime.Format(process.StartTimeLayout))
+			fmt.Printf("  Uptime:        %s\n", process.FormatSince(proc.StartTime))
 		}
 	}
 }
 
-func outputTree(processes []process.Process) {
-	// Group processes by service type
-	serviceGroups := make(map[string][]process.Process)
-	for _, proc := range processes {
-		serviceGroups[proc.ServiceType] = append(serviceGroups[proc.ServiceType], proc)
+// outputGroups renders processes in sections grouped by field, each with a
+// process count
+func outputGroups(processes []process.Process, field string) {
+	groups, err := process.GroupProcesses(processes, field)
+	if err != nil {
+		exitWithError(listJSON, exitCodeUsage, "%v", err)
 	}
 
-	color.Cyan("📊 Process Tree by Service Type\n")
+	statusf(color.Cyan, "📊 Processes by %s\n", field)
 
-	for serviceType, procs := range serviceGroups {
-		color.Yellow("├─ %s (%d processes)", serviceType, len(procs))
+	for _, group := range groups {
+		color.Yellow("├─ %s (%d processes)", group.Key, len(group.Processes))
 
-		for i, proc := range procs {
+		for i, proc := range group.Processes {
 			symbol := "├─"
-			if i == len(procs)-1 {
+			if i == len(group.Processes)-1 {
 				symbol = "└─"
 			}
 
 			uptime := ""
 			if !proc.StartTime.IsZero() {
-				uptime = fmt.Sprintf(" [%s]", time.Since(proc.StartTime).Round(time.Second))
+				uptime = fmt.Sprintf(" [%s]", process.FormatSince(proc.StartTime))
 			}
 
-			fmt.Printf("   %s PID %d: %s (Port %d) - %.1fMB%s\n",
-				symbol, proc.PID, proc.Command, proc.Port, proc.MemoryMB, uptime)
+			fmt.Printf("   %s PID %d: %s (Port %d) - %s%s\n",
+				symbol, proc.PID, proc.Command, proc.Port, process.FormatMemory(float64(proc.MemoryMB)), uptime)
 		}
 		fmt.Println()
 	}
 }
 
 func outputJSON(processes []process.Process) {
-	// Enhanced JSON output with all fields
-	fmt.Println("[")
-	for i, proc := range processes {
-		fmt.Printf(`  {
-    "pid": %d,
-    "port": %d,
-    "protocol": "%s",
-    "state": "%s",
-    "command": "%s",
-    "full_command": "%s",
-    "service_type": "%s",
-    "user": "%s",
-    "local_addr": "%s",
-    "remote_addr": "%s",
-    "cpu_percent": %.1f,
-    "memory_mb": %.1f,
-    "start_time": "%s"
-  }`, proc.PID, proc.Port, proc.Protocol, proc.State, proc.Command,
-			proc.FullCommand, proc.ServiceType, proc.User, proc.LocalAddr,
-			proc.RemoteAddr, proc.CPUPercent, proc.MemoryMB, proc.StartTime.Format(time.RFC3339))
-
-		if i < len(processes)-1 {
-			fmt.Println(",")
-		} else {
-			fmt.Println()
-		}
+	if processes == nil {
+		processes = []process.Process{}
 	}
-	fmt.Println("]")
+	writeJSON(processes)
 }
 
 func init() {
@@ -249,16 +787,59 @@ func init() {
 		"List all processes (same as not specifying a port)")
 	listCmd.Flags().StringVarP(&listService, "service", "s", "",
 		"Filter by service type or command name")
+	listCmd.Flags().StringVar(&listExclude, "exclude-service", "",
+		"Exclude processes matching service type or command name")
 	listCmd.Flags().StringVarP(&listUser, "user", "u", "",
 		"Filter by user")
 	listCmd.Flags().StringVar(&listSort, "sort", "port",
-		"Sort by field (port, pid, cpu, memory, command, service, user)")
+		"Sort by comma-separated fields, in priority order (port, pid, cpu, memory, command, service, user)")
+	listCmd.Flags().StringVar(&listOrder, "sort-order", "",
+		"Override sort direction for every field (asc, desc); default is descending for cpu and memory")
 	listCmd.Flags().BoolVarP(&listTree, "tree", "t", false,
-		"Show process tree grouped by service type")
+		"Show process tree grouped by service type (same as --group-by service)")
+	listCmd.Flags().StringVar(&listGroupBy, "group-by", "",
+		"Group processes into sections by field (service, user, protocol, bind-scope)")
 	listCmd.Flags().BoolVarP(&listDetails, "details", "d", false,
 		"Show detailed information for each process")
 	listCmd.Flags().Float64Var(&listMemLimit, "mem-limit", 0,
 		"Show only processes using more than X MB of memory")
 	listCmd.Flags().Float64Var(&listCPULimit, "cpu-limit", 0,
 		"Show only processes using more than X% CPU")
+	listCmd.Flags().IntVar(&listFDLimit, "fd-limit", 0,
+		"Show only processes with more than N open file descriptors (Unix)")
+	listCmd.Flags().BoolVar(&listFast, "fast", false,
+		"Skip per-process CPU, memory, user and start time lookups")
+	listCmd.Flags().BoolVar(&listFast, "no-enhance", false,
+		"Alias for --fast")
+	listCmd.Flags().BoolVar(&listPIDs, "pids-only", false,
+		"Print only the matching PIDs, one per line")
+	listCmd.Flags().BoolVar(&listPorts, "ports-only", false,
+		"Print only the matching ports, one per line")
+	listCmd.Flags().BoolVarP(&listWide, "wide", "w", false,
+		"Show every field (full command, addresses, start time, bind scope) in one table")
+	listCmd.Flags().IntVar(&listMaxWidth, "max-width", 60,
+		"With --wide, truncate long values to this many characters (0 = no limit)")
+	listCmd.Flags().BoolVar(&listConflicts, "conflicts", false,
+		"Report only ports with more than one listener or owning PID")
+	listCmd.Flags().BoolVar(&listSockets, "sockets", false,
+		"List each socket separately with its inode, read from /proc (Linux)")
+	listCmd.Flags().StringVar(&listColumns, "columns", "",
+		"Comma-separated optional columns to add to the table (nice, ppid, fds, remote)")
+	listCmd.Flags().BoolVar(&listResolve, "resolve", false,
+		"Reverse-resolve remote addresses to host names (failures show the raw IP)")
+	listCmd.Flags().BoolVar(&listASN, "resolve-asn", false,
+		"Also look up the AS number, owner and country of public remote addresses via DNS (implies --resolve)")
+	listCmd.Flags().DurationVar(&listResolveTimeout, "resolve-timeout", process.DefaultResolveTimeout,
+		"Give up on each address lookup after this long")
+	listCmd.Flags().StringVar(&listBind, "bind-scope", "",
+		"Show only listeners with this bind scope (all, loopback, specific)")
+	listCmd.Flags().IntVar(&listPPID, "ppid", 0,
+		"Show only processes whose parent has this PID")
+	listCmd.Flags().StringVar(&listInterface, "interface", "",
+		"Show only sockets bound to an address of this network interface, or to a matching wildcard")
+	listCmd.Flags().BoolVar(&tableNoHeader, "no-header", false,
+		"Omit the table header and the process count, for scripts")
+	listCmd.Flags().BoolVar(&tableMarkdown, "markdown", false,
+		"Print the table as Markdown, without colors")
+	listCmd.MarkFlagsMutuallyExclusive("markdown", "no-header")
 }
@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/fatih/color"
+	"github.com/mark3labs/mcp-go/client"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/spf13/cobra"
@@ -16,24 +22,117 @@ var mcpCmd = &cobra.Command{
 	Use:   "mcp",
 	Short: "Start the Model Context Protocol (MCP) server",
 	Long: `Start the MCP server to allow AI agents to interact with portctl.
-This command runs a JSON-RPC server over stdio.`,
+This command runs a JSON-RPC server over stdio.
+
+With --watch-file, saving the config file applies server.cache-ttl and
+server.metrics to the running server without a restart (a --cache-ttl given
+on the command line still wins). Reload messages go to stderr.
+
+Each tool call or resource read is cancelled after --request-timeout
+(server.request-timeout, default 30s; 0 disables) and reports a timeout
+error, so a stuck lsof cannot hold the server forever. The scan_ports tool is
+exempt: a scan is bounded by its per-port connection timeout and may take
+longer over a large range.`,
 	Run: runMCP,
 }
 
-func runMCP(cmd *cobra.Command, args []string) {
-	// Create MCP server
+var mcpManifestOutput string
+
+var mcpSelfTestJSON bool
+
+var mcpSelfTestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Check the MCP tools by calling them through an in-process client",
+	Long: `Start the MCP server in-process, connect a client over an in-memory
+transport, and call the read-only tools (list_processes, get_system_stats),
+checking that each answers with valid JSON containing the expected fields.
+No external MCP client is needed. Exits 1 if any check fails.
+
+Examples:
+  portctl mcp selftest
+  portctl mcp selftest --json`,
+	Args: cobra.NoArgs,
+	Run:  runMCPSelfTest,
+}
+
+var mcpManifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Print the MCP manifest (JSON-LD) for the registered tools",
+	Long: `Generate the MCP manifest from the tools actually registered by the server,
+so that .well-known/mcp-manifest.jsonld never drifts from the code.
+
+Examples:
+  portctl mcp manifest                                        # Print to stdout
+  portctl mcp manifest -o .well-known/mcp-manifest.jsonld     # Write to a file`,
+	Run: runMCPManifest,
+}
+
+// mcpManifestTool describes a single tool entry in the MCP manifest
+type mcpManifestTool struct {
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	InputSchema mcp.ToolArgumentsSchema `json:"inputSchema"`
+}
+
+// mcpManifest is the JSON-LD document published at .well-known/mcp-manifest.jsonld
+type mcpManifest struct {
+	Context       string            `json:"@context"`
+	Type          string            `json:"type"`
+	Name          string            `json:"name"`
+	Version       string            `json:"version"`
+	Description   string            `json:"description"`
+	Homepage      string            `json:"homepage"`
+	Documentation string            `json:"documentation"`
+	Protocol      string            `json:"protocol"`
+	Capabilities  map[string]bool   `json:"capabilities"`
+	Tools         []mcpManifestTool `json:"tools"`
+	Integration   map[string]string `json:"integration"`
+}
+
+// newMCPServer creates the MCP server with all portctl tools registered.
+// Tools and resources share one process manager so that rapid successive
+// calls reuse the same snapshot.
+func newMCPServer() *server.MCPServer {
+	return newMCPServerWith(newServerProcessManager())
+}
+
+// newMCPServerWith creates the MCP server around pm, which the caller may
+// keep to reconfigure while the server runs
+func newMCPServerWith(pm *process.ProcessManager) *server.MCPServer {
+
 	s := server.NewMCPServer(
 		"portctl",
-		"1.0.0",
+		rootCmd.Version,
 		server.WithResourceCapabilities(true, true),
 		server.WithLogging(),
+		server.WithToolHandlerMiddleware(timeoutToolMiddleware(serverRequestTimeout)),
+		server.WithResourceHandlerMiddleware(timeoutResourceMiddleware(serverRequestTimeout)),
 	)
 
 	// Register tools
-	registerListProcessesTool(s)
-	registerKillProcessTool(s)
+	registerListProcessesTool(s, pm)
+	registerKillProcessTool(s, pm)
+	registerKillByServiceTool(s, pm)
 	registerScanPortsTool(s)
-	registerSystemStatsTool(s)
+	registerSystemStatsTool(s, pm)
+
+	// Register resources
+	registerProcessesResource(s, pm)
+	registerStatsResource(s, pm)
+
+	return s
+}
+
+func runMCP(cmd *cobra.Command, args []string) {
+	pm := newServerProcessManager()
+	s := newMCPServerWith(pm)
+
+	if serverWatchConfig {
+		if err := watchServerConfig(cmd, pm); err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot watch config: %v\n", err)
+			os.Exit(exitCodeError)
+		}
+	}
 
 	// Serve stdio
 	if err := server.ServeStdio(s); err != nil {
@@ -42,7 +141,50 @@ func runMCP(cmd *cobra.Command, args []string) {
 	}
 }
 
-func registerListProcessesTool(s *server.MCPServer) {
+// untimedTools are the MCP tools exempt from the request timeout, for the
+// same reason as untimedGRPCMethods
+var untimedTools = map[string]bool{
+	"scan_ports": true,
+}
+
+// timeoutToolMiddleware runs every tool call except those in untimedTools
+// under the request timeout. A call that overran reports a tool error
+// instead of any partial result.
+func timeoutToolMiddleware(timeout time.Duration) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if untimedTools[request.Params.Name] {
+				return next(ctx, request)
+			}
+			callCtx, cancel := withRequestTimeout(ctx, timeout)
+			defer cancel()
+
+			result, err := next(callCtx, request)
+			if requestTimedOut(ctx, callCtx) {
+				return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s", request.Params.Name, timeout)), nil
+			}
+			return result, err
+		}
+	}
+}
+
+// timeoutResourceMiddleware runs every resource read under the request timeout
+func timeoutResourceMiddleware(timeout time.Duration) server.ResourceHandlerMiddleware {
+	return func(next server.ResourceHandlerFunc) server.ResourceHandlerFunc {
+		return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+			callCtx, cancel := withRequestTimeout(ctx, timeout)
+			defer cancel()
+
+			contents, err := next(callCtx, request)
+			if requestTimedOut(ctx, callCtx) {
+				return nil, fmt.Errorf("reading %s timed out after %s", request.Params.URI, timeout)
+			}
+			return contents, err
+		}
+	}
+}
+
+func registerListProcessesTool(s *server.MCPServer, pm *process.ProcessManager) {
 	tool := mcp.NewTool("list_processes",
 		mcp.WithDescription("List running processes, optionally filtered by port or service"),
 		mcp.WithNumber("port",
@@ -51,11 +193,12 @@ func registerListProcessesTool(s *server.MCPServer) {
 		mcp.WithString("service",
 			mcp.Description("Filter by service name (e.g., 'node', 'python')"),
 		),
+		mcp.WithString("fields",
+			mcp.Description("Comma-separated fields to include (e.g., 'port,command'); all fields if omitted"),
+		),
 	)
 
 	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		pm := process.NewProcessManager()
-
 		var processes []process.Process
 		var err error
 
@@ -82,11 +225,36 @@ func registerListProcessesTool(s *server.MCPServer) {
 			processes = pm.FilterProcesses(processes, filterOpts)
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("%v", processes)), nil
+		// Project to the requested fields to keep the payload small
+		var payload interface{} = processes
+		var ignored []string
+		if fields, ok := args["fields"].(string); ok && fields != "" {
+			var valid []string
+			valid, ignored = process.SelectFields([]string{fields})
+			if len(valid) > 0 {
+				payload = process.ProjectProcesses(processes, valid)
+			}
+		}
+		if processes == nil {
+			payload = []process.Process{}
+		}
+
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error encoding processes: %v", err)), nil
+		}
+
+		result := mcp.NewToolResultText(string(data))
+		if len(ignored) > 0 {
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+				"Note: ignored unknown fields: %s (valid fields: %s)",
+				strings.Join(ignored, ", "), strings.Join(process.ProcessFieldNames(), ", "))))
+		}
+		return result, nil
 	})
 }
 
-func registerKillProcessTool(s *server.MCPServer) {
+func registerKillProcessTool(s *server.MCPServer, pm *process.ProcessManager) {
 	tool := mcp.NewTool("kill_process",
 		mcp.WithDescription("Kill a process by PID or Port"),
 		mcp.WithNumber("pid",
@@ -101,8 +269,6 @@ func registerKillProcessTool(s *server.MCPServer) {
 	)
 
 	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		pm := process.NewProcessManager()
-
 		args, ok := request.Params.Arguments.(map[string]any)
 		if !ok {
 			args = make(map[string]any)
@@ -125,6 +291,8 @@ func registerKillProcessTool(s *server.MCPServer) {
 		}
 
 		if portOk {
+			// Look the port up afresh, never in a cached snapshot, before signalling
+			pm.InvalidateCache()
 			processes, err := pm.GetProcessesOnPort(ctx, int(port))
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("Error finding processes on port %d: %v", int(port), err)), nil
@@ -163,6 +331,83 @@ func registerKillProcessTool(s *server.MCPServer) {
 	})
 }
 
+// killByServiceResult is the JSON result of the kill_by_service tool
+type killByServiceResult struct {
+	Service string            `json:"service"`
+	Force   bool              `json:"force"`
+	Targets []process.Process `json:"targets"`
+	Killed  []int             `json:"killed"`
+	Failed  []killFailure     `json:"failed"`
+}
+
+func registerKillByServiceTool(s *server.MCPServer, pm *process.ProcessManager) {
+	tool := mcp.NewTool("kill_by_service",
+		mcp.WithDescription("Kill all processes of a service type (e.g., all node servers)"),
+		mcp.WithString("service",
+			mcp.Required(),
+			mcp.Description("Service name to match (e.g., 'node', 'python')"),
+		),
+		mcp.WithBoolean("force",
+			mcp.Description("Force kill (SIGKILL)"),
+		),
+	)
+
+	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			args = make(map[string]any)
+		}
+
+		service, _ := args["service"].(string)
+		if strings.TrimSpace(service) == "" {
+			return mcp.NewToolResultError("Must provide 'service'"), nil
+		}
+		force, _ := args["force"].(bool)
+
+		// Enumerate afresh, never from a cached snapshot, before signalling
+		pm.InvalidateCache()
+		processes, err := pm.GetAllProcesses(ctx)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error getting processes: %v", err)), nil
+		}
+
+		targets := pm.FilterProcesses(processes, process.FilterOptions{Service: service})
+		targets = removeDuplicateProcesses(targets)
+
+		result := killByServiceResult{
+			Service: service,
+			Force:   force,
+			Targets: []process.Process{},
+			Killed:  []int{},
+			Failed:  []killFailure{},
+		}
+
+		var pids []int
+		for _, proc := range targets {
+			if isSelfPID(proc.PID) {
+				continue
+			}
+			result.Targets = append(result.Targets, proc)
+			pids = append(pids, proc.PID)
+		}
+
+		results := pm.KillProcesses(ctx, pids, force)
+		for _, pid := range pids {
+			if err := results[pid]; err != nil {
+				result.Failed = append(result.Failed, killFailure{PID: pid, Error: err.Error()})
+			} else {
+				result.Killed = append(result.Killed, pid)
+			}
+		}
+
+		data, err := json.Marshal(result)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error encoding results: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
+	})
+}
+
 func registerScanPortsTool(s *server.MCPServer) {
 	tool := mcp.NewTool("scan_ports",
 		mcp.WithDescription("Scan for open ports on a host"),
@@ -207,7 +452,7 @@ func registerScanPortsTool(s *server.MCPServer) {
 			ports = append(ports, p)
 		}
 
-		results := scanPorts(host, ports)
+		results := scanPorts(ctx, host, ports, nil)
 
 		var openPorts []ScanResult
 		for _, r := range results {
@@ -220,22 +465,335 @@ func registerScanPortsTool(s *server.MCPServer) {
 	})
 }
 
-func registerSystemStatsTool(s *server.MCPServer) {
+func registerSystemStatsTool(s *server.MCPServer, pm *process.ProcessManager) {
 	tool := mcp.NewTool("get_system_stats",
 		mcp.WithDescription("Get system resource usage and statistics"),
+		mcp.WithBoolean("per_core",
+			mcp.Description("Include per-core CPU utilization"),
+		),
 	)
 
 	s.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		pm := process.NewProcessManager()
-		stats, err := pm.GetSystemStats(ctx)
+		stats, err := pm.GetSystemStats(ctx, process.StatsOptions{})
 		if err != nil {
 			return mcp.NewToolResultError(fmt.Sprintf("Error getting stats: %v", err)), nil
 		}
 
-		return mcp.NewToolResultText(fmt.Sprintf("%+v", stats)), nil
+		args, ok := request.Params.Arguments.(map[string]any)
+		if !ok {
+			args = make(map[string]any)
+		}
+		if perCore, _ := args["per_core"].(bool); !perCore {
+			stats.PerCorePercent = nil
+		}
+
+		data, err := json.Marshal(stats)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error encoding stats: %v", err)), nil
+		}
+		return mcp.NewToolResultText(string(data)), nil
 	})
 }
 
+func registerProcessesResource(s *server.MCPServer, pm *process.ProcessManager) {
+	resource := mcp.NewResource("portctl://processes", "Processes",
+		mcp.WithResourceDescription("Current snapshot of processes listening on or connected to ports"),
+		mcp.WithMIMEType("application/json"),
+	)
+
+	s.AddResource(resource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		processes, err := pm.GetAllProcesses(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error getting processes: %w", err)
+		}
+		if processes == nil {
+			processes = []process.Process{}
+		}
+		return jsonResourceContents(request.Params.URI, processes)
+	})
+}
+
+func registerStatsResource(s *server.MCPServer, pm *process.ProcessManager) {
+	resource := mcp.NewResource("portctl://stats", "System Stats",
+		mcp.WithResourceDescription("Current system resource usage and port statistics"),
+		mcp.WithMIMEType("application/json"),
+	)
+
+	s.AddResource(resource, func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		stats, err := pm.GetSystemStats(ctx, process.StatsOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting stats: %w", err)
+		}
+		return jsonResourceContents(request.Params.URI, stats)
+	})
+}
+
+// jsonResourceContents encodes v as the JSON text contents of a resource
+func jsonResourceContents(uri string, v interface{}) ([]mcp.ResourceContents, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding %s: %w", uri, err)
+	}
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      uri,
+			MIMEType: "application/json",
+			Text:     string(data),
+		},
+	}, nil
+}
+
+// mcpCheck is the outcome of one selftest step
+type mcpCheck struct {
+	Name   string `json:"name"`
+	OK     bool   `json:"ok"`
+	Detail string `json:"detail"`
+}
+
+func runMCPSelfTest(cmd *cobra.Command, args []string) {
+	checks, err := selfTestMCP(cmd.Context(), newMCPServer())
+	if err != nil {
+		exitWithError(mcpSelfTestJSON, exitCodeError, "MCP selftest could not run: %v", err)
+	}
+
+	failed := 0
+	for _, check := range checks {
+		if !check.OK {
+			failed++
+		}
+	}
+
+	if mcpSelfTestJSON {
+		writeJSON(checks)
+	} else {
+		for _, check := range checks {
+			if check.OK {
+				color.Green("✅ %s: %s", check.Name, check.Detail)
+			} else {
+				color.Red("❌ %s: %s", check.Name, check.Detail)
+			}
+		}
+		if failed == 0 {
+			statusf(color.Green, "\nAll %d MCP checks passed", len(checks))
+		} else {
+			statusf(color.Red, "\n%d of %d MCP checks failed", failed, len(checks))
+		}
+	}
+
+	if failed > 0 {
+		os.Exit(exitCodeError)
+	}
+}
+
+// selfTestMCP connects an in-process client to s, performs the MCP handshake
+// and exercises the read-only tools. It returns an error only if the client
+// cannot be set up; failing tools are reported as failed checks.
+func selfTestMCP(ctx context.Context, s *server.MCPServer) ([]mcpCheck, error) {
+	c, initResult, err := connectMCPClient(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = c.Close() }()
+
+	checks := []mcpCheck{{
+		Name:   "initialize",
+		OK:     initResult.ServerInfo.Name == "portctl",
+		Detail: fmt.Sprintf("server %s %s, protocol %s", initResult.ServerInfo.Name, initResult.ServerInfo.Version, initResult.ProtocolVersion),
+	}}
+
+	checks = append(checks, checkMCPToolList(ctx, c, s))
+	checks = append(checks, checkMCPTool(ctx, c, "list_processes", map[string]any{"fields": "pid,port,command"},
+		func(text string) (string, error) {
+			var processes []map[string]any
+			if err := json.Unmarshal([]byte(text), &processes); err != nil {
+				return "", fmt.Errorf("response is not a JSON array: %w", err)
+			}
+			for _, proc := range processes {
+				if err := requireKeys(proc, "pid", "port", "command"); err != nil {
+					return "", err
+				}
+			}
+			return fmt.Sprintf("returned %d process(es)", len(processes)), nil
+		}))
+	checks = append(checks, checkMCPTool(ctx, c, "get_system_stats", nil,
+		func(text string) (string, error) {
+			var stats map[string]any
+			if err := json.Unmarshal([]byte(text), &stats); err != nil {
+				return "", fmt.Errorf("response is not a JSON object: %w", err)
+			}
+			if err := requireKeys(stats, "total_processes", "listening_ports", "cpu_usage_percent", "memory_usage_gb"); err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%v processes, %v listening ports", stats["total_processes"], stats["listening_ports"]), nil
+		}))
+
+	return checks, nil
+}
+
+// connectMCPClient starts an in-process client for s and performs the MCP
+// initialize handshake. The caller must close the client.
+func connectMCPClient(ctx context.Context, s *server.MCPServer) (*client.Client, *mcp.InitializeResult, error) {
+	c, err := client.NewInProcessClient(s)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.Start(ctx); err != nil {
+		_ = c.Close()
+		return nil, nil, fmt.Errorf("starting client: %w", err)
+	}
+
+	request := mcp.InitializeRequest{}
+	request.Params.ProtocolVersion = mcp.LATEST_PROTOCOL_VERSION
+	request.Params.ClientInfo = mcp.Implementation{Name: "portctl-selftest", Version: rootCmd.Version}
+	result, err := c.Initialize(ctx, request)
+	if err != nil {
+		_ = c.Close()
+		return nil, nil, fmt.Errorf("initialize: %w", err)
+	}
+	return c, result, nil
+}
+
+// checkMCPToolList verifies the client sees every tool registered on s
+func checkMCPToolList(ctx context.Context, c *client.Client, s *server.MCPServer) mcpCheck {
+	check := mcpCheck{Name: "tools/list"}
+
+	result, err := c.ListTools(ctx, mcp.ListToolsRequest{})
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+
+	listed := make(map[string]bool, len(result.Tools))
+	for _, tool := range result.Tools {
+		listed[tool.Name] = true
+	}
+	var missing []string
+	for name := range s.ListTools() {
+		if !listed[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		check.Detail = "missing tools: " + strings.Join(missing, ", ")
+		return check
+	}
+
+	check.OK = true
+	check.Detail = fmt.Sprintf("%d tool(s) listed", len(result.Tools))
+	return check
+}
+
+// checkMCPTool calls a tool and validates the text of its first content item
+func checkMCPTool(ctx context.Context, c *client.Client, name string, args map[string]any, validate func(text string) (string, error)) mcpCheck {
+	check := mcpCheck{Name: name}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := c.CallTool(ctx, request)
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	if len(result.Content) == 0 {
+		check.Detail = "empty response"
+		return check
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	if !ok {
+		check.Detail = "response is not text"
+		return check
+	}
+	if result.IsError {
+		check.Detail = text.Text
+		return check
+	}
+
+	detail, err := validate(text.Text)
+	if err != nil {
+		check.Detail = err.Error()
+		return check
+	}
+	check.OK = true
+	check.Detail = detail
+	return check
+}
+
+// requireKeys returns an error naming the first key missing from m
+func requireKeys(m map[string]any, keys ...string) error {
+	for _, key := range keys {
+		if _, ok := m[key]; !ok {
+			return fmt.Errorf("missing field %q", key)
+		}
+	}
+	return nil
+}
+
+func runMCPManifest(cmd *cobra.Command, args []string) {
+	data, err := json.MarshalIndent(buildMCPManifest(newMCPServer()), "", "  ")
+	if err != nil {
+		exitWithError(false, exitCodeError, "Error encoding manifest: %v", err)
+	}
+	data = append(data, '\n')
+
+	if mcpManifestOutput == "" {
+		_, _ = os.Stdout.Write(data)
+		return
+	}
+	if err := os.WriteFile(mcpManifestOutput, data, 0644); err != nil {
+		exitWithError(false, exitCodeError, "Error writing manifest: %v", err)
+	}
+}
+
+// buildMCPManifest describes the tools registered on s, sorted by name
+func buildMCPManifest(s *server.MCPServer) mcpManifest {
+	registered := s.ListTools()
+	names := make([]string, 0, len(registered))
+	for name := range registered {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tools := make([]mcpManifestTool, 0, len(names))
+	for _, name := range names {
+		tool := registered[name].Tool
+		tools = append(tools, mcpManifestTool{
+			Name:        tool.Name,
+			Description: tool.Description,
+			InputSchema: mcp.ToolArgumentsSchema(tool.InputSchema),
+		})
+	}
+
+	return mcpManifest{
+		Context:       "https://www.w3.org/ns/activitystreams",
+		Type:          "Service",
+		Name:          "portctl",
+		Version:       rootCmd.Version,
+		Description:   "Secure, cross-platform CLI for managing processes on ports",
+		Homepage:      "https://github.com/ckodex-labs/portctl",
+		Documentation: "https://ckodex-labs.github.io/portctl",
+		Protocol:      "mcp",
+		Capabilities:  map[string]bool{"tools": true, "resources": true, "logging": true},
+		Tools:         tools,
+		Integration:   map[string]string{"command": "portctl mcp", "transport": "stdio", "format": "json-rpc"},
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(mcpCmd)
+	mcpCmd.Flags().DurationVar(&serverCacheTTL, "cache-ttl", defaultServerCacheTTL,
+		"Reuse process listings for this long between tool calls; 0 disables caching")
+	mcpCmd.Flags().BoolVar(&serverWatchConfig, "watch-file", false,
+		"Reload server.cache-ttl and server.metrics when the config file changes")
+	mcpCmd.Flags().DurationVar(&serverRequestTimeout, "request-timeout", defaultRequestTimeout,
+		"Cancel any tool call or resource read still running after this long; 0 disables")
+	mcpCmd.AddCommand(mcpManifestCmd)
+	mcpCmd.AddCommand(mcpSelfTestCmd)
+
+	mcpManifestCmd.Flags().StringVarP(&mcpManifestOutput, "output", "o", "",
+		"Write the manifest to a file instead of stdout")
+
+	mcpSelfTestCmd.Flags().BoolVarP(&mcpSelfTestJSON, "json", "j", false,
+		"Output the check results in JSON format")
 }
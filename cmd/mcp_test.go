@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/client"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// newMCPTestClient connects an in-process client to a fresh portctl MCP server
+func newMCPTestClient(t *testing.T) *client.Client {
+	t.Helper()
+	c, _, err := connectMCPClient(context.Background(), newMCPServer())
+	if err != nil {
+		t.Fatalf("connecting MCP client: %v", err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestMCPSelfTest(t *testing.T) {
+	checks, err := selfTestMCP(context.Background(), newMCPServer())
+	if err != nil {
+		t.Fatalf("selfTestMCP: %v", err)
+	}
+	if len(checks) == 0 {
+		t.Fatal("Expected selftest checks")
+	}
+	for _, check := range checks {
+		if !check.OK {
+			t.Errorf("check %s failed: %s", check.Name, check.Detail)
+		}
+	}
+}
+
+func TestMCPListProcessesOnPort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	// The listener must be visible to the process backends for the tool to list it
+	owners, err := newProcessManager().GetProcessesOnPort(context.Background(), port)
+	if err != nil {
+		t.Skipf("cannot enumerate processes here: %v", err)
+	}
+	visible := false
+	for _, proc := range owners {
+		visible = visible || proc.PID == os.Getpid()
+	}
+	if !visible {
+		t.Skip("listener not visible to the process backends here")
+	}
+
+	c := newMCPTestClient(t)
+	check := checkMCPTool(context.Background(), c, "list_processes", map[string]any{"port": float64(port)},
+		func(text string) (string, error) {
+			var processes []map[string]any
+			if err := json.Unmarshal([]byte(text), &processes); err != nil {
+				return "", err
+			}
+			for _, proc := range processes {
+				if pid, _ := proc["pid"].(float64); int(pid) == os.Getpid() {
+					return "found", nil
+				}
+			}
+			return "", fmt.Errorf("PID %d not listed on port %d: %s", os.Getpid(), port, text)
+		})
+	if !check.OK {
+		t.Fatalf("list_processes on port %d: %s", port, check.Detail)
+	}
+}
+
+func TestMCPKillProcessRequiresTarget(t *testing.T) {
+	c := newMCPTestClient(t)
+	check := checkMCPTool(context.Background(), c, "kill_process", map[string]any{},
+		func(text string) (string, error) { return text, nil })
+	if check.OK {
+		t.Fatalf("Expected kill_process without pid or port to fail, got %q", check.Detail)
+	}
+	if check.Detail != "Must provide either 'pid' or 'port'" {
+		t.Errorf("Unexpected error: %q", check.Detail)
+	}
+}
+
+func TestTimeoutToolMiddleware(t *testing.T) {
+	handler := timeoutToolMiddleware(20 * time.Millisecond)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		<-ctx.Done()
+		return mcp.NewToolResultText("partial"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "list_processes"
+	result, err := handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.IsError {
+		t.Fatalf("result = %+v, want a tool error", result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok || !strings.Contains(text.Text, "list_processes timed out") {
+		t.Errorf("result content = %+v, want a timeout message", result.Content)
+	}
+}
+
+func TestTimeoutToolMiddlewareExemptsScans(t *testing.T) {
+	handler := timeoutToolMiddleware(time.Millisecond)(func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := ctx.Deadline(); ok {
+			t.Error("scan_ports ran under the request timeout")
+		}
+		return mcp.NewToolResultText("done"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "scan_ports"
+	result, err := handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Errorf("scan_ports = (%+v, %v), want a result", result, err)
+	}
+}
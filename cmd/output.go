@@ -0,0 +1,130 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	tablepretty "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// Exit codes used by commands and reported in JSON error envelopes
+const (
+	exitCodeError = 1 // Operation failed
+	exitCodeUsage = 2 // Invalid arguments or flags
+	exitCodeAudit = 3 // Audit findings exceeded the allowed threshold
+)
+
+// jsonEnvelope is the common shape of every --json payload.
+// Successful commands populate Data; failures populate Error and Code.
+type jsonEnvelope struct {
+	Data  interface{} `json:"data,omitempty"`
+	Error string      `json:"error,omitempty"`
+	Code  int         `json:"code,omitempty"`
+}
+
+// tableNoHeader is set by --no-header on commands that print tables, so
+// their rows can be appended together or fed to awk
+var tableNoHeader bool
+
+// appendTableHeader adds the column header row to t unless --no-header is set
+func appendTableHeader(t tablepretty.Writer, header tablepretty.Row) {
+	if !tableNoHeader {
+		t.AppendHeader(header)
+	}
+}
+
+// tableMarkdown is set by --markdown on list, scan and stats to print their
+// tables as GitHub-flavored Markdown for pasting into issues and docs
+var tableMarkdown bool
+
+// renderTable prints t in its terminal style, or as Markdown with --markdown
+func renderTable(t tablepretty.Writer) {
+	if tableMarkdown {
+		t.RenderMarkdown()
+		return
+	}
+	t.Render()
+}
+
+// disableColors turns off both color libraries, so no escape codes end up in
+// output meant to be pasted elsewhere
+func disableColors() {
+	color.NoColor = true
+	text.DisableColors()
+}
+
+// tableStatusf prints a heading or count line that frames a table. Like
+// statusf it is suppressed by --quiet, and also by --no-header.
+func tableStatusf(print func(format string, a ...interface{}), format string, a ...interface{}) {
+	if !tableNoHeader {
+		statusf(print, format, a...)
+	}
+}
+
+// writeJSON writes a successful payload wrapped in the JSON envelope to stdout
+func writeJSON(data interface{}) {
+	if err := writeJSONTo(os.Stdout, data); err != nil {
+		fmt.Fprintf(os.Stderr, "Error encoding JSON: %v\n", err)
+		os.Exit(exitCodeError)
+	}
+}
+
+// exitWithError reports an error and exits with the given code.
+// In JSON mode the error is written to stdout as an envelope so that
+// consumers always receive valid JSON regardless of outcome; otherwise it
+// goes to stderr, keeping stdout clean for piped results.
+func exitWithError(jsonMode bool, code int, format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	if jsonMode {
+		_ = encodeJSON(jsonEnvelope{Error: msg, Code: code})
+	} else {
+		fmt.Fprintln(color.Error, color.RedString("%s", msg))
+	}
+	os.Exit(code)
+}
+
+// statusf prints a decorative or status line (headers, progress, tips, footers)
+// to stderr unless --quiet is set, so stdout carries only the results and can
+// be piped or parsed. Results themselves should be printed directly.
+//
+// print is one of the color.* printers or printfln, which all write to
+// color.Output; it is pointed at stderr for the call. Commands print from one
+// goroutine, so the swap is not seen by other output.
+func statusf(print func(format string, a ...interface{}), format string, a ...interface{}) {
+	if quietOutput {
+		return
+	}
+	stdout := color.Output
+	color.Output = color.Error
+	defer func() { color.Output = stdout }()
+	print(format, a...)
+}
+
+// printfln is fmt.Printf with a trailing newline, matching the color.* printers
+// (including writing to color.Output, so statusf can redirect it)
+func printfln(format string, a ...interface{}) {
+	_, _ = fmt.Fprintf(color.Output, format+"\n", a...)
+}
+
+// writeJSONTo writes a successful payload wrapped in the JSON envelope to w,
+// for saving output in the same format as --json
+func writeJSONTo(w io.Writer, data interface{}) error {
+	return encodeJSONTo(w, jsonEnvelope{Data: data})
+}
+
+// encodeJSON writes v to stdout, indented unless --json-compact is set
+func encodeJSON(v interface{}) error {
+	return encodeJSONTo(os.Stdout, v)
+}
+
+func encodeJSONTo(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	if !jsonCompact {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/fatih/color"
+	tablepretty "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// captureColorOutput points color's stdout and stderr at buffers for the test
+func captureColorOutput(t *testing.T) (stdout, stderr *bytes.Buffer) {
+	t.Helper()
+	stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+	origOut, origErr, origNoColor := color.Output, color.Error, color.NoColor
+	color.Output, color.Error, color.NoColor = stdout, stderr, true
+	t.Cleanup(func() {
+		color.Output, color.Error, color.NoColor = origOut, origErr, origNoColor
+	})
+	return stdout, stderr
+}
+
+func TestStatusfWritesToStderr(t *testing.T) {
+	stdout, stderr := captureColorOutput(t)
+
+	statusf(color.Green, "Found %d process(es)", 3)
+	statusf(printfln, "Searching...")
+	printfln("8080")
+
+	if got := stdout.String(); got != "8080\n" {
+		t.Errorf("stdout = %q, want only the result", got)
+	}
+	if got := stderr.String(); !strings.Contains(got, "Found 3 process(es)\n") || !strings.Contains(got, "Searching...\n") {
+		t.Errorf("stderr = %q, want both status lines", got)
+	}
+	if color.Output != stdout {
+		t.Error("statusf did not restore color.Output")
+	}
+}
+
+func TestExitWithErrorWritesToStderr(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCheckHelper$")
+	cmd.Env = append(os.Environ(), "PORTCTL_TEST_ARGS=kill 100%x")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	_ = cmd.Run()
+
+	if stdout.Len() != 0 {
+		t.Errorf("stdout = %q, want nothing", stdout.String())
+	}
+	if got := stderr.String(); !strings.Contains(got, "Invalid port number: 100%x") {
+		t.Errorf("stderr = %q, want the error message verbatim", got)
+	}
+}
+
+func TestStatusfQuiet(t *testing.T) {
+	stdout, stderr := captureColorOutput(t)
+	quietOutput = true
+	t.Cleanup(func() { quietOutput = false })
+
+	statusf(color.Green, "Found %d process(es)", 3)
+	if stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Errorf("--quiet printed %q to stdout and %q to stderr", stdout, stderr)
+	}
+}
+
+func TestRenderTableMarkdown(t *testing.T) {
+	origNoColor := color.NoColor
+	tableMarkdown = true
+	disableColors()
+	t.Cleanup(func() {
+		tableMarkdown = false
+		color.NoColor = origNoColor
+		text.EnableColors()
+	})
+
+	var out bytes.Buffer
+	tw := tablepretty.NewWriter()
+	tw.SetOutputMirror(&out)
+	tw.SetStyle(tablepretty.StyleColoredBright)
+	tw.AppendHeader(tablepretty.Row{"Port", "Command"})
+	tw.SetColumnConfigs([]tablepretty.ColumnConfig{{Number: 1, Align: text.AlignRight}})
+	tw.AppendRow(tablepretty.Row{8080, text.FgRed.Sprint("a|b")})
+	renderTable(tw)
+
+	want := "| Port | Command |\n| ---:| --- |\n| 8080 | a\\|b |\n"
+	if got := out.String(); got != want {
+		t.Errorf("renderTable with --markdown = %q, want %q", got, want)
+	}
+}
@@ -14,7 +14,10 @@ import (
 )
 
 var (
-	quickExport bool
+	quickExport   bool
+	quickJSON     bool
+	quickAllUsers bool
+	quickBatchOK  bool
 )
 
 var quickCmd = &cobra.Command{
@@ -26,42 +29,53 @@ This command provides shortcuts for frequent port management tasks that
 developers need during their daily workflow.
 
 Subcommands:
-  kill-dev       Kill all development servers (ports 3000-9999)
+  kill-dev       Kill all development servers (ports in dev.ports, default 3000-9999)
   kill-node      Kill all Node.js processes
   kill-stale     Kill processes older than 1 hour
   cleanup        Clean up zombie processes and free ports
   dev-ports      Show status of common development ports
   next-port      Find and export the next available port
+
+The kill actions (and cleanup) only touch your own processes, or the invoking
+user's under sudo, so they are safe on shared hosts. Pass --all-users to
+include processes owned by other users. Like kill, they refuse to kill more
+than kill.max-batch processes (default 10) at once without --confirm-batch.
   
 Examples:
   portctl quick kill-dev          # Kill all dev servers
+  portctl quick kill-dev --all-users  # Also kill other users' dev servers
+  portctl quick kill-dev --confirm-batch  # Allow more than kill.max-batch kills
   portctl quick kill-node         # Kill all Node.js processes  
   portctl quick cleanup           # Clean up stale processes
   portctl quick dev-ports         # Show dev port status
-  portctl quick next-port         # Get next available port`,
+  portctl quick next-port         # Get next available port
+  portctl quick kill-dev --json   # Machine-readable kill results`,
 	Args: cobra.ExactArgs(1),
 	Run:  runQuick,
 }
 
 func runQuick(cmd *cobra.Command, args []string) {
 	action := args[0]
-	pm := process.NewProcessManager()
+	pm := newProcessManager()
 	ctx := cmd.Context()
 
 	switch action {
 	case "kill-dev":
-		killDevProcesses(ctx, pm)
+		outputQuickReport(killDevProcesses(ctx, pm))
 	case "kill-node":
-		killNodeProcesses(ctx, pm)
+		outputQuickReport(killNodeProcesses(ctx, pm))
 	case "kill-stale":
-		killStaleProcesses(ctx, pm)
+		outputQuickReport(killStaleProcesses(ctx, pm))
 	case "cleanup":
-		cleanupProcesses(ctx, pm)
+		outputQuickReport(cleanupProcesses(ctx, pm))
 	case "dev-ports":
-		showDevPorts(ctx, pm)
+		outputQuickReport(showDevPorts(ctx, pm))
 	case "next-port":
-		findNextPort(ctx, pm)
+		outputQuickReport(findNextPort(ctx, pm))
 	default:
+		if quickJSON {
+			exitWithError(true, exitCodeUsage, "Unknown quick action: %s", action)
+		}
 		color.Red("Unknown quick action: %s", action)
 		fmt.Println("\nAvailable actions:")
 		fmt.Println("  kill-dev     - Kill all development servers")
@@ -74,111 +88,168 @@ func runQuick(cmd *cobra.Command, args []string) {
 	}
 }
 
-func killDevProcesses(ctx context.Context, pm *process.ProcessManager) {
-	color.Cyan("🧹 Killing all development server processes...")
+// quickKillReport is the machine-readable result of a kill-oriented quick action
+type quickKillReport struct {
+	Action  string            `json:"action"`
+	Targets []process.Process `json:"targets"`
+	Killed  []int             `json:"killed"`
+	Failed  []killFailure     `json:"failed"`
+}
 
-	processes, err := pm.GetAllProcesses(ctx)
-	if err != nil {
-		color.Red("Error getting processes: %v", err)
-		return
-	}
+// killFailure records a PID that could not be killed
+type killFailure struct {
+	PID   int    `json:"pid"`
+	Error string `json:"error"`
+}
 
-	var devProcesses []process.Process
-	for _, proc := range processes {
-		// Kill processes on development ports (3000-9999)
-		if proc.Port >= 3000 && proc.Port <= 9999 {
-			devProcesses = append(devProcesses, proc)
-		}
+// quickCleanupReport is the machine-readable result of the cleanup action
+type quickCleanupReport struct {
+	Action    string            `json:"action"`
+	Steps     []quickKillReport `json:"steps"`
+	Remaining int               `json:"remaining"`
+}
+
+// portStatus describes whether a port is in use and by what
+type portStatus struct {
+	Port    int    `json:"port"`
+	InUse   bool   `json:"in_use"`
+	PID     int    `json:"pid,omitempty"`
+	Command string `json:"command,omitempty"`
+}
+
+// devPortsReport is the machine-readable result of the dev-ports action
+type devPortsReport struct {
+	Ports         []portStatus `json:"ports"`
+	NextAvailable []int        `json:"next_available"`
+}
+
+// nextPortReport is the machine-readable result of the next-port action
+type nextPortReport struct {
+	Port int `json:"port"`
+}
+
+func outputQuickReport(report interface{}) {
+	if quickJSON && report != nil {
+		writeJSON(report)
 	}
+}
 
-	if len(devProcesses) == 0 {
-		color.Green("✅ No development processes found")
-		return
+// quickInfo prints decorative output unless JSON mode is enabled
+func quickInfo(print func(format string, a ...interface{}), format string, a ...interface{}) {
+	if !quickJSON {
+		print(format, a...)
 	}
+}
 
-	color.Yellow("Found %d development processes to kill:", len(devProcesses))
-	for _, proc := range devProcesses {
-		fmt.Printf("  • PID %d: %s on port %d\n", proc.PID, proc.Command, proc.Port)
+// killQuickTargets kills the given processes and builds a report from the KillProcesses result map
+func killQuickTargets(ctx context.Context, pm *process.ProcessManager, action, label string, targets []process.Process) *quickKillReport {
+	report := &quickKillReport{
+		Action:  action,
+		Targets: targets,
+		Killed:  []int{},
+		Failed:  []killFailure{},
+	}
+	if !quickAllUsers {
+		owned, skipped, err := scopeToCurrentUser(targets)
+		if err != nil {
+			exitWithError(quickJSON, exitCodeError, "%v; use --all-users to target every user's processes", err)
+		}
+		if skipped > 0 {
+			quickInfo(color.Yellow, "Note: skipped %d %s owned by other users; use --all-users to include them", skipped, label)
+		}
+		targets = owned
+		report.Targets = owned
 	}
 
-	pids := make([]int, len(devProcesses))
-	for i, proc := range devProcesses {
-		pids[i] = proc.PID
+	// Never signal portctl itself or the shell that started it
+	kept := make([]process.Process, 0, len(targets))
+	for _, proc := range targets {
+		if isSelfPID(proc.PID) {
+			quickInfo(color.Yellow, "Note: skipping PID %d (%s), which is portctl or its parent shell", proc.PID, proc.Command)
+			continue
+		}
+		kept = append(kept, proc)
 	}
+	targets = kept
+	report.Targets = kept
 
-	results := pm.KillProcesses(ctx, pids, false)
+	if len(targets) == 0 {
+		quickInfo(color.Green, "✅ No %s found", label)
+		return report
+	}
 
-	var killed, failed int
-	for _, err := range results {
-		if err == nil {
-			killed++
-		} else {
-			failed++
+	quickInfo(color.Yellow, "Found %d %s:", len(targets), label)
+	// A process listening on several ports shows up once per port; kill it once.
+	pids := make([]int, 0, len(targets))
+	seen := make(map[int]bool, len(targets))
+	for _, proc := range targets {
+		quickInfo(printfln, "  • PID %d: %s on port %d", proc.PID, proc.Command, proc.Port)
+		if !seen[proc.PID] {
+			seen[proc.PID] = true
+			pids = append(pids, proc.PID)
 		}
 	}
 
-	color.Green("✅ Killed %d processes", killed)
-	if failed > 0 {
-		color.Red("❌ Failed to kill %d processes", failed)
+	if maxBatch, over := exceedsBatchLimit(len(pids), quickBatchOK); over {
+		statusf(color.Yellow, "Tip: Re-run with --confirm-batch to allow large batch kills")
+		exitWithError(quickJSON, exitCodeUsage, "Refusing to kill %d processes: exceeds kill.max-batch limit of %d", len(pids), maxBatch)
 	}
+
+	results := newKillReport(pids, pm.KillProcesses(ctx, pids, false))
+	report.Killed, report.Failed = results.Killed, results.Failed
+
+	quickInfo(color.Green, "✅ Killed %d %s", len(report.Killed), label)
+	if len(report.Failed) > 0 {
+		quickInfo(color.Red, "❌ Failed to kill %d processes", len(report.Failed))
+	}
+
+	return report
 }
 
-func killNodeProcesses(ctx context.Context, pm *process.ProcessManager) {
-	color.Cyan("🧹 Killing all Node.js processes...")
+func killDevProcesses(ctx context.Context, pm *process.ProcessManager) *quickKillReport {
+	quickInfo(color.Cyan, "🧹 Killing all development server processes...")
 
 	processes, err := pm.GetAllProcesses(ctx)
 	if err != nil {
-		color.Red("Error getting processes: %v", err)
-		return
+		exitWithError(quickJSON, exitCodeError, "Error getting processes: %v", err)
 	}
 
-	var nodeProcesses []process.Process
+	devPorts := devPortRange()
+	var devProcesses []process.Process
 	for _, proc := range processes {
-		if strings.Contains(strings.ToLower(proc.Command), "node") ||
-			strings.Contains(strings.ToLower(proc.ServiceType), "node") {
-			nodeProcesses = append(nodeProcesses, proc)
+		if devPorts.Contains(proc.Port) {
+			devProcesses = append(devProcesses, proc)
 		}
 	}
 
-	if len(nodeProcesses) == 0 {
-		color.Green("✅ No Node.js processes found")
-		return
-	}
+	return killQuickTargets(ctx, pm, "kill-dev", "development processes", devProcesses)
+}
 
-	color.Yellow("Found %d Node.js processes:", len(nodeProcesses))
-	for _, proc := range nodeProcesses {
-		fmt.Printf("  • PID %d: %s on port %d\n", proc.PID, proc.Command, proc.Port)
-	}
+func killNodeProcesses(ctx context.Context, pm *process.ProcessManager) *quickKillReport {
+	quickInfo(color.Cyan, "🧹 Killing all Node.js processes...")
 
-	pids := make([]int, len(nodeProcesses))
-	for i, proc := range nodeProcesses {
-		pids[i] = proc.PID
+	processes, err := pm.GetAllProcesses(ctx)
+	if err != nil {
+		exitWithError(quickJSON, exitCodeError, "Error getting processes: %v", err)
 	}
 
-	results := pm.KillProcesses(ctx, pids, false)
-
-	var killed, failed int
-	for _, err := range results {
-		if err == nil {
-			killed++
-		} else {
-			failed++
+	var nodeProcesses []process.Process
+	for _, proc := range processes {
+		if strings.Contains(strings.ToLower(proc.Command), "node") ||
+			strings.Contains(strings.ToLower(proc.ServiceType), "node") {
+			nodeProcesses = append(nodeProcesses, proc)
 		}
 	}
 
-	color.Green("✅ Killed %d Node.js processes", killed)
-	if failed > 0 {
-		color.Red("❌ Failed to kill %d processes", failed)
-	}
+	return killQuickTargets(ctx, pm, "kill-node", "Node.js processes", nodeProcesses)
 }
 
-func killStaleProcesses(ctx context.Context, pm *process.ProcessManager) {
-	color.Cyan("🧹 Killing stale processes (older than 1 hour)...")
+func killStaleProcesses(ctx context.Context, pm *process.ProcessManager) *quickKillReport {
+	quickInfo(color.Cyan, "🧹 Killing stale processes (older than 1 hour)...")
 
 	processes, err := pm.GetAllProcesses(ctx)
 	if err != nil {
-		color.Red("Error getting processes: %v", err)
-		return
+		exitWithError(quickJSON, exitCodeError, "Error getting processes: %v", err)
 	}
 
 	var staleProcesses []process.Process
@@ -192,83 +263,71 @@ func killStaleProcesses(ctx context.Context, pm *process.ProcessManager) {
 		}
 	}
 
-	if len(staleProcesses) == 0 {
-		color.Green("✅ No stale processes found")
-		return
-	}
-
-	color.Yellow("Found %d stale processes:", len(staleProcesses))
-	for _, proc := range staleProcesses {
-		fmt.Printf("  • PID %d: %s on port %d\n", proc.PID, proc.Command, proc.Port)
-	}
-
-	pids := make([]int, len(staleProcesses))
-	for i, proc := range staleProcesses {
-		pids[i] = proc.PID
-	}
-
-	results := pm.KillProcesses(ctx, pids, false)
-
-	var killed, failed int
-	for _, err := range results {
-		if err == nil {
-			killed++
-		} else {
-			failed++
-		}
-	}
-
-	color.Green("✅ Killed %d stale processes", killed)
-	if failed > 0 {
-		color.Red("❌ Failed to kill %d processes", failed)
-	}
+	return killQuickTargets(ctx, pm, "kill-stale", "stale processes", staleProcesses)
 }
 
-func cleanupProcesses(ctx context.Context, pm *process.ProcessManager) {
-	color.Cyan("🧹 Performing comprehensive cleanup...")
+func cleanupProcesses(ctx context.Context, pm *process.ProcessManager) *quickCleanupReport {
+	quickInfo(color.Cyan, "🧹 Performing comprehensive cleanup...")
+	report := &quickCleanupReport{Action: "cleanup"}
 
 	// Kill development processes
-	color.Yellow("Step 1: Cleaning up development processes...")
-	killDevProcesses(ctx, pm)
+	quickInfo(color.Yellow, "Step 1: Cleaning up development processes...")
+	report.Steps = append(report.Steps, *killDevProcesses(ctx, pm))
 
-	fmt.Println()
+	quickInfo(printfln, "")
 
 	// Kill stale processes
-	color.Yellow("Step 2: Cleaning up stale processes...")
-	killStaleProcesses(ctx, pm)
+	quickInfo(color.Yellow, "Step 2: Cleaning up stale processes...")
+	report.Steps = append(report.Steps, *killStaleProcesses(ctx, pm))
 
-	fmt.Println()
+	quickInfo(printfln, "")
 
 	// Show final status
 	processes, err := pm.GetAllProcesses(ctx)
 	if err != nil {
-		color.Red("Error getting final process count: %v", err)
-		return
+		exitWithError(quickJSON, exitCodeError, "Error getting final process count: %v", err)
 	}
+	report.Remaining = len(processes)
 
-	color.Green("🎉 Cleanup complete! %d processes remain with open ports", len(processes))
+	quickInfo(color.Green, "🎉 Cleanup complete! %d processes remain with open ports", len(processes))
+	return report
 }
 
-func showDevPorts(ctx context.Context, pm *process.ProcessManager) {
-	color.Cyan("🛠️  Development Port Status")
+func showDevPorts(ctx context.Context, pm *process.ProcessManager) *devPortsReport {
+	quickInfo(color.Cyan, "🛠️  Development Port Status")
 
 	devPorts := []int{3000, 3001, 3002, 4000, 5000, 8000, 8080, 8081, 9000}
+	report := &devPortsReport{}
 
-	color.Yellow("\nCommon Development Ports:")
+	quickInfo(color.Yellow, "\nCommon Development Ports:")
 	for _, port := range devPorts {
 		processes, _ := pm.GetProcessesOnPort(ctx, port)
 
+		status := portStatus{Port: port}
 		if len(processes) > 0 {
 			proc := processes[0]
-			color.Red("  Port %d: IN USE (%s - PID %d)", port, proc.Command, proc.PID)
+			status.InUse = true
+			status.PID = proc.PID
+			status.Command = proc.Command
+			quickInfo(color.Red, "  Port %d: IN USE (%s - PID %d)", port, proc.Command, proc.PID)
 		} else {
-			color.Green("  Port %d: AVAILABLE", port)
+			quickInfo(color.Green, "  Port %d: AVAILABLE", port)
 		}
+		report.Ports = append(report.Ports, status)
 	}
 
 	// Find next 3 available ports
+	devRange := devPortRange()
+	available, _ := pm.FindAvailablePorts(ctx, devRange.Start, devRange.End, 3)
+	report.NextAvailable = available
+	if report.NextAvailable == nil {
+		report.NextAvailable = []int{}
+	}
+	if quickJSON {
+		return report
+	}
+
 	fmt.Println()
-	available, _ := pm.FindAvailablePorts(ctx, 3000, 9999, 3)
 	if len(available) > 0 {
 		color.Cyan("💡 Next available ports: %v", available)
 		fmt.Printf("\nQuick export commands:\n")
@@ -278,21 +337,30 @@ func showDevPorts(ctx context.Context, pm *process.ProcessManager) {
 			}
 		}
 	}
+
+	return report
 }
 
-func findNextPort(ctx context.Context, pm *process.ProcessManager) {
-	available, err := pm.FindAvailablePorts(ctx, 3000, 9999, 1)
+func findNextPort(ctx context.Context, pm *process.ProcessManager) *nextPortReport {
+	devPorts := devPortRange()
+	available, err := pm.FindAvailablePorts(ctx, devPorts.Start, devPorts.End, 1)
 	if err != nil {
-		color.Red("Error finding available ports: %v", err)
-		return
+		exitWithError(quickJSON, exitCodeError, "Error finding available ports: %v", err)
 	}
 
 	if len(available) == 0 {
-		color.Yellow("No available ports found in range 3000-9999")
-		return
+		if quickJSON {
+			exitWithError(true, exitCodeError, "No available ports found in range %s", devPorts)
+		}
+		color.Yellow("No available ports found in range %s", devPorts)
+		return nil
 	}
 
 	port := available[0]
+	if quickJSON {
+		return &nextPortReport{Port: port}
+	}
+
 	color.Green("🎯 Next available port: %d", port)
 
 	// Show export commands
@@ -310,10 +378,12 @@ func findNextPort(ctx context.Context, pm *process.ProcessManager) {
 		// Actually export the PORT environment variable
 		if err := os.Setenv("PORT", strconv.Itoa(port)); err != nil {
 			// TODO: handle error appropriately (log, return, etc.)
-			return
+			return nil
 		}
 		color.Green("✅ Exported PORT=%d to current shell", port)
 	}
+
+	return &nextPortReport{Port: port}
 }
 
 func init() {
@@ -321,4 +391,10 @@ func init() {
 
 	quickCmd.Flags().BoolVar(&quickExport, "export", false,
 		"Export the PORT environment variable (for next-port)")
+	quickCmd.Flags().BoolVarP(&quickJSON, "json", "j", false,
+		"Output results in JSON format")
+	quickCmd.Flags().BoolVar(&quickAllUsers, "all-users", false,
+		"Let kill actions target other users' processes, not just your own")
+	quickCmd.Flags().BoolVar(&quickBatchOK, "confirm-batch", false,
+		"Allow kill actions to kill more processes than the kill.max-batch limit")
 }
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var reniceValue int
+
+var reniceCmd = &cobra.Command{
+	Use:   "renice <pid> --value N",
+	Short: "Change the scheduling priority of a process",
+	Long: `Set the nice value of a process, from -20 (highest priority) to 19 (lowest).
+
+Lowering the priority of your own processes is always allowed; raising it, or
+changing another user's process, usually requires root. On Windows the value
+is mapped to the nearest priority class (idle, below normal, normal, above
+normal or high).
+
+Use "portctl list --columns nice" to see current values.
+
+Examples:
+  portctl renice 12345 --value 10     # Let a batch job yield to everything else
+  sudo portctl renice 12345 -n -5     # Give a latency-sensitive server more CPU`,
+	Args: cobra.ExactArgs(1),
+	Run:  runRenice,
+}
+
+func runRenice(cmd *cobra.Command, args []string) {
+	pid, err := strconv.Atoi(args[0])
+	if err != nil || pid <= 0 {
+		exitWithError(false, exitCodeUsage, "Invalid PID: %s", args[0])
+	}
+	if !cmd.Flags().Changed("value") {
+		exitWithError(false, exitCodeUsage, "--value is required")
+	}
+
+	pm := newProcessManager()
+	ctx := cmd.Context()
+
+	if err := pm.Renice(ctx, pid, reniceValue); err != nil {
+		if errors.Is(err, process.ErrPriorityPermission) {
+			exitWithError(false, exitCodeError, "%v", err)
+		}
+		exitWithError(false, exitCodeError, "Error changing priority: %v", err)
+	}
+
+	statusf(color.Green, "✅ Set nice value of PID %d to %d", pid, reniceValue)
+}
+
+func init() {
+	rootCmd.AddCommand(reniceCmd)
+
+	reniceCmd.Flags().IntVarP(&reniceValue, "value", "n", 0,
+		"New nice value, from -20 (highest priority) to 19 (lowest)")
+}
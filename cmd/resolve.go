@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var (
+	resolveFrom string
+	resolveTo   string
+	resolveUDP  bool
+	resolveJSON bool
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <port>",
+	Short: "Show which listener receives traffic on a port",
+	Long: `When several sockets listen on the same port with overlapping addresses,
+such as 0.0.0.0:8080 and 127.0.0.1:8080, show which one receives a client's
+connections. The kernel picks the most specific bind: an exact address beats
+a wildcard, and for IPv4 traffic an IPv4 wildcard beats a dual-stack IPv6
+one. Sockets that tie (SO_REUSEPORT) share the connections.
+
+A loopback client (the default) is assumed to dial its own address. For a
+remote client, the result is shown for each address of this host that a
+listener is bound to, and for any other address. Use --to to ask about one
+destination address directly.
+
+Examples:
+  portctl resolve 8080                       # Local client on 127.0.0.1
+  portctl resolve 8080 --from 192.168.1.20   # Client on the LAN
+  portctl resolve 8080 --to ::1              # Connections to [::1]:8080
+  portctl resolve 5353 --udp --json`,
+	Args: cobra.ExactArgs(1),
+	Run:  runResolve,
+}
+
+func runResolve(cmd *cobra.Command, args []string) {
+	port, err := strconv.Atoi(args[0])
+	if err != nil || port < process.MinPort || port > process.MaxPort {
+		exitWithError(resolveJSON, exitCodeUsage, "Invalid port number: %s", args[0])
+	}
+	from, err := netip.ParseAddr(resolveFrom)
+	if err != nil {
+		exitWithError(resolveJSON, exitCodeUsage, "Invalid --from address %q: must be an IP address", resolveFrom)
+	}
+	var to netip.Addr
+	if resolveTo != "" {
+		if to, err = netip.ParseAddr(resolveTo); err != nil {
+			exitWithError(resolveJSON, exitCodeUsage, "Invalid --to address %q: must be an IP address", resolveTo)
+		}
+	}
+
+	protocol := "tcp"
+	if resolveUDP {
+		protocol = "udp"
+	}
+
+	processes, err := newProcessManager().GetProcessesOnPort(cmd.Context(), port)
+	if err != nil {
+		exitWithError(resolveJSON, exitCodeError, "Error getting processes on port %d: %v", port, err)
+	}
+
+	var routes []process.ListenerRoute
+	if to.IsValid() {
+		routes = []process.ListenerRoute{process.ResolveListener(processes, port, protocol, to)}
+	} else {
+		routes = process.ResolveTraffic(processes, port, protocol, from)
+	}
+
+	if resolveJSON {
+		writeJSON(routes)
+		return
+	}
+
+	for i, route := range routes {
+		if i > 0 {
+			fmt.Println()
+		}
+		printListenerRoute(route, port, protocol)
+	}
+}
+
+// printListenerRoute prints who receives the traffic for one destination,
+// why, and which listeners it bypasses
+func printListenerRoute(route process.ListenerRoute, port int, protocol string) {
+	destination := fmt.Sprintf("%s, port %d/%s", route.Destination, port, protocol)
+	if addr, err := netip.ParseAddr(route.Destination); err == nil {
+		destination = fmt.Sprintf("%s/%s", netip.AddrPortFrom(addr, uint16(port)), protocol)
+	}
+
+	if len(route.Winners) == 0 {
+		color.Yellow("%s: nothing receives this traffic", destination)
+	} else {
+		color.Green("%s is received by:", destination)
+		for _, proc := range route.Winners {
+			fmt.Printf("  %s (PID %d) on %s\n", proc.Command, proc.PID, proc.LocalAddr)
+		}
+	}
+	statusf(color.Cyan, "  Why: %s", route.Reason)
+
+	if len(route.Shadowed) > 0 {
+		fmt.Println("  Not reached:")
+		for _, proc := range route.Shadowed {
+			fmt.Printf("    %s (PID %d) on %s\n", proc.Command, proc.PID, proc.LocalAddr)
+		}
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+
+	resolveCmd.Flags().StringVar(&resolveFrom, "from", "127.0.0.1",
+		"Address of the client sending the traffic")
+	resolveCmd.Flags().StringVar(&resolveTo, "to", "",
+		"Destination address the client dials, instead of inferring it from --from")
+	resolveCmd.Flags().BoolVar(&resolveUDP, "udp", false,
+		"Resolve UDP sockets instead of TCP")
+	resolveCmd.Flags().BoolVarP(&resolveJSON, "json", "j", false,
+		"Output the routes in JSON format")
+}
@@ -2,8 +2,26 @@ package cmd
 
 import (
 	"fmt"
-	"github.com/spf13/cobra"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var (
+	enumTimeout time.Duration
+	debugLog    bool
+	quietOutput bool
+	deepEnum    bool
+	jsonCompact bool
+	netNS       string
+	configPath  string
 )
 
 var rootCmd = &cobra.Command{
@@ -19,6 +37,22 @@ Examples:
   portctl kill 8080          # Kill processes on port 8080
   portctl kill --pid 12345   # Kill process by PID`,
 	Version: "1.0.0",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if debugLog {
+			slog.SetLogLoggerLevel(slog.LevelDebug)
+		}
+		if err := initConfig(); err != nil {
+			// A config problem is not a usage mistake, so skip the usage dump
+			cmd.SilenceUsage = true
+			return err
+		}
+		if err := applyConfigToFlags(cmd); err != nil {
+			cmd.SilenceUsage = true
+			return err
+		}
+		loadServiceDefinitions()
+		return nil
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -29,6 +63,49 @@ func Execute() {
 	}
 }
 
+// newProcessManager creates a ProcessManager configured from the global flags
+func newProcessManager() *process.ProcessManager {
+	return process.NewProcessManager().WithTimeout(enumTimeout).WithDeep(deepEnum).WithNetNS(netNS)
+}
+
+// printPrivilegeHint warns on stderr when a listing is likely missing processes
+// owned by other users
+func printPrivilegeHint(hint process.PrivilegeHint) {
+	if !hint.Incomplete() {
+		return
+	}
+
+	ports := make([]string, 0, len(hint.HiddenPorts))
+	for i, port := range hint.HiddenPorts {
+		if i == 5 {
+			ports = append(ports, "...")
+			break
+		}
+		ports = append(ports, strconv.Itoa(port))
+	}
+
+	fmt.Fprintln(os.Stderr, color.YellowString(
+		"⚠️  %d listening port(s) have owners hidden from this user (%s); run with sudo to see all processes",
+		len(hint.HiddenPorts), strings.Join(ports, ", ")))
+}
+
 func init() {
 	rootCmd.Flags().BoolP("version", "v", false, "Show version")
+	rootCmd.PersistentFlags().StringVar(&configPath, "config", "",
+		"Config file to use instead of ~/.config/portctl/config.yaml")
+	rootCmd.PersistentFlags().DurationVar(&enumTimeout, "enum-timeout", process.DefaultEnumerationTimeout,
+		"Maximum time for process enumeration commands (lsof/netstat); 0 disables")
+	// Appended here because rootCmd in configFlagBindings would create an
+	// initialization cycle
+	configFlagBindings = append(configFlagBindings, configFlagBinding{rootCmd, "enum-timeout", "enum.timeout"})
+	rootCmd.PersistentFlags().BoolVarP(&quietOutput, "quiet", "q", false,
+		"Suppress headers, spinners, tips and footers; print only results")
+	rootCmd.PersistentFlags().BoolVar(&deepEnum, "deep", false,
+		"Query lsof, netstat and /proc together and merge the results (slower, more complete)")
+	rootCmd.PersistentFlags().StringVar(&netNS, "netns", "",
+		"List sockets in another Linux network namespace (PID, 'ip netns' name, or namespace file path)")
+	rootCmd.PersistentFlags().BoolVar(&jsonCompact, "json-compact", false,
+		"Write --json output on a single line instead of indented")
+	rootCmd.PersistentFlags().BoolVar(&debugLog, "debug", false,
+		"Write debug logs (e.g. process cache hits and misses) to stderr")
 }
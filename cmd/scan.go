@@ -1,12 +1,25 @@
 package cmd
 
 import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"net"
+	"net/netip"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/briandowns/spinner"
@@ -14,6 +27,7 @@ import (
 	tablepretty "github.com/jedib0t/go-pretty/v6/table"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
 
 	process "dagger/portctl/pkg"
 )
@@ -24,26 +38,84 @@ var (
 	scanRange      string
 	scanCommon     bool
 	scanUDP        bool
+	scanJSON       bool
+	scanBrief      bool
+	scanRetries    int
+	scanIPv4       bool
+	scanIPv6       bool
+	scanSummary    bool
+	scanRate       float64
+	scanShow       string
+	scanHostsFile  string
+	scanMaxResults int
+	scanCacheTTL   time.Duration
+	scanNoCache    bool
+	scanRefresh    bool
 )
 
+// maxScanCIDRHostBits caps CIDR expansion at 65536 addresses (an IPv4 /16 or IPv6 /112)
+const maxScanCIDRHostBits = 16
+
 type ScanResult struct {
-	Port     int
-	Host     string
-	Protocol string
-	Status   string
-	Service  string
-	Banner   string
-	Error    error
+	Port     int    `json:"port"`
+	Host     string `json:"host"`
+	Protocol string `json:"protocol"`
+	Address  string `json:"address,omitempty"` // Resolved IP the port was reached on
+	Family   string `json:"family,omitempty"`  // "ipv4" or "ipv6"
+	Status   string `json:"status"`
+	Service  string `json:"service,omitempty"`
+	Banner   string `json:"banner,omitempty"`
+	Error    error  `json:"-"`
+}
+
+// scanStatuses are the possible ScanResult statuses, in display order
+var scanStatuses = []string{"open", "closed", "filtered", "error"}
+
+// ScanSummary aggregates the results of a scan
+type ScanSummary struct {
+	Host          string         `json:"host"`
+	Scanned       int            `json:"scanned"`
+	StatusCounts  map[string]int `json:"status_counts"`
+	OpenByService map[string]int `json:"open_by_service"`
 }
 
 var scanCmd = &cobra.Command{
-	Use:   "scan [host] [port|port-range]",
+	Use:   "scan [host|cidr] [port|port-range]",
 	Short: "Scan ports on local or remote hosts",
 	Long: `Scan for open ports on local or remote hosts with service detection.
 
 This command performs TCP/UDP port scans with banner grabbing and service
 identification. Useful for network discovery and security assessment.
 
+--concurrent caps how many connections are in flight at once, while --rate
+caps how many are started per second (retries included), shared by all
+workers. Use --rate for polite scans of hosts behind rate-based firewalls,
+which otherwise start dropping packets and make open ports look filtered;
+with a low --rate, a high --concurrent only matters for slow responses.
+
+The host may be a CIDR block such as 192.168.1.0/24 (at most 65536 addresses),
+which is expanded to every address in it. --hosts reads hosts and CIDR blocks
+from a file, one per line; blank lines and "#" comments are ignored. All
+host/port pairs share the same --concurrent and --rate limits, and results are
+grouped by host.
+
+Only the ports selected by --show are kept in memory (none with --summary,
+which just counts them), so a wide range scanned for open ports stays small.
+--max-results caps how many are kept, for huge scans with --show all; the
+first ones in scan order are shown and the rest only counted.
+
+Pressing Ctrl+C stops the scan without starting new connections; the results
+gathered so far are shown (in the usual format, including --json) and portctl
+exits with status 1. Ports whose probe was cut short are left out.
+
+With --cache-ttl (or scan.cache-ttl in the config), open, closed and filtered
+results are remembered per host, port and address family in scan-cache.json
+next to the config file, and a repeat scan within the TTL reuses them instead
+of redialling; only missing or stale ports are probed. --refresh probes every
+port again and updates the cache, and --no-cache neither reads nor writes it.
+Banners are cached with the result, so re-probe with --refresh to see a
+service that changed.
+
 Examples:
   # Scan common ports on localhost
   portctl scan localhost --common
@@ -55,135 +127,723 @@ Examples:
   # Advanced scanning
   portctl scan example.com 1-1000 --timeout 2s
   portctl scan localhost --udp --range "53,67,68"
+  portctl scan example.com 80,443 -6   # Only connect over IPv6 (AAAA records)
+  portctl scan example.com 80,443 -4   # Only connect over IPv4
   
   # Fast concurrent scan
-  portctl scan 192.168.1.0/24 --common --concurrent 100`,
+  portctl scan 192.168.1.0/24 --common --concurrent 100
+  portctl scan localhost 1-65535 --concurrent 500 --retries 3  # Retry transient failures
+
+  # Scan a fleet listed in a file
+  portctl scan --hosts hosts.txt --common
+  portctl scan --hosts hosts.txt 22,443 --summary
+
+  # Polite scan: at most 20 connection attempts per second
+  portctl scan 192.168.1.1 1-1000 --rate 20
+
+  # Machine-readable output
+  portctl scan localhost --common --json
+  portctl scan localhost --common --markdown  # Paste into an issue or doc
+
+  # One "host port service" line per open port, for grep/awk
+  portctl scan localhost --common --service-only
+
+  # Which ports are explicitly closed rather than filtered
+  portctl scan 192.168.1.1 1-1000 --show closed
+  portctl scan 192.168.1.1 1-1000 --show all --json
+
+  # Only counts by status and open ports by service
+  portctl scan localhost 1-10000 --summary
+
+  # Re-scan while iterating on a service, probing only ports not seen in 10m
+  portctl scan 192.168.1.1 1-10000 --cache-ttl 10m
+  portctl scan 192.168.1.1 8080 --cache-ttl 10m --refresh  # Re-probe, update the cache`,
 	Aliases: []string{"portscan", "nmap"},
-	Args:    cobra.RangeArgs(1, 2),
-	Run:     runScan,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if scanHostsFile != "" {
+			if len(args) > 1 {
+				return fmt.Errorf("with --hosts, only the ports may be given as an argument")
+			}
+			return nil
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
+	Run: runScan,
 }
 
 func runScan(cmd *cobra.Command, args []string) {
-	host := args[0]
-	if host == "" {
-		host = "localhost"
+	if scanJSON && scanBrief {
+		exitWithError(scanJSON, exitCodeUsage, "--json and --service-only cannot be combined")
+	}
+	if scanSummary && scanBrief {
+		exitWithError(scanJSON, exitCodeUsage, "--summary and --service-only cannot be combined")
+	}
+	if tableMarkdown && (scanJSON || scanBrief || scanSummary) {
+		exitWithError(scanJSON, exitCodeUsage, "--markdown cannot be combined with --json, --service-only or --summary")
+	}
+	if scanBrief {
+		// Plain text only, so the output can be piped
+		color.NoColor = true
+	}
+	if tableMarkdown {
+		disableColors()
+	}
+	show, err := parseScanShow(scanShow)
+	if err != nil {
+		exitWithError(scanJSON, exitCodeUsage, "%v", err)
+	}
+	if scanMaxResults < 0 {
+		exitWithError(scanJSON, exitCodeUsage, "--max-results must not be negative")
+	}
+	if scanRate < 0 {
+		exitWithError(scanJSON, exitCodeUsage, "--rate must not be negative")
+	}
+	if scanIPv4 && scanIPv6 {
+		exitWithError(scanJSON, exitCodeUsage, "-4 and -6 cannot be combined")
+	}
+	if scanCacheTTL < 0 {
+		exitWithError(scanJSON, exitCodeUsage, "--cache-ttl must not be negative")
+	}
+	if scanRefresh && (scanNoCache || scanCacheTTL == 0) {
+		exitWithError(scanJSON, exitCodeUsage, "--refresh needs the scan cache: set --cache-ttl or scan.cache-ttl, without --no-cache")
+	}
+
+	var hosts []string
+	portArgs := args
+	if scanHostsFile != "" {
+		hosts, err = readScanHosts(scanHostsFile)
+		if err != nil {
+			exitWithError(scanJSON, exitCodeUsage, "Error reading hosts from %s: %v", scanHostsFile, err)
+		}
+		if len(hosts) == 0 {
+			exitWithError(scanJSON, exitCodeUsage, "No hosts found in %s", scanHostsFile)
+		}
+	} else {
+		host := args[0]
+		if host == "" {
+			host = "localhost"
+		}
+		hosts, err = expandScanHost(host)
+		if err != nil {
+			exitWithError(scanJSON, exitCodeUsage, "%v", err)
+		}
+		portArgs = args[1:]
+	}
+
+	// Describes the scan target in messages and the summary
+	target := hosts[0]
+	if len(hosts) > 1 {
+		target = fmt.Sprintf("%d hosts", len(hosts))
 	}
 
 	var ports []int
-	var err error
 
 	if scanCommon {
 		ports = process.CommonPorts
 	} else if scanRange != "" {
-		ports, err = parsePortRange(scanRange)
+		ports, err = process.ParsePorts(scanRange)
 		if err != nil {
-			color.Red("Error parsing port range: %v", err)
-			os.Exit(1)
+			exitWithError(scanJSON, exitCodeUsage, "Error parsing port range: %v", err)
 		}
-	} else if len(args) > 1 {
-		ports, err = parsePortRange(args[1])
+	} else if len(portArgs) > 0 {
+		ports, err = process.ParsePorts(portArgs[0])
 		if err != nil {
-			color.Red("Error parsing ports: %v", err)
-			os.Exit(1)
+			exitWithError(scanJSON, exitCodeUsage, "Error parsing ports: %v", err)
 		}
 	} else {
-		color.Red("Please specify ports to scan or use --common")
-		os.Exit(1)
+		exitWithError(scanJSON, exitCodeUsage, "Please specify ports to scan or use --common")
 	}
 
-	color.Cyan("🔍 Scanning %s for %d port(s)...", host, len(ports))
+	// Fail early if a single host has no address in the requested family
+	if network := scanNetwork(); network != "tcp" && len(hosts) == 1 {
+		ipNetwork := "ip4"
+		if network == "tcp6" {
+			ipNetwork = "ip6"
+		}
+		if _, err := net.DefaultResolver.LookupIP(cmd.Context(), ipNetwork, hosts[0]); err != nil {
+			exitWithError(scanJSON, exitCodeError, "Cannot resolve %s over %s: %v", hosts[0], ipNetwork, err)
+		}
+	}
 
-	// Start spinner
-	s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
-	_ = s.Color("cyan") // Ignore color error, not critical
-	s.Suffix = fmt.Sprintf(" Scanning %d ports ", len(ports))
-	s.Start()
+	// Ctrl+C stops the scan; whatever finished is still shown before exiting
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	results := scanPorts(host, ports)
-	s.Stop()
+	// Only the shown ports are kept in memory; the rest are just counted
+	keep := show
+	if scanSummary {
+		keep = nil
+	}
+	collector := newScanCollector(target, keep, scanMaxResults)
 
-	// Filter open ports
-	var openPorts []ScanResult
-	for _, result := range results {
-		if result.Status == "open" {
-			openPorts = append(openPorts, result)
+	var cache *scanCache
+	if scanCacheTTL > 0 && !scanNoCache {
+		cache, err = loadScanCache(scanCachePath(), scanCacheTTL, scanNetwork(), scanRefresh)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, color.YellowString("⚠️  Ignoring scan cache: %v", err))
+		}
+	}
+
+	if scanJSON || scanBrief || tableMarkdown || quietOutput || tableNoHeader {
+		scanHosts(ctx, hosts, ports, nil, collector, cache)
+	} else {
+		total := len(hosts) * len(ports)
+		statusf(color.Cyan, "🔍 Scanning %s for %d port(s)...", target, len(ports))
+
+		// Start spinner
+		s := spinner.New(spinner.CharSets[14], 100*time.Millisecond)
+		_ = s.Color("cyan") // Ignore color error, not critical
+		s.Suffix = fmt.Sprintf(" Scanning %d ports ", total)
+		s.Start()
+
+		// The spinner does not start when stdout is not a terminal; skip progress too
+		var completed atomic.Int64
+		stopProgress := func() {}
+		if s.Active() {
+			stopProgress = reportScanProgress(s, &completed, total)
+		}
+
+		scanHosts(ctx, hosts, ports, &completed, collector, cache)
+		stopProgress()
+		s.Stop()
+	}
+
+	if cache != nil {
+		if err := cache.save(); err != nil {
+			fmt.Fprintln(os.Stderr, color.YellowString("⚠️  Could not save scan cache: %v", err))
+		}
+		if cache.hits > 0 {
+			statusf(color.Cyan, "♻️  Reused %d cached result(s) younger than %s; --refresh probes them again",
+				cache.hits, scanCacheTTL)
+		}
+	}
+
+	// An interrupted scan still shows its partial results and exits non-zero
+	// only at the end, once the cache is saved and Ctrl+C is released again
+	exitCode := 0
+	if ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr, color.YellowString("⚠️  Scan interrupted after %d of %d port(s); showing partial results",
+			collector.summary.Scanned, len(hosts)*len(ports)))
+		exitCode = exitCodeError
+	}
+	stop()
+
+	printScanOutput(collector, hosts, target, show)
+	if exitCode != 0 {
+		os.Exit(exitCode)
+	}
+}
+
+// printScanOutput shows the results held by collector in the format chosen
+// by the scan flags
+func printScanOutput(collector *scanCollector, hosts []string, target string, show []string) {
+	if scanSummary {
+		if scanJSON {
+			writeJSON(collector.summary)
+		} else {
+			displayScanSummary(collector.summary)
 		}
+		return
+	}
+
+	shown := collector.results()
+	if dropped := collector.truncated(); dropped > 0 {
+		fmt.Fprintln(os.Stderr, color.YellowString("⚠️  Showing the first %d of %d matching port(s); raise --max-results to see the rest",
+			len(shown), len(shown)+dropped))
 	}
 
-	if len(openPorts) == 0 {
-		color.Yellow("No open ports found on %s", host)
+	if scanJSON {
+		writeJSON(shown)
 		return
 	}
 
-	color.Green("✅ Found %d open port(s) on %s:", len(openPorts), host)
-	displayScanResults(openPorts)
+	if scanBrief {
+		displayScanServices(shown)
+		return
+	}
+
+	label := scanShowLabel(show)
+	if len(shown) == 0 {
+		statusf(color.Yellow, "No %s ports found on %s", label, target)
+		return
+	}
+
+	tableStatusf(color.Green, "✅ Found %d %s port(s) on %s:", len(shown), label, target)
+	if len(hosts) == 1 {
+		displayScanResults(shown)
+		return
+	}
+
+	// Results are already ordered by host
+	for start := 0; start < len(shown); {
+		end := start + 1
+		for end < len(shown) && shown[end].Host == shown[start].Host {
+			end++
+		}
+		fmt.Println()
+		if tableMarkdown {
+			fmt.Printf("### %s (%d port(s))\n\n", shown[start].Host, end-start)
+		} else {
+			color.Cyan("🖥️  %s (%d port(s))", shown[start].Host, end-start)
+		}
+		displayScanResults(shown[start:end])
+		start = end
+	}
 }
 
-func parsePortRange(portStr string) ([]int, error) {
-	var ports []int
+// readScanHosts reads hosts and CIDR blocks from path ("-" for stdin), one
+// per line, ignoring blank lines and "#" comments. CIDR blocks are expanded
+// and repeated hosts dropped, keeping the order of first appearance.
+func readScanHosts(path string) ([]string, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	ranges := strings.Split(portStr, ",")
-	for _, r := range ranges {
-		r = strings.TrimSpace(r)
+	var hosts []string
+	seen := make(map[string]bool)
+	for i, line := range strings.Split(string(data), "\n") {
+		if comment := strings.Index(line, "#"); comment >= 0 {
+			line = line[:comment]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
 
-		if strings.Contains(r, "-") {
-			// Handle range like "80-90"
-			parts := strings.Split(r, "-")
-			if len(parts) != 2 {
-				return nil, fmt.Errorf("invalid range format: %s", r)
+		expanded, err := expandScanHost(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", i+1, err)
+		}
+		for _, host := range expanded {
+			if !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
 			}
+		}
+	}
+	return hosts, nil
+}
 
-			start, err := strconv.Atoi(strings.TrimSpace(parts[0]))
-			if err != nil {
-				return nil, fmt.Errorf("invalid start port: %s", parts[0])
-			}
+// expandScanHost returns every address in a CIDR block such as
+// 192.168.1.0/24, leaving out the IPv4 network and broadcast addresses, or
+// spec itself if it is a plain host name or address
+func expandScanHost(spec string) ([]string, error) {
+	if !strings.Contains(spec, "/") {
+		return []string{spec}, nil
+	}
 
-			end, err := strconv.Atoi(strings.TrimSpace(parts[1]))
-			if err != nil {
-				return nil, fmt.Errorf("invalid end port: %s", parts[1])
-			}
+	prefix, err := netip.ParsePrefix(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CIDR block %q: %v", spec, err)
+	}
+	prefix = prefix.Masked()
 
-			if start > end {
-				return nil, fmt.Errorf("start port must be less than end port")
-			}
+	hostBits := prefix.Addr().BitLen() - prefix.Bits()
+	if hostBits > maxScanCIDRHostBits {
+		return nil, fmt.Errorf("CIDR block %s is too large to scan (at most %d addresses)", spec, 1<<maxScanCIDRHostBits)
+	}
 
-			for port := start; port <= end; port++ {
-				ports = append(ports, port)
-			}
-		} else {
-			// Single port
-			port, err := strconv.Atoi(r)
-			if err != nil {
-				return nil, fmt.Errorf("invalid port: %s", r)
+	hosts := make([]string, 0, 1<<hostBits)
+	for addr := prefix.Addr(); addr.IsValid() && prefix.Contains(addr); addr = addr.Next() {
+		hosts = append(hosts, addr.String())
+	}
+	if prefix.Addr().Is4() && hostBits >= 2 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+	return hosts, nil
+}
+
+// parseScanShow parses the --show value: "all" or a comma-separated list of
+// scan statuses. The result lists the selected statuses in display order.
+func parseScanShow(spec string) ([]string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	if spec == "all" {
+		return scanStatuses, nil
+	}
+
+	selected := make(map[string]bool)
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if !slices.Contains(scanStatuses, part) {
+			return nil, fmt.Errorf("invalid --show value %q (valid: all, %s)", part, strings.Join(scanStatuses, ", "))
+		}
+		selected[part] = true
+	}
+
+	var show []string
+	for _, status := range scanStatuses {
+		if selected[status] {
+			show = append(show, status)
+		}
+	}
+	return show, nil
+}
+
+// scanShowLabel describes the selected statuses for messages, e.g. "open" or "closed/filtered"
+func scanShowLabel(show []string) string {
+	if len(show) == len(scanStatuses) {
+		return "scanned"
+	}
+	return strings.Join(show, "/")
+}
+
+// scanProgressInterval is how often the spinner's progress suffix is refreshed
+const scanProgressInterval = 200 * time.Millisecond
+
+// reportScanProgress updates the spinner with completed/total and an ETA until
+// the returned stop function is called
+func reportScanProgress(s *spinner.Spinner, completed *atomic.Int64, total int) (stop func()) {
+	start := time.Now()
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(scanProgressInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				suffix := scanProgressSuffix(int(completed.Load()), total, time.Since(start))
+				s.Lock()
+				s.Suffix = suffix
+				s.Unlock()
 			}
-			ports = append(ports, port)
 		}
+	}()
+
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+// scanProgressSuffix formats " Scanning 1200/10000 ports (12%, ETA 1m5s) ".
+// The ETA assumes the remaining ports complete at the average rate so far.
+func scanProgressSuffix(completed, total int, elapsed time.Duration) string {
+	percent := 0
+	if total > 0 {
+		percent = completed * 100 / total
+	}
+
+	eta := "--"
+	if completed > 0 && completed < total {
+		remaining := time.Duration(float64(elapsed) / float64(completed) * float64(total-completed))
+		eta = process.FormatUptime(remaining.Round(time.Second))
+	} else if completed >= total {
+		eta = "0s"
 	}
 
-	return ports, nil
+	return fmt.Sprintf(" Scanning %d/%d ports (%d%%, ETA %s) ", completed, total, percent, eta)
+}
+
+// scanPorts scans every port on host concurrently, preserving the order of
+// ports in the results. If completed is non-nil it is incremented as each
+// port finishes.
+func scanPorts(ctx context.Context, host string, ports []int, completed *atomic.Int64) []ScanResult {
+	collector := newScanCollector(host, scanStatuses, 0)
+	scanHosts(ctx, []string{host}, ports, completed, collector, nil)
+	return collector.results()
 }
 
-func scanPorts(host string, ports []int) []ScanResult {
-	results := make([]ScanResult, len(ports))
-	sem := make(chan struct{}, scanConcurrent)
+// scanHosts scans every port on every host, handing each result to collector,
+// which orders them by host, then by the order of ports. If completed is
+// non-nil it is incremented as each host/port pair finishes. Pairs with a
+// fresh result in cache, if given, are not probed again.
+//
+// When ctx is cancelled no new connections are started and the scan returns
+// with what it has gathered; pairs that were not scanned, or whose dial was
+// cut short, are left out rather than reported as closed.
+//
+// A pool of scanConcurrent workers probes the pairs, so at most that many are
+// in flight across all hosts and, when scanRate is set, all workers share one
+// limiter so connection attempts (including retries) never exceed scanRate
+// per second, however high the concurrency.
+func scanHosts(ctx context.Context, hosts []string, ports []int, completed *atomic.Int64, collector *scanCollector, cache *scanCache) {
+	total := len(hosts) * len(ports)
+	if total == 0 {
+		return
+	}
+
+	limiter := newScanLimiter(scanRate)
+	indexes := make(chan int)
 	var wg sync.WaitGroup
 
-	for i, port := range ports {
+	for w := 0; w < max(1, min(scanConcurrent, total)); w++ {
 		wg.Add(1)
-		go func(idx, p int) {
+		go func() {
 			defer wg.Done()
-			sem <- struct{}{}        // Acquire semaphore
-			defer func() { <-sem }() // Release semaphore
-
-			results[idx] = scanPort(host, p)
-		}(i, port)
+			for i := range indexes {
+				if ctx.Err() != nil {
+					continue
+				}
+				host, port := hosts[i/len(ports)], ports[i%len(ports)]
+				if result, ok := cache.lookup(host, port); ok {
+					collector.add(i, result)
+				} else if result, ok := scanPort(ctx, host, port, limiter); ok {
+					cache.store(result)
+					collector.add(i, result)
+				}
+				if completed != nil {
+					completed.Add(1)
+				}
+			}
+		}()
 	}
 
+feed:
+	for i := 0; i < total; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
 	wg.Wait()
+}
+
+// scanCollector gathers results as the workers finish them. Every result is
+// counted in the summary, but only those whose status is in keep are retained,
+// and at most limit of them (0 = no limit), so scanning a large range for open
+// ports holds only the open ones in memory rather than one result per pair.
+type scanCollector struct {
+	mu      sync.Mutex
+	keep    []string
+	limit   int
+	summary ScanSummary
+	matched int // results whose status is in keep, retained or not
+	kept    indexedResults
+}
+
+func newScanCollector(host string, keep []string, limit int) *scanCollector {
+	return &scanCollector{keep: keep, limit: limit, summary: newScanSummary(host)}
+}
+
+// add records the result for the pair at index in scan order. Once limit
+// results are retained, the one latest in scan order is dropped, so the
+// retained results are always the first limit matches whatever order the
+// workers finish in.
+func (c *scanCollector) add(index int, result ScanResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.summary.add(result)
+	if !slices.Contains(c.keep, result.Status) {
+		return
+	}
+	c.matched++
+	if c.limit > 0 && len(c.kept) >= c.limit {
+		if index > c.kept[0].index {
+			return
+		}
+		heap.Pop(&c.kept)
+	}
+	heap.Push(&c.kept, indexedResult{index: index, result: result})
+}
+
+// results returns the retained results in scan order
+func (c *scanCollector) results() []ScanResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	sorted := slices.Clone(c.kept)
+	slices.SortFunc(sorted, func(a, b indexedResult) int { return a.index - b.index })
+	results := make([]ScanResult, len(sorted))
+	for i, r := range sorted {
+		results[i] = r.result
+	}
 	return results
 }
 
-func scanPort(host string, port int) ScanResult {
+// truncated reports how many matching results were dropped by the limit
+func (c *scanCollector) truncated() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.matched - len(c.kept)
+}
+
+type indexedResult struct {
+	index  int
+	result ScanResult
+}
+
+// indexedResults is a max-heap on index, for container/heap
+type indexedResults []indexedResult
+
+func (h indexedResults) Len() int           { return len(h) }
+func (h indexedResults) Less(i, j int) bool { return h[i].index > h[j].index }
+func (h indexedResults) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *indexedResults) Push(x any)        { *h = append(*h, x.(indexedResult)) }
+func (h *indexedResults) Pop() any {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// scanCacheFile is the name of the scan cache, kept next to the config file
+const scanCacheFile = "scan-cache.json"
+
+// scanCachePath returns where the scan cache is stored
+func scanCachePath() string {
+	return filepath.Join(filepath.Dir(getConfigFile()), scanCacheFile)
+}
+
+// scanCache remembers recent probe results on disk, keyed by address family,
+// host and port, so that repeated scans only redial ports whose result is
+// missing or older than ttl. Its methods are safe for concurrent use by the
+// scan workers, and do nothing on a nil cache.
+type scanCache struct {
+	mu      sync.Mutex
+	path    string
+	ttl     time.Duration
+	network string // scanNetwork() of this scan: tcp, tcp4 or tcp6
+	refresh bool   // probe everything again, but still record the results
+	entries map[string]scanCacheEntry
+	dirty   bool
+	hits    int
+}
+
+// scanCacheEntry is one remembered result and when it was probed
+type scanCacheEntry struct {
+	Result  ScanResult `json:"result"`
+	Scanned time.Time  `json:"scanned"`
+}
+
+// loadScanCache reads the cache at path, dropping entries older than ttl. A
+// missing file is an empty cache. On error the returned cache is still usable
+// and starts empty, so a damaged file is replaced on save.
+func loadScanCache(path string, ttl time.Duration, network string, refresh bool) (*scanCache, error) {
+	c := &scanCache{
+		path:    path,
+		ttl:     ttl,
+		network: network,
+		refresh: refresh,
+		entries: make(map[string]scanCacheEntry),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return c, err
+	}
+	if err := json.Unmarshal(data, &c.entries); err != nil {
+		c.entries = make(map[string]scanCacheEntry)
+		c.dirty = true
+		return c, fmt.Errorf("%s is not a valid scan cache: %v", path, err)
+	}
+
+	for key, entry := range c.entries {
+		if !c.fresh(entry) {
+			delete(c.entries, key)
+			c.dirty = true
+		}
+	}
+	return c, nil
+}
+
+func (c *scanCache) key(host string, port int) string {
+	return c.network + " " + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+func (c *scanCache) fresh(entry scanCacheEntry) bool {
+	return time.Since(entry.Scanned) < c.ttl
+}
+
+// lookup returns the remembered result for host and port if it is fresh
+func (c *scanCache) lookup(host string, port int) (ScanResult, bool) {
+	if c == nil || c.refresh {
+		return ScanResult{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[c.key(host, port)]
+	if !ok || !c.fresh(entry) {
+		return ScanResult{}, false
+	}
+	c.hits++
+	return entry.Result, true
+}
+
+// store remembers a probe result. Errors such as running out of file
+// descriptors say nothing about the port, so they are not cached.
+func (c *scanCache) store(result ScanResult) {
+	if c == nil || result.Status == "error" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[c.key(result.Host, result.Port)] = scanCacheEntry{Result: result, Scanned: time.Now()}
+	c.dirty = true
+}
+
+// save writes the cache back if anything changed, replacing the file
+// atomically so concurrent scans never read a partial cache
+func (c *scanCache) save() error {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.dirty {
+		return nil
+	}
+
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), scanCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// newScanLimiter returns a limiter allowing perSecond connection attempts, with
+// no bursting so attempts are spread evenly. Zero means unlimited.
+func newScanLimiter(perSecond float64) *rate.Limiter {
+	if perSecond <= 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), 1)
+}
+
+// scanPort probes one port. It returns false if ctx was cancelled before the
+// port's state could be determined.
+func scanPort(ctx context.Context, host string, port int, limiter *rate.Limiter) (ScanResult, bool) {
 	result := ScanResult{
 		Port:     port,
 		Host:     host,
@@ -192,10 +852,19 @@ func scanPort(host string, port int) ScanResult {
 	}
 
 	address := net.JoinHostPort(host, strconv.Itoa(port))
-	conn, err := net.DialTimeout("tcp", address, scanTimeout)
+	conn, err := dialWithRetry(ctx, limiter, address)
 	if err != nil {
+		if ctx.Err() != nil {
+			return result, false
+		}
 		result.Error = err
-		return result
+		switch classifyDialError(err) {
+		case dialTimeout:
+			result.Status = "filtered"
+		case dialResource:
+			result.Status = "error"
+		}
+		return result, true
 	}
 	defer func() {
 		// Best effort close, ignore error as we are done with the connection
@@ -204,21 +873,102 @@ func scanPort(host string, port int) ScanResult {
 
 	result.Status = "open"
 	result.Service = process.GetServiceName(port)
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		result.Address = addr.IP.String()
+		result.Family = "ipv6"
+		if addr.IP.To4() != nil {
+			result.Family = "ipv4"
+		}
+	}
 
 	// Try to grab banner
-	banner := grabBanner(conn, port)
+	banner := grabBanner(ctx, conn, port)
 	if banner != "" {
 		result.Banner = banner
 	}
 
-	return result
+	return result, true
+}
+
+// scanNetwork returns the dial network for the selected address family
+func scanNetwork() string {
+	switch {
+	case scanIPv4:
+		return "tcp4"
+	case scanIPv6:
+		return "tcp6"
+	default:
+		return "tcp"
+	}
+}
+
+// scanRetryBackoff is the delay before the first retry; it doubles on each attempt
+const scanRetryBackoff = 50 * time.Millisecond
+
+// dialOutcome classifies why a connection attempt failed
+type dialOutcome int
+
+const (
+	dialRefused  dialOutcome = iota // Port is closed
+	dialTimeout                     // No response; filtered, or a transient drop
+	dialResource                    // Local resource exhaustion (EAGAIN, EMFILE, ...)
+	dialOther                       // Unreachable host, DNS failure, etc.
+)
+
+// classifyDialError distinguishes a closed port from failures worth retrying
+func classifyDialError(err error) dialOutcome {
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return dialRefused
+	case errors.Is(err, syscall.EAGAIN),
+		errors.Is(err, syscall.EADDRNOTAVAIL),
+		errors.Is(err, syscall.EMFILE),
+		errors.Is(err, syscall.ENFILE),
+		errors.Is(err, syscall.ENOBUFS):
+		return dialResource
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return dialTimeout
+	}
+	return dialOther
 }
 
-func grabBanner(conn net.Conn, port int) string {
+// dialWithRetry connects to address, retrying timeouts and resource errors up
+// to scanRetries times with exponential backoff. Refused connections are
+// definitive and returned immediately. Every attempt waits for the limiter.
+func dialWithRetry(ctx context.Context, limiter *rate.Limiter, address string) (net.Conn, error) {
+	for attempt := 0; ; attempt++ {
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		dialer := net.Dialer{Timeout: scanTimeout}
+		conn, err := dialer.DialContext(ctx, scanNetwork(), address)
+		if err == nil {
+			return conn, nil
+		}
+
+		outcome := classifyDialError(err)
+		if attempt >= scanRetries || (outcome != dialTimeout && outcome != dialResource) {
+			return nil, err
+		}
+		select {
+		case <-time.After(scanRetryBackoff << attempt):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func grabBanner(ctx context.Context, conn net.Conn, port int) string {
 	// Set read deadline
 	if err := conn.SetReadDeadline(time.Now().Add(3 * time.Second)); err != nil {
 		return ""
 	}
+	// Cut the read short if the scan is cancelled
+	stop := context.AfterFunc(ctx, func() { _ = conn.SetReadDeadline(time.Now()) })
+	defer stop()
 
 	// Send HTTP request for web services
 	if port == 80 || port == 8080 || port == 443 {
@@ -250,16 +1000,17 @@ func displayScanResults(results []ScanResult) {
 	t.SetStyle(tablepretty.StyleColoredBright)
 
 	// Set header and header color
-	t.AppendHeader(tablepretty.Row{"Port", "Protocol", "Service", "Status", "Banner"})
+	appendTableHeader(t, tablepretty.Row{"Port", "Protocol", "Address", "Service", "Status", "Banner"})
 	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
 
 	// Set column configs for alignment and color
 	t.SetColumnConfigs([]tablepretty.ColumnConfig{
 		{Number: 1, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}}, // Port
 		{Number: 2, Align: text.AlignCenter},                                             // Protocol
-		{Number: 3, Align: text.AlignLeft, Colors: text.Colors{text.Bold}},               // Service
-		{Number: 4, Align: text.AlignCenter},                                             // Status
-		{Number: 5, Align: text.AlignLeft, Colors: text.Colors{text.FgYellow}},           // Banner
+		{Number: 3, Align: text.AlignLeft},                                               // Address
+		{Number: 4, Align: text.AlignLeft, Colors: text.Colors{text.Bold}},               // Service
+		{Number: 5, Align: text.AlignCenter},                                             // Status
+		{Number: 6, Align: text.AlignLeft, Colors: text.Colors{text.FgYellow}},           // Banner
 	})
 
 	for _, result := range results {
@@ -271,6 +1022,7 @@ func displayScanResults(results []ScanResult) {
 		row := tablepretty.Row{
 			result.Port,
 			result.Protocol,
+			result.Address,
 			result.Service,
 			result.Status,
 			banner,
@@ -278,7 +1030,75 @@ func displayScanResults(results []ScanResult) {
 		t.AppendRow(row)
 	}
 
-	t.Render()
+	renderTable(t)
+}
+
+// newScanSummary returns an empty summary with every status counted as zero
+func newScanSummary(host string) ScanSummary {
+	summary := ScanSummary{
+		Host:          host,
+		StatusCounts:  make(map[string]int, len(scanStatuses)),
+		OpenByService: make(map[string]int),
+	}
+	for _, status := range scanStatuses {
+		summary.StatusCounts[status] = 0
+	}
+	return summary
+}
+
+// add counts result by status and, if open, by service
+func (s *ScanSummary) add(result ScanResult) {
+	s.Scanned++
+	s.StatusCounts[result.Status]++
+	if result.Status == "open" {
+		service := result.Service
+		if service == "" {
+			service = "Unknown"
+		}
+		s.OpenByService[service]++
+	}
+}
+
+func displayScanSummary(summary ScanSummary) {
+	statusf(color.Cyan, "📊 Scan summary for %s (%d port(s))", summary.Host, summary.Scanned)
+
+	counts := make([]string, 0, len(scanStatuses))
+	for _, status := range scanStatuses {
+		counts = append(counts, fmt.Sprintf("%d %s", summary.StatusCounts[status], status))
+	}
+	fmt.Println(strings.Join(counts, ", "))
+
+	if len(summary.OpenByService) == 0 {
+		return
+	}
+
+	services := make([]string, 0, len(summary.OpenByService))
+	for service := range summary.OpenByService {
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		ci, cj := summary.OpenByService[services[i]], summary.OpenByService[services[j]]
+		if ci != cj {
+			return ci > cj
+		}
+		return services[i] < services[j]
+	})
+
+	statusf(color.Cyan, "\nOpen ports by service:")
+	for _, service := range services {
+		fmt.Printf("  %-20s %d\n", service, summary.OpenByService[service])
+	}
+}
+
+// displayScanServices prints one space-separated "host port service" line per result
+func displayScanServices(results []ScanResult) {
+	for _, result := range results {
+		service := result.Service
+		if service == "" {
+			service = "unknown"
+		}
+		fmt.Printf("%s %d %s\n", result.Host, result.Port, strings.ReplaceAll(service, " ", "-"))
+	}
 }
 
 func init() {
@@ -288,10 +1108,41 @@ func init() {
 		"Connection timeout for each port")
 	scanCmd.Flags().IntVarP(&scanConcurrent, "concurrent", "c", 50,
 		"Number of concurrent scans")
+	scanCmd.Flags().Float64Var(&scanRate, "rate", 0,
+		"Maximum connection attempts per second across all workers, including retries (0 = unlimited); --concurrent still caps how many are in flight")
+	scanCmd.Flags().BoolVarP(&scanIPv4, "ipv4", "4", false,
+		"Only connect over IPv4")
+	scanCmd.Flags().BoolVarP(&scanIPv6, "ipv6", "6", false,
+		"Only connect over IPv6 (resolves AAAA records)")
+	scanCmd.Flags().IntVar(&scanRetries, "retries", 1,
+		"Retries for timeouts and transient resource errors (refused connections are not retried)")
 	scanCmd.Flags().StringVarP(&scanRange, "range", "r", "",
 		"Port range to scan (e.g., '80,443,1000-2000')")
+	scanCmd.Flags().StringVar(&scanHostsFile, "hosts", "",
+		"Read hosts and CIDR blocks to scan from a file, one per line, or '-' for stdin")
 	scanCmd.Flags().BoolVar(&scanCommon, "common", false,
 		"Scan common ports (21,22,23,25,53,80,110,135,139,143,443,993,995,1433,1521,3306,3389,5432,5900,8080)")
 	scanCmd.Flags().BoolVar(&scanUDP, "udp", false,
 		"Scan UDP ports instead of TCP")
+	scanCmd.Flags().BoolVarP(&scanJSON, "json", "j", false,
+		"Output the shown ports in JSON format")
+	scanCmd.Flags().BoolVar(&scanBrief, "service-only", false,
+		"Print only \"host port service\" lines for the shown ports")
+	scanCmd.Flags().StringVar(&scanShow, "show", "open",
+		"Ports to show by status: all, or a comma list of open, closed, filtered, error")
+	scanCmd.Flags().IntVar(&scanMaxResults, "max-results", 0,
+		"Keep at most this many shown ports, the first in scan order (0 = unlimited); the summary still counts every port")
+	scanCmd.Flags().BoolVar(&scanSummary, "summary", false,
+		"Print counts by status and open ports by service instead of the port table")
+	scanCmd.Flags().DurationVar(&scanCacheTTL, "cache-ttl", 0,
+		"Reuse results cached on disk for up to this long instead of redialling (0 = no cache)")
+	scanCmd.Flags().BoolVar(&scanNoCache, "no-cache", false,
+		"Neither read nor update the scan cache")
+	scanCmd.Flags().BoolVar(&scanRefresh, "refresh", false,
+		"Probe every port again, ignoring cached results, and update the cache")
+	scanCmd.Flags().BoolVar(&tableNoHeader, "no-header", false,
+		"Omit the table header and the result count, for scripts")
+	scanCmd.Flags().BoolVar(&tableMarkdown, "markdown", false,
+		"Print the port table as Markdown, without colors")
+	scanCmd.MarkFlagsMutuallyExclusive("markdown", "no-header")
 }
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanCollectorKeepsShownStatuses(t *testing.T) {
+	c := newScanCollector("localhost", []string{"open"}, 0)
+	c.add(2, ScanResult{Port: 3, Status: "open", Service: "HTTP"})
+	c.add(0, ScanResult{Port: 1, Status: "closed"})
+	c.add(1, ScanResult{Port: 2, Status: "open"})
+
+	got := c.results()
+	if len(got) != 2 || got[0].Port != 2 || got[1].Port != 3 {
+		t.Fatalf("results = %+v, want open ports 2 and 3 in scan order", got)
+	}
+	if c.summary.Scanned != 3 || c.summary.StatusCounts["closed"] != 1 || c.summary.StatusCounts["open"] != 2 {
+		t.Errorf("summary = %+v, want every result counted", c.summary)
+	}
+	if c.summary.OpenByService["HTTP"] != 1 || c.summary.OpenByService["Unknown"] != 1 {
+		t.Errorf("OpenByService = %v", c.summary.OpenByService)
+	}
+	if c.truncated() != 0 {
+		t.Errorf("truncated() = %d, want 0", c.truncated())
+	}
+}
+
+func TestScanCollectorLimitKeepsFirstInScanOrder(t *testing.T) {
+	c := newScanCollector("localhost", scanStatuses, 3)
+	// Workers finish out of order
+	for _, i := range []int{5, 1, 4, 0, 3, 2} {
+		c.add(i, ScanResult{Port: i, Status: "closed"})
+	}
+
+	got := c.results()
+	if len(got) != 3 {
+		t.Fatalf("kept %d results, want 3", len(got))
+	}
+	for i, r := range got {
+		if r.Port != i {
+			t.Errorf("results[%d].Port = %d, want %d", i, r.Port, i)
+		}
+	}
+	if c.truncated() != 3 {
+		t.Errorf("truncated() = %d, want 3", c.truncated())
+	}
+	if c.summary.Scanned != 6 {
+		t.Errorf("summary.Scanned = %d, want 6", c.summary.Scanned)
+	}
+}
+
+func TestScanCollectorSummaryOnly(t *testing.T) {
+	c := newScanCollector("localhost", nil, 0)
+	c.add(0, ScanResult{Port: 1, Status: "open"})
+	if got := c.results(); len(got) != 0 {
+		t.Errorf("results = %+v, want none retained", got)
+	}
+	if c.summary.StatusCounts["open"] != 1 {
+		t.Errorf("summary = %+v, want the open port counted", c.summary)
+	}
+}
+
+// BenchmarkScanCollectorOpenOnly models scanning a /24 for open ports, where
+// nearly every result is closed and should not be retained
+func BenchmarkScanCollectorOpenOnly(b *testing.B) {
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		c := newScanCollector("10.0.0.0/24", []string{"open"}, 0)
+		for i := 0; i < 256*1024; i++ {
+			status := "closed"
+			if i%1000 == 0 {
+				status = "open"
+			}
+			c.add(i, ScanResult{Port: i % 1024, Status: status})
+		}
+		_ = c.results()
+	}
+}
+
+func TestScanCacheTTL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), scanCacheFile)
+	cache, err := loadScanCache(path, time.Minute, "tcp", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache.store(ScanResult{Host: "db", Port: 5432, Status: "open", Service: "PostgreSQL"})
+	cache.store(ScanResult{Host: "db", Port: 22, Status: "closed"})
+	cache.store(ScanResult{Host: "db", Port: 23, Status: "error"})
+	// Probed two minutes ago, so already stale
+	cache.entries[cache.key("db", 22)] = scanCacheEntry{
+		Result:  ScanResult{Host: "db", Port: 22, Status: "closed"},
+		Scanned: time.Now().Add(-2 * time.Minute),
+	}
+
+	if got, ok := cache.lookup("db", 5432); !ok || got.Status != "open" || got.Service != "PostgreSQL" {
+		t.Errorf("lookup(db, 5432) = %+v, %v; want the cached open result", got, ok)
+	}
+	if _, ok := cache.lookup("db", 22); ok {
+		t.Error("lookup(db, 22) hit an expired entry")
+	}
+	if _, ok := cache.lookup("db", 23); ok {
+		t.Error("lookup(db, 23) hit an error result, which should not be cached")
+	}
+	if cache.hits != 1 {
+		t.Errorf("hits = %d, want 1", cache.hits)
+	}
+
+	if err := cache.save(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := loadScanCache(path, time.Minute, "tcp", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.entries) != 1 {
+		t.Errorf("reloaded %d entries, want only the fresh one", len(reloaded.entries))
+	}
+	if _, ok := reloaded.lookup("db", 5432); !ok {
+		t.Error("fresh entry lost across save and load")
+	}
+
+	// Results for another address family are kept apart
+	ipv6, err := loadScanCache(path, time.Minute, "tcp6", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := ipv6.lookup("db", 5432); ok {
+		t.Error("tcp6 scan reused a tcp result")
+	}
+}
+
+func TestScanCacheRefresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), scanCacheFile)
+	cache, _ := loadScanCache(path, time.Hour, "tcp", false)
+	cache.store(ScanResult{Host: "web", Port: 80, Status: "closed"})
+	if err := cache.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	refresh, err := loadScanCache(path, time.Hour, "tcp", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := refresh.lookup("web", 80); ok {
+		t.Error("--refresh reused a cached result")
+	}
+	refresh.store(ScanResult{Host: "web", Port: 80, Status: "open"})
+	if err := refresh.save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, _ := loadScanCache(path, time.Hour, "tcp", false)
+	if got, ok := reloaded.lookup("web", 80); !ok || got.Status != "open" {
+		t.Errorf("after --refresh, lookup(web, 80) = %+v, %v; want the new open result", got, ok)
+	}
+}
+
+func TestScanCacheDamagedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), scanCacheFile)
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := loadScanCache(path, time.Hour, "tcp", false)
+	if err == nil {
+		t.Error("expected an error for a damaged cache file")
+	}
+	if cache == nil || len(cache.entries) != 0 {
+		t.Fatalf("cache = %+v, want a usable empty cache", cache)
+	}
+	if err := cache.save(); err != nil {
+		t.Fatalf("saving over a damaged cache: %v", err)
+	}
+	if _, err := loadScanCache(path, time.Hour, "tcp", false); err != nil {
+		t.Errorf("cache still damaged after save: %v", err)
+	}
+}
+
+func TestScanHostsUsesCache(t *testing.T) {
+	cache, _ := loadScanCache(filepath.Join(t.TempDir(), scanCacheFile), time.Hour, scanNetwork(), false)
+	// .invalid never resolves, and a failed lookup is reported as closed, so an
+	// open result can only have come from the cache
+	cache.store(ScanResult{Host: "cached.invalid", Port: 80, Protocol: "tcp", Status: "open"})
+
+	collector := newScanCollector("cached.invalid", scanStatuses, 0)
+	scanHosts(context.Background(), []string{"cached.invalid"}, []int{80}, nil, collector, cache)
+
+	got := collector.results()
+	if len(got) != 1 || got[0].Status != "open" {
+		t.Errorf("results = %+v, want the cached open result", got)
+	}
+}
+
+func TestExpandScanHost(t *testing.T) {
+	tests := []struct {
+		spec      string
+		wantCount int
+		wantFirst string
+		wantLast  string
+		wantErr   bool
+	}{
+		{spec: "example.com", wantCount: 1, wantFirst: "example.com", wantLast: "example.com"},
+		{spec: "10.0.0.7", wantCount: 1, wantFirst: "10.0.0.7", wantLast: "10.0.0.7"},
+		// A /32 is the address itself
+		{spec: "10.0.0.7/32", wantCount: 1, wantFirst: "10.0.0.7", wantLast: "10.0.0.7"},
+		// A /31 is a point-to-point link with no network or broadcast address
+		{spec: "10.0.0.6/31", wantCount: 2, wantFirst: "10.0.0.6", wantLast: "10.0.0.7"},
+		// Larger IPv4 blocks drop the network and broadcast addresses
+		{spec: "192.168.1.0/30", wantCount: 2, wantFirst: "192.168.1.1", wantLast: "192.168.1.2"},
+		{spec: "192.168.1.77/24", wantCount: 254, wantFirst: "192.168.1.1", wantLast: "192.168.1.254"},
+		// The cap is 65536 addresses: a /16 is allowed, a /15 is not
+		{spec: "10.1.0.0/16", wantCount: 65534, wantFirst: "10.1.0.1", wantLast: "10.1.255.254"},
+		{spec: "10.0.0.0/15", wantErr: true},
+		// IPv6 has no broadcast address, so every address is kept
+		{spec: "2001:db8::/126", wantCount: 4, wantFirst: "2001:db8::", wantLast: "2001:db8::3"},
+		{spec: "2001:db8::1/128", wantCount: 1, wantFirst: "2001:db8::1", wantLast: "2001:db8::1"},
+		{spec: "2001:db8::/112", wantCount: 65536, wantFirst: "2001:db8::", wantLast: "2001:db8::ffff"},
+		{spec: "2001:db8::/111", wantErr: true},
+		{spec: "10.0.0.0/33", wantErr: true},
+		{spec: "not-a-cidr/8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			hosts, err := expandScanHost(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expandScanHost(%q) = %d host(s), want an error", tt.spec, len(hosts))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expandScanHost(%q) error = %v", tt.spec, err)
+			}
+			if len(hosts) != tt.wantCount {
+				t.Fatalf("expandScanHost(%q) = %d host(s), want %d", tt.spec, len(hosts), tt.wantCount)
+			}
+			if hosts[0] != tt.wantFirst || hosts[len(hosts)-1] != tt.wantLast {
+				t.Errorf("expandScanHost(%q) spans %s..%s, want %s..%s",
+					tt.spec, hosts[0], hosts[len(hosts)-1], tt.wantFirst, tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestReadScanHosts(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:    "hosts and comments",
+			content: "# staging hosts\nweb1.example.com\n\n  db.example.com  # primary\n#10.0.0.1\n",
+			want:    []string{"web1.example.com", "db.example.com"},
+		},
+		{
+			name:    "CIDR blocks and repeats",
+			content: "10.0.0.2\n10.0.0.0/30\n10.0.0.1 # again\n",
+			want:    []string{"10.0.0.2", "10.0.0.1"},
+		},
+		{
+			name:    "IPv6",
+			content: "2001:db8::/127\n::1\n",
+			want:    []string{"2001:db8::", "2001:db8::1", "::1"},
+		},
+		{name: "only comments", content: "# nothing\n\n", want: nil},
+		{name: "block over the cap", content: "web1\n10.0.0.0/8\n", wantErr: "line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hosts.txt")
+			if err := os.WriteFile(path, []byte(tt.content), 0600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readScanHosts(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readScanHosts() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readScanHosts() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("readScanHosts() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"os"
+
+	tablepretty "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var signalsJSON bool
+
+var signalsCmd = &cobra.Command{
+	Use:   "signals",
+	Short: "List the signals kill --signal accepts on this platform",
+	Long: `List the signal names and numbers accepted by "portctl kill --signal",
+with what each one does. Numbers differ between Linux and macOS, so prefer names
+in scripts.
+
+Windows has no signals: SIGTERM and SIGINT are emulated with taskkill, SIGKILL
+with taskkill /F, and the rest are marked unsupported.
+
+Examples:
+  portctl signals
+  portctl signals --json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		signals := process.Signals()
+		if signalsJSON {
+			writeJSON(signals)
+			return
+		}
+
+		t := tablepretty.NewWriter()
+		t.SetOutputMirror(os.Stdout)
+		t.SetStyle(tablepretty.StyleColoredBright)
+		t.AppendHeader(tablepretty.Row{"Name", "Number", "Support", "Description"})
+		t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
+		t.SetColumnConfigs([]tablepretty.ColumnConfig{
+			{Number: 1, Colors: text.Colors{text.FgCyan, text.Bold}},
+			{Number: 2, Align: text.AlignRight},
+		})
+		for _, info := range signals {
+			support := info.Support
+			switch support {
+			case process.SignalEmulated:
+				support = text.FgYellow.Sprint(support)
+			case process.SignalUnsupported:
+				support = text.FgRed.Sprint(support)
+			}
+			t.AppendRow(tablepretty.Row{info.Name, info.Number, support, info.Description})
+		}
+		t.Render()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(signalsCmd)
+
+	signalsCmd.Flags().BoolVarP(&signalsJSON, "json", "j", false,
+		"Output the signals in JSON format")
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var (
+	suspendPIDs []int
+	resumePIDs  []int
+)
+
+var suspendCmd = &cobra.Command{
+	Use:   "suspend [port...]",
+	Short: "Pause the processes on a port without killing them",
+	Long: `Pause processes (SIGSTOP on macOS/Linux) so they stop using CPU, then
+continue them later with "portctl resume".
+
+A suspended process keeps its ports open, so clients connecting to them hang
+instead of being refused. portctl never suspends itself or its parent shell.
+
+Examples:
+  portctl suspend 8080              # Pause whatever listens on port 8080
+  portctl suspend --pid 12345       # Pause a specific process
+  portctl resume 8080               # Continue it again`,
+	Args: signalCommandArgs(&suspendPIDs),
+	Run: func(cmd *cobra.Command, args []string) {
+		runSignalCommand(cmd.Context(), args, suspendPIDs, "suspend", "Suspended",
+			func(ctx context.Context, pm *process.ProcessManager, pid int) error { return pm.Suspend(ctx, pid) })
+	},
+}
+
+var resumeCmd = &cobra.Command{
+	Use:   "resume [port...]",
+	Short: "Continue processes paused with suspend",
+	Long: `Continue processes paused by "portctl suspend" (SIGCONT on macOS/Linux).
+
+Examples:
+  portctl resume 8080               # Continue whatever listens on port 8080
+  portctl resume --pid 12345        # Continue a specific process`,
+	Args: signalCommandArgs(&resumePIDs),
+	Run: func(cmd *cobra.Command, args []string) {
+		runSignalCommand(cmd.Context(), args, resumePIDs, "resume", "Resumed",
+			func(ctx context.Context, pm *process.ProcessManager, pid int) error { return pm.Resume(ctx, pid) })
+	},
+}
+
+// signalCommandArgs requires at least one port unless --pid was given
+func signalCommandArgs(pids *[]int) cobra.PositionalArgs {
+	return func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 && len(*pids) == 0 {
+			return fmt.Errorf("specify at least one port or use --pid")
+		}
+		return nil
+	}
+}
+
+// runSignalCommand resolves ports to PIDs the same way kill does, then applies
+// action to each distinct process, exiting non-zero if any of them failed
+func runSignalCommand(ctx context.Context, args []string, pids []int, verb, past string,
+	action func(context.Context, *process.ProcessManager, int) error) {
+	pm := newProcessManager()
+
+	var targets []process.Process
+	for _, pid := range pids {
+		targets = append(targets, process.Process{PID: pid})
+	}
+	for _, portStr := range args {
+		port, err := strconv.Atoi(portStr)
+		if err != nil {
+			exitWithError(false, exitCodeUsage, "Invalid port number: %s", portStr)
+		}
+
+		processes, err := pm.GetProcessesOnPort(ctx, port)
+		if err != nil {
+			color.Red("Error getting processes on port %d: %v", port, err)
+			continue
+		}
+		if len(processes) == 0 {
+			color.Yellow("No processes found on port %d", port)
+			printPrivilegeHint(pm.PrivilegeHint(ctx, nil, port))
+		}
+		targets = append(targets, processes...)
+	}
+
+	failed := 0
+	for _, proc := range removeDuplicateProcesses(targets) {
+		if isSelfPID(proc.PID) {
+			color.Yellow("Note: skipping PID %d, which is portctl or its parent shell", proc.PID)
+			continue
+		}
+
+		if err := action(ctx, pm, proc.PID); err != nil {
+			color.Red("Failed to %s process %d: %v", verb, proc.PID, err)
+			failed++
+			continue
+		}
+
+		if proc.Port > 0 {
+			color.Green("%s process %d (%s on port %d)", past, proc.PID, proc.Command, proc.Port)
+		} else {
+			color.Green("%s process %d", past, proc.PID)
+		}
+	}
+
+	if failed > 0 {
+		os.Exit(exitCodeError)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(suspendCmd)
+	rootCmd.AddCommand(resumeCmd)
+
+	suspendCmd.Flags().IntSliceVar(&suspendPIDs, "pid", nil,
+		"PID to suspend (repeatable)")
+	resumeCmd.Flags().IntSliceVar(&resumePIDs, "pid", nil,
+		"PID to resume (repeatable)")
+}
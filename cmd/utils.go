@@ -14,11 +14,34 @@ import (
 )
 
 var (
-	availableStart int
-	availableEnd   int
-	availableCount int
+	availableStart     int
+	availableEnd       int
+	availableCount     int
+	availableJSON      bool
+	availableStrict    bool
+	availableBlock     int
+	availableEphemeral bool
 )
 
+// AvailablePort is a free port with hints about what it is typically used for
+type AvailablePort struct {
+	Port          int    `json:"port"`
+	SuggestedUse  string `json:"suggested_use"`
+	CommonService string `json:"common_service"`
+}
+
+// AvailableResult is the --json payload of the available command
+type AvailableResult struct {
+	Start     int             `json:"start"`
+	End       int             `json:"end"`
+	Requested int             `json:"requested"`
+	Block     bool            `json:"block,omitempty"` // Ports are one consecutive run
+	Ports     []AvailablePort `json:"ports"`
+
+	// EphemeralExcluded is the OS ephemeral range, when it overlapped the search and was skipped
+	EphemeralExcluded *process.PortRange `json:"ephemeral_excluded,omitempty"`
+}
+
 var availableCmd = &cobra.Command{
 	Use:   "available",
 	Short: "Find available ports in specified ranges",
@@ -27,26 +50,36 @@ var availableCmd = &cobra.Command{
 This command helps you quickly find free ports for development or testing.
 You can specify custom port ranges or use common development port ranges.
 
+Ports in the operating system's ephemeral range (32768-60999 on most Linux
+systems) are skipped unless --include-ephemeral is given: outbound connections
+are assigned local ports from that range, so a server configured to use one
+can randomly find it taken.
+
 Examples:
-  portctl available                    # Find 10 ports in development range (3000-9999)
+  portctl available                    # Find 10 ports in the dev.ports range (default 3000-9999)
   portctl available --start 8000      # Find ports starting from 8000
   portctl available --end 8100        # Find ports up to 8100
   portctl available --count 5         # Find only 5 available ports
-  portctl available --start 3000 --end 4000 --count 20  # Custom range`,
+  portctl available --start 3000 --end 4000 --count 20  # Custom range
+  portctl available --count 1 --json | jq .data.ports[0].port  # Grab a free port in a script
+  portctl available --count 3 --strict  # Fail unless all 3 ports are free
+  portctl available --block 3          # Find 3 consecutive free ports
+  portctl available -s 40000 -e 50000 --include-ephemeral  # Allow ephemeral ports`,
 	Aliases: []string{"free", "open"},
 	Run:     runAvailable,
 }
 
 func runAvailable(cmd *cobra.Command, args []string) {
-	pm := process.NewProcessManager()
+	pm := newProcessManager().WithEphemeral(availableEphemeral)
 	ctx := cmd.Context()
 
-	// Set defaults if not specified
+	// Default to the configured development range
+	devPorts := devPortRange()
 	if availableStart == 0 {
-		availableStart = 3000
+		availableStart = devPorts.Start
 	}
 	if availableEnd == 0 {
-		availableEnd = 9999
+		availableEnd = devPorts.End
 	}
 	if availableCount == 0 {
 		availableCount = 10
@@ -54,24 +87,88 @@ func runAvailable(cmd *cobra.Command, args []string) {
 
 	// Validate range
 	if availableStart >= availableEnd {
-		fmt.Println("\033[91mStart port must be less than end port\033[0m")
-		os.Exit(1)
+		exitWithError(availableJSON, exitCodeUsage, "Start port must be less than end port")
+	}
+	if cmd.Flags().Changed("block") {
+		if cmd.Flags().Changed("count") {
+			exitWithError(availableJSON, exitCodeUsage, "--block cannot be combined with --count")
+		}
+		if availableBlock < 1 {
+			exitWithError(availableJSON, exitCodeUsage, "--block must be at least 1")
+		}
 	}
 
-	fmt.Printf("\033[96m🔍 Searching for available ports in range %d-%d...\033[0m\n", availableStart, availableEnd)
+	// Report the skipped ephemeral range when it overlaps the search
+	var skipped *process.PortRange
+	if !availableEphemeral {
+		if ephemeral, err := process.EphemeralPortRange(ctx); err == nil &&
+			ephemeral.Start <= availableEnd && ephemeral.End >= availableStart {
+			skipped = &ephemeral
+		}
+	}
 
-	available, err := pm.FindAvailablePorts(ctx, availableStart, availableEnd, availableCount)
-	if err != nil {
-		fmt.Printf("\033[91mError finding available ports: %v\033[0m\n", err)
-		os.Exit(1)
+	if !availableJSON {
+		statusf(printfln, "\033[96m🔍 Searching for available ports in range %d-%d...\033[0m", availableStart, availableEnd)
+		if skipped != nil {
+			statusf(printfln, "\033[93mSkipping ephemeral ports %s (use --include-ephemeral to allow them)\033[0m", skipped)
+		}
+	}
+
+	var available []int
+	if availableBlock > 0 {
+		// A block is all or nothing, so a missing one is always an error
+		start, err := pm.FindAvailableBlock(ctx, availableStart, availableEnd, availableBlock)
+		if err != nil {
+			exitWithError(availableJSON, exitCodeError, "Error finding a port block: %v", err)
+		}
+		for port := start; port < start+availableBlock; port++ {
+			available = append(available, port)
+		}
+		availableCount = availableBlock
+	} else {
+		var err error
+		available, err = pm.FindAvailablePorts(ctx, availableStart, availableEnd, availableCount)
+		if err != nil {
+			exitWithError(availableJSON, exitCodeError, "Error finding available ports: %v", err)
+		}
+	}
+
+	if availableStrict && len(available) < availableCount {
+		exitWithError(availableJSON, exitCodeError, "Only %d of %d requested port(s) are available in range %d-%d",
+			len(available), availableCount, availableStart, availableEnd)
+	}
+
+	if availableJSON {
+		result := AvailableResult{
+			Start:     availableStart,
+			End:       availableEnd,
+			Requested: availableCount,
+			Block:     availableBlock > 0,
+			Ports:     make([]AvailablePort, 0, len(available)),
+
+			EphemeralExcluded: skipped,
+		}
+		for _, port := range available {
+			result.Ports = append(result.Ports, AvailablePort{
+				Port:          port,
+				SuggestedUse:  getSuggestedUse(port),
+				CommonService: getCommonService(port),
+			})
+		}
+		writeJSON(result)
+		return
 	}
 
 	if len(available) == 0 {
-		fmt.Printf("\033[93mNo available ports found in range %d-%d\033[0m\n", availableStart, availableEnd)
+		statusf(printfln, "\033[93mNo available ports found in range %d-%d\033[0m", availableStart, availableEnd)
 		return
 	}
 
-	fmt.Printf("\033[92m✅ Found %d available port(s):\033[0m\n\n", len(available))
+	if availableBlock > 0 {
+		statusf(printfln, "\033[92m✅ Found %d consecutive available port(s) starting at %d:\033[0m\n", len(available), available[0])
+	} else {
+		statusf(printfln, "\033[92m✅ Found %d available port(s):\033[0m\n", len(available))
+	}
 
 	// Create table
 	t := tablepretty.NewWriter()
@@ -98,9 +195,9 @@ func runAvailable(cmd *cobra.Command, args []string) {
 	t.Render()
 
 	// Show quick copy commands
-	fmt.Println()
-	fmt.Printf("\033[96m💡 Quick commands:\033[0m\n")
-	if len(available) > 0 {
+	if len(available) > 0 && !quietOutput {
+		fmt.Println()
+		fmt.Printf("\033[96m💡 Quick commands:\033[0m\n")
 		fmt.Printf("  export PORT=%d\n", available[0])
 		fmt.Printf("  npm start -- --port %d\n", available[0])
 		fmt.Printf("  python -m http.server %d\n", available[0])
@@ -141,120 +238,244 @@ This command provides insights into:
   • Common development ports status
 
 Examples:
-  portctl stats           # Show all statistics
-  portctl stats --json   # Output in JSON format`,
+  portctl stats                  # Show all statistics
+  portctl stats --json           # Output in JSON format
+  portctl stats --top 20         # Show the top 20 processes
+  portctl stats --top-by cpu     # Rank top processes by CPU instead of memory
+  portctl stats --oneline        # procs=142 ports=37 cpu=12.3% mem=8.1/16.0GB
+  portctl stats --markdown       # Markdown report for GitHub issues and docs
+
+--oneline prints a single plain line for shell prompts and status bars, e.g.
+PS1='$(portctl stats --oneline) \$ '. Its fields and format are stable: memory
+is used over total installed, always in GB with one decimal, and there is
+never any color.`,
 	Aliases: []string{"statistics", "info", "system"},
 	Run:     runStats,
 }
 
-var statsJSON bool
+// memoryUsagePercent returns used memory as a percentage of installed memory.
+// Every stats view, including the gRPC API, reports this same figure.
+func memoryUsagePercent(stats *process.SystemStats) float64 {
+	return stats.MemoryUsageGB / stats.TotalMemoryGB * 100
+}
+
+// formatStatsLine renders stats as a single colorless line for embedding in
+// prompts. The format is relied on by scripts, so fields must not change.
+func formatStatsLine(stats *process.SystemStats) string {
+	return fmt.Sprintf("procs=%d ports=%d cpu=%.1f%% mem=%.1f/%.1fGB",
+		stats.TotalProcesses, stats.ListeningPorts, stats.CPUUsagePercent,
+		stats.MemoryUsageGB, stats.TotalMemoryGB)
+}
+
+var (
+	statsJSON  bool
+	statsTop   int
+	statsTopBy string
+	statsLine  bool
+)
+
+// statsHeading prints a section heading of the stats report, in color or as
+// a Markdown heading with --markdown
+func statsHeading(title string) {
+	if tableMarkdown {
+		fmt.Printf("\n### %s\n\n", title)
+		return
+	}
+	fmt.Printf("\033[96m%s:\033[0m\n", title)
+}
 
 func runStats(cmd *cobra.Command, args []string) {
-	pm := process.NewProcessManager()
+	pm := newProcessManager()
 	ctx := cmd.Context()
 
-	fmt.Printf("\033[96m📊 Gathering system statistics...\033[0m\n")
+	if statsTopBy != "cpu" && statsTopBy != "memory" {
+		exitWithError(statsJSON, exitCodeUsage, "Invalid --top-by value: %s (must be 'cpu' or 'memory')", statsTopBy)
+	}
 
-	stats, err := pm.GetSystemStats(ctx)
+	if statsJSON && statsLine {
+		exitWithError(statsJSON, exitCodeUsage, "--json and --oneline cannot be combined")
+	}
+	if tableMarkdown {
+		if statsJSON || statsLine {
+			exitWithError(statsJSON, exitCodeUsage, "--markdown cannot be combined with --json or --oneline")
+		}
+		disableColors()
+	}
+
+	if !statsJSON && !statsLine {
+		statusf(printfln, "\033[96m📊 Gathering system statistics...\033[0m")
+	}
+
+	stats, err := pm.GetSystemStats(ctx, process.StatsOptions{TopN: statsTop, TopBy: statsTopBy})
 	if err != nil {
-		fmt.Printf("\033[91mError getting system statistics: %v\033[0m\n", err)
-		os.Exit(1)
+		exitWithError(statsJSON, exitCodeError, "Error getting system statistics: %v", err)
 	}
 
 	if statsJSON {
-		// Output JSON
-		fmt.Printf(`{
-  "total_processes": %d,
-  "listening_ports": %d,
-  "cpu_usage_percent": %.1f,
-  "memory_usage_gb": %.1f,
-  "available_memory_gb": %.1f,
-  "top_port_users": [`,
-			stats.TotalProcesses,
-			stats.ListeningPorts,
-			stats.CPUUsagePercent,
-			stats.MemoryUsageGB,
-			stats.AvailableMemoryGB)
-
-		for i, proc := range stats.TopPortUsers {
-			if i > 0 {
-				fmt.Print(",")
-			}
-			fmt.Printf(`
-    {
-      "pid": %d,
-      "port": %d,
-      "command": "%s",
-      "service_type": "%s",
-      "memory_mb": %.1f,
-      "cpu_percent": %.1f
-    }`, proc.PID, proc.Port, proc.Command, proc.ServiceType, proc.MemoryMB, proc.CPUPercent)
-		}
-		fmt.Println(`
-  ]
-}`)
+		writeJSON(stats)
 		return
 	}
 
-	// Pretty output
-	fmt.Print("\033[2J\033[H") // Clear screen
+	if statsLine {
+		fmt.Println(formatStatsLine(stats))
+		return
+	}
 
-	fmt.Printf("\033[92m🚀 portctl System Statistics\033[0m\n")
-	fmt.Println(strings.Repeat("═", 50))
+	if tableMarkdown {
+		printStatsOverviewMarkdown(stats)
+	} else {
+		printStatsOverview(stats)
+	}
+
+	// Top processes
+	if len(stats.TopPortUsers) > 0 {
+		if statsTopBy == "cpu" {
+			statsHeading("🔥 Top CPU Users")
+		} else {
+			statsHeading("🔥 Top Memory Users")
+		}
+		printTopUsers(stats.TopPortUsers)
+	}
+
+	// Port distribution by service type
+	if len(stats.Services) > 0 {
+		statsHeading("📦 Ports by Service")
+		printServiceCounts(stats.Services)
+	}
+
+	// Development ports status
+	statsHeading("🛠️  Common Development Ports")
+	checkCommonPorts(ctx, pm)
+}
+
+// printStatsOverview prints the system, network and per-core figures of the
+// terminal report
+func printStatsOverview(stats *process.SystemStats) {
+	if !quietOutput {
+		fmt.Print("\033[2J\033[H") // Clear screen
+
+		fmt.Printf("\033[92m🚀 portctl System Statistics\033[0m\n")
+		fmt.Println(strings.Repeat("═", 50))
+	}
 
 	// System overview
 	fmt.Printf("\033[96m📈 System Overview:\033[0m\n")
 	fmt.Printf("  Total Processes:    %d\n", stats.TotalProcesses)
 	fmt.Printf("  Listening Ports:    %d\n", stats.ListeningPorts)
 	fmt.Printf("  CPU Usage:          %.1f%%\n", stats.CPUUsagePercent)
-	fmt.Printf("  Memory Used:        %.1f GB\n", stats.MemoryUsageGB)
-	fmt.Printf("  Memory Available:   %.1f GB\n", stats.AvailableMemoryGB)
+	fmt.Printf("  Memory Used:        %s\n", process.FormatMemory(stats.MemoryUsageGB*1024))
+	fmt.Printf("  Memory Available:   %s\n", process.FormatMemory(stats.AvailableMemoryGB*1024))
+	fmt.Printf("  Memory Total:       %s\n", process.FormatMemory(stats.TotalMemoryGB*1024))
 
 	// Memory usage bar
-	totalMemory := stats.MemoryUsageGB + stats.AvailableMemoryGB
-	memoryPercent := (stats.MemoryUsageGB / totalMemory) * 100
+	memoryPercent := memoryUsagePercent(stats)
 	fmt.Printf("  Memory Usage:       %s (%.1f%%)\n",
 		getProgressBar(memoryPercent), memoryPercent)
 
-	// Top processes
-	if len(stats.TopPortUsers) > 0 {
-		fmt.Printf("\033[96m🔥 Top Memory Users:\033[0m\n")
-		t := tablepretty.NewWriter()
-		t.SetOutputMirror(os.Stdout)
-		t.SetStyle(tablepretty.StyleColoredBright)
-		t.AppendHeader(tablepretty.Row{"Rank", "PID", "Port", "Command", "Service", "Memory", "CPU%"})
-		t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
-		t.SetColumnConfigs([]tablepretty.ColumnConfig{
-			{Number: 1, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}}, // Rank
-			{Number: 2, Align: text.AlignRight},                                              // PID
-			{Number: 3, Align: text.AlignRight},                                              // Port
-			{Number: 4, Align: text.AlignLeft},                                               // Command
-			{Number: 5, Align: text.AlignLeft},                                               // Service
-			{Number: 6, Align: text.AlignRight, Colors: text.Colors{text.FgYellow}},          // Memory
-			{Number: 7, Align: text.AlignRight},                                              // CPU%
-		})
+	// Network activity
+	fmt.Printf("\033[96m🌐 Network:\033[0m\n")
+	fmt.Printf("  Established Conns:  %d\n", stats.Established)
+	fmt.Printf("  Sent:               %s (%s/s)\n",
+		process.FormatBytes(stats.BytesSent), process.FormatBytes(uint64(stats.SendRate)))
+	fmt.Printf("  Received:           %s (%s/s)\n",
+		process.FormatBytes(stats.BytesRecv), process.FormatBytes(uint64(stats.RecvRate)))
+
+	// Per-core CPU usage
+	if len(stats.PerCorePercent) > 0 {
+		fmt.Printf("\033[96m🧮 CPU Cores:\033[0m\n")
+		for i, percent := range stats.PerCorePercent {
+			fmt.Printf("  Core %-3d %s %5.1f%%\n", i, getProgressBar(percent), percent)
+		}
+	}
+}
 
-		for i, proc := range stats.TopPortUsers {
-			if i >= 5 {
-				break
-			}
-			row := tablepretty.Row{
-				fmt.Sprintf("#%d", i+1),
-				proc.PID,
-				proc.Port,
-				proc.Command,
-				proc.ServiceType,
-				fmt.Sprintf("%.1f MB", proc.MemoryMB),
-				fmt.Sprintf("%.1f", proc.CPUPercent),
-			}
-			t.AppendRow(row)
+// printStatsOverviewMarkdown prints the figures of printStatsOverview as a
+// Markdown metric table, leaving out the bars
+func printStatsOverviewMarkdown(stats *process.SystemStats) {
+	fmt.Println("## portctl System Statistics")
+	statsHeading("📈 System Overview")
+
+	t := tablepretty.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.AppendHeader(tablepretty.Row{"Metric", "Value"})
+	t.SetColumnConfigs([]tablepretty.ColumnConfig{
+		{Number: 2, Align: text.AlignRight}, // Value
+	})
+	t.AppendRows([]tablepretty.Row{
+		{"Total Processes", stats.TotalProcesses},
+		{"Listening Ports", stats.ListeningPorts},
+		{"CPU Usage", fmt.Sprintf("%.1f%%", stats.CPUUsagePercent)},
+		{"Memory Used", process.FormatMemory(stats.MemoryUsageGB * 1024)},
+		{"Memory Available", process.FormatMemory(stats.AvailableMemoryGB * 1024)},
+		{"Memory Total", process.FormatMemory(stats.TotalMemoryGB * 1024)},
+		{"Memory Usage", fmt.Sprintf("%.1f%%", memoryUsagePercent(stats))},
+		{"Established Conns", stats.Established},
+		{"Sent", fmt.Sprintf("%s (%s/s)", process.FormatBytes(stats.BytesSent), process.FormatBytes(uint64(stats.SendRate)))},
+		{"Received", fmt.Sprintf("%s (%s/s)", process.FormatBytes(stats.BytesRecv), process.FormatBytes(uint64(stats.RecvRate)))},
+	})
+	renderTable(t)
+}
+
+// printTopUsers renders the processes using the most CPU or memory, ranked
+func printTopUsers(top []process.Process) {
+	t := tablepretty.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tablepretty.StyleColoredBright)
+	t.AppendHeader(tablepretty.Row{"Rank", "PID", "Port", "Command", "Service", "Memory", "CPU%"})
+	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
+	t.SetColumnConfigs([]tablepretty.ColumnConfig{
+		{Number: 1, Align: text.AlignRight, Colors: text.Colors{text.FgCyan, text.Bold}}, // Rank
+		{Number: 2, Align: text.AlignRight},                                              // PID
+		{Number: 3, Align: text.AlignRight},                                              // Port
+		{Number: 4, Align: text.AlignLeft},                                               // Command
+		{Number: 5, Align: text.AlignLeft},                                               // Service
+		{Number: 6, Align: text.AlignRight, Colors: text.Colors{text.FgYellow}},          // Memory
+		{Number: 7, Align: text.AlignRight},                                              // CPU%
+	})
+
+	for i, proc := range top {
+		row := tablepretty.Row{
+			fmt.Sprintf("#%d", i+1),
+			proc.PID,
+			proc.Port,
+			proc.Command,
+			proc.ServiceType,
+			process.FormatMemory(float64(proc.MemoryMB)),
+			fmt.Sprintf("%.1f", proc.CPUPercent),
 		}
-		t.Render()
+		t.AppendRow(row)
 	}
+	renderTable(t)
+}
 
-	// Development ports status
-	fmt.Printf("\033[96m🛠️  Common Development Ports:\033[0m\n")
-	checkCommonPorts(ctx, pm)
+// printServiceCounts renders the service distribution as a table with a bar
+// scaled to the service with the most processes
+func printServiceCounts(counts []process.ServiceCount) {
+	const barWidth = 20
+	most := counts[0].Processes
+
+	t := tablepretty.NewWriter()
+	t.SetOutputMirror(os.Stdout)
+	t.SetStyle(tablepretty.StyleColoredBright)
+	t.AppendHeader(tablepretty.Row{"Service", "Processes", "Ports", ""})
+	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
+	t.SetColumnConfigs([]tablepretty.ColumnConfig{
+		{Number: 1, Align: text.AlignLeft, Colors: text.Colors{text.FgYellow}}, // Service
+		{Number: 2, Align: text.AlignRight},                                    // Processes
+		{Number: 3, Align: text.AlignRight},                                    // Ports
+		{Number: 4, Align: text.AlignLeft, Colors: text.Colors{text.FgCyan}},   // Bar
+	})
+
+	for _, count := range counts {
+		filled := max(1, count.Processes*barWidth/max(1, most))
+		t.AppendRow(tablepretty.Row{
+			count.Service,
+			count.Processes,
+			count.Ports,
+			strings.Repeat("█", filled),
+		})
+	}
+	renderTable(t)
 }
 
 func getProgressBar(percent float64) string {
@@ -321,7 +542,7 @@ func checkCommonPorts(ctx context.Context, pm *process.ProcessManager) {
 			t.AppendRow(row)
 		}
 	}
-	t.Render()
+	renderTable(t)
 }
 
 func init() {
@@ -330,13 +551,29 @@ func init() {
 
 	// Available command flags
 	availableCmd.Flags().IntVarP(&availableStart, "start", "s", 0,
-		"Start of port range (default: 3000)")
+		"Start of port range (default: start of dev.ports)")
 	availableCmd.Flags().IntVarP(&availableEnd, "end", "e", 0,
-		"End of port range (default: 9999)")
+		"End of port range (default: end of dev.ports)")
 	availableCmd.Flags().IntVarP(&availableCount, "count", "c", 0,
 		"Number of ports to find (default: 10)")
+	availableCmd.Flags().BoolVarP(&availableJSON, "json", "j", false,
+		"Output the available ports in JSON format")
+	availableCmd.Flags().IntVar(&availableBlock, "block", 0,
+		"Find this many consecutive free ports instead of the first --count free ones")
+	availableCmd.Flags().BoolVar(&availableEphemeral, "include-ephemeral", false,
+		"Also suggest ports in the OS ephemeral range")
+	availableCmd.Flags().BoolVar(&availableStrict, "strict", false,
+		"Exit non-zero if fewer than --count ports are available")
 
 	// Stats command flags
 	statsCmd.Flags().BoolVarP(&statsJSON, "json", "j", false,
 		"Output statistics in JSON format")
+	statsCmd.Flags().IntVar(&statsTop, "top", process.DefaultTopN,
+		"Number of top processes to show")
+	statsCmd.Flags().BoolVar(&statsLine, "oneline", false,
+		"Print a compact single-line summary without color, for shell prompts")
+	statsCmd.Flags().BoolVar(&tableMarkdown, "markdown", false,
+		"Print the report as Markdown headings and tables, without colors")
+	statsCmd.Flags().StringVar(&statsTopBy, "top-by", "memory",
+		"Rank top processes by resource (cpu, memory)")
 }
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"testing"
+
+	process "dagger/portctl/pkg"
+)
+
+func TestFormatStatsLine(t *testing.T) {
+	stats := &process.SystemStats{
+		TotalProcesses:    142,
+		ListeningPorts:    37,
+		CPUUsagePercent:   12.345,
+		MemoryUsageGB:     8.06,
+		AvailableMemoryGB: 6.5,
+		TotalMemoryGB:     16.0,
+	}
+	want := "procs=142 ports=37 cpu=12.3% mem=8.1/16.0GB"
+	if got := formatStatsLine(stats); got != want {
+		t.Errorf("formatStatsLine() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/gen2brain/beeep"
+	"github.com/spf13/cobra"
+
+	process "dagger/portctl/pkg"
+)
+
+var (
+	waitPID      int
+	waitTimeout  time.Duration
+	waitInterval time.Duration
+	waitNotify   bool
+)
+
+var waitCmd = &cobra.Command{
+	Use:   "wait",
+	Short: "Wait for a process to exit",
+	Long: `Block until a process exits, then return.
+
+Useful for waiting on long builds or servers shutting down. PID reuse is
+handled by matching the original start time of the process where available.
+
+Exit codes:
+  0  The process exited
+  1  The timeout elapsed or the wait was interrupted
+
+Examples:
+  portctl wait --pid 12345                 # Wait until PID 12345 exits
+  portctl wait --pid 12345 --timeout 10m   # Give up after 10 minutes
+  portctl wait --pid 12345 --notify        # Desktop notification on exit`,
+	Args: cobra.NoArgs,
+	Run:  runWait,
+}
+
+func runWait(cmd *cobra.Command, args []string) {
+	if waitPID <= 0 {
+		exitWithError(false, exitCodeUsage, "Specify the process to wait for with --pid")
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if waitTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, waitTimeout)
+		defer cancel()
+	}
+
+	proc, err := newProcessManager().GetProcessByPID(ctx, waitPID)
+	if errors.Is(err, process.ErrProcessNotFound) {
+		// Already gone
+		statusf(color.Green, "✅ PID %d is not running", waitPID)
+		return
+	}
+	if err != nil {
+		exitWithError(false, exitCodeError, "Cannot wait for PID %d: %v", waitPID, err)
+	}
+
+	statusf(color.Cyan, "⏳ Waiting for PID %d (%s) to exit...", proc.PID, proc.Command)
+	start := time.Now()
+
+	if err := process.WaitForExitSince(ctx, waitPID, proc.StartTime, waitInterval); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			exitWithError(false, exitCodeError, "Timed out after %s: PID %d is still running", waitTimeout, waitPID)
+		}
+		exitWithError(false, exitCodeError, "Wait for PID %d stopped: %v", waitPID, err)
+	}
+
+	color.Green("✅ PID %d (%s) exited after %s", proc.PID, proc.Command, process.FormatUptime(time.Since(start)))
+
+	if waitNotify {
+		_ = beeep.Notify("portctl - Process Exited",
+			fmt.Sprintf("%s (PID %d) exited", proc.Command, proc.PID), "")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(waitCmd)
+
+	waitCmd.Flags().IntVarP(&waitPID, "pid", "p", 0,
+		"PID of the process to wait for")
+	waitCmd.Flags().DurationVar(&waitTimeout, "timeout", 0,
+		"Give up after this long (default: wait forever)")
+	waitCmd.Flags().DurationVarP(&waitInterval, "interval", "i", 500*time.Millisecond,
+		"How often to check whether the process is still running")
+	waitCmd.Flags().BoolVarP(&waitNotify, "notify", "n", false,
+		"Send a desktop notification when the process exits")
+}
@@ -2,9 +2,14 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand/v2"
 	"os"
+	"os/exec"
 	"os/signal"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -27,8 +32,24 @@ var (
 	watchChanges    bool
 	watchContinuous bool
 	watchCount      int
+	watchCPUThresh  float64
+	watchMemThresh  float64
+	watchExitThresh bool
+	watchEvents     bool
+	watchLog        string
+	watchLogFormat  string
+	watchJitter     float64
+	watchOnNew      string
+	watchOnGone     string
+	watchFlaps      bool
 )
 
+// watchHookTimeout bounds how long a single --on-new/--on-gone command may run
+const watchHookTimeout = 30 * time.Second
+
+// flapReportSize is how many ports the flapping report lists
+const flapReportSize = 10
+
 var watchCmd = &cobra.Command{
 	Use:   "watch [port]",
 	Short: "Watch processes on ports in real-time",
@@ -45,8 +66,36 @@ Examples:
   portctl watch                    # Watch all processes
   portctl watch 8080               # Watch specific port
   portctl watch --interval 2s     # Update every 2 seconds
+  portctl watch --interval 5s --jitter 0.2  # Every 4-6s, so watchers don't poll in lockstep
   portctl watch --notify           # Send desktop notifications
   portctl watch --changes-only     # Only show when changes occur
+  portctl watch --cpu-threshold 80 # Highlight processes above 80% CPU
+  portctl watch --mem-threshold 500 --exit-on-threshold  # Exit non-zero above 500MB
+  portctl watch --event-driven     # Refresh as soon as sockets open or close (Linux)
+  portctl watch --log changes.log  # Append NEW/GONE/CHANGED events to a file
+  portctl watch --log changes.ndjson --log-format json  # Log events as JSON lines
+  portctl watch 8080 --on-new 'systemctl restart proxy'  # Run a command when 8080 gets a listener
+  portctl watch --on-gone 'notify-send "{command} left port {port}"'
+  portctl watch --flap-report      # Keep a running list of ports that keep restarting
+
+Hooks:
+  --on-new and --on-gone run a command for every NEW or GONE event. The
+  command is split into words (single and double quotes group words) and run
+  directly, not through a shell, so shell syntax such as ; | & > < $ and
+  backticks is rejected; put anything more involved in a script. The
+  placeholders {port}, {pid}, {command} and {event} are replaced in each word,
+  and the same values are set as PORTCTL_PORT, PORTCTL_PID, PORTCTL_COMMAND
+  and PORTCTL_EVENT in the hook's environment. Hooks run one at a time, in
+  event order, for at most 30s each; their exit status and output are shown
+  with the changes and written to --log.
+
+Flapping:
+  Every time a port goes from busy to free or back counts as a transition. A
+  listener replaced between two refreshes (a new PID, or a reused PID with a
+  new start time) went free and busy again unseen, so it counts as two. When
+  the watch stops, the ports with the most transitions are listed, which
+  catches crash-looping servers; --flap-report also shows the list after
+  every refresh.
 `,
 	Args: cobra.MaximumNArgs(1),
 	Run:  runWatch,
@@ -56,7 +105,83 @@ type watchState struct {
 	processes    map[string]process.Process
 	lastUpdate   time.Time
 	changes      []string
+	events       []watchEvent
+	hookRuns     []hookRun
+	breaches     []string
+	inBreach     map[string]bool // "pid:port" of processes reported over a threshold
 	totalUpdates int
+	totalChanges int // NEW/GONE/CHANGED events seen since the watch started
+	flaps        map[int]portFlaps
+}
+
+// portFlaps counts how often a port switched between busy and free
+type portFlaps struct {
+	Port        int
+	Transitions int
+	LastChange  time.Time
+}
+
+// recordTransitions counts the busy/free transitions of each port between
+// two snapshots, given the events derived from them
+func (s *watchState) recordTransitions(previous, current []process.Process, events []watchEvent, now time.Time) {
+	wasBusy := make(map[int]bool)
+	for _, proc := range previous {
+		wasBusy[proc.Port] = true
+	}
+	isBusy := make(map[int]bool)
+	for _, proc := range current {
+		isBusy[proc.Port] = true
+	}
+
+	transitions := make(map[int]int)
+	for port := range wasBusy {
+		if !isBusy[port] {
+			transitions[port]++
+		}
+	}
+	for port := range isBusy {
+		if !wasBusy[port] {
+			transitions[port]++
+		}
+	}
+	// A replaced listener freed the port and took it again between refreshes
+	for _, event := range events {
+		if event.Type == "CHANGED" && (event.OldPID != event.PID || slices.Contains(event.Fields, "start_time")) {
+			transitions[event.Port] += 2
+		}
+	}
+
+	if s.flaps == nil {
+		s.flaps = make(map[int]portFlaps)
+	}
+	for port, n := range transitions {
+		f := s.flaps[port]
+		f.Port = port
+		f.Transitions += n
+		f.LastChange = now
+		s.flaps[port] = f
+	}
+}
+
+// topFlaps returns up to n ports that went free and busy again at least
+// once, most transitions first
+func (s *watchState) topFlaps(n int) []portFlaps {
+	var flaps []portFlaps
+	for _, f := range s.flaps {
+		if f.Transitions >= 2 {
+			flaps = append(flaps, f)
+		}
+	}
+	sort.Slice(flaps, func(i, j int) bool {
+		if flaps[i].Transitions != flaps[j].Transitions {
+			return flaps[i].Transitions > flaps[j].Transitions
+		}
+		return flaps[i].Port < flaps[j].Port
+	})
+	if len(flaps) > n {
+		flaps = flaps[:n]
+	}
+	return flaps
 }
 
 func runWatch(cmd *cobra.Command, args []string) {
@@ -70,8 +195,49 @@ func runWatch(cmd *cobra.Command, args []string) {
 			os.Exit(1)
 		}
 	}
+	if watchJitter < 0 || watchJitter >= 1 {
+		color.Red("Invalid --jitter %v: must be at least 0 and less than 1", watchJitter)
+		os.Exit(exitCodeUsage)
+	}
 
-	pm := process.NewProcessManager()
+	var hooks []watchHook
+	for _, spec := range []struct{ event, flag, value string }{
+		{"NEW", "--on-new", watchOnNew},
+		{"GONE", "--on-gone", watchOnGone},
+	} {
+		if spec.value == "" {
+			continue
+		}
+		hook, err := parseWatchHook(spec.event, spec.value)
+		if err != nil {
+			color.Red("Invalid %s: %v", spec.flag, err)
+			os.Exit(exitCodeUsage)
+		}
+		hooks = append(hooks, hook)
+	}
+
+	if watchLog == "" && cmd.Flags().Changed("log-format") {
+		color.Red("--log-format requires --log")
+		os.Exit(exitCodeUsage)
+	}
+	if watchLogFormat != "text" && watchLogFormat != "json" {
+		color.Red("Invalid --log-format %q (use text or json)", watchLogFormat)
+		os.Exit(exitCodeUsage)
+	}
+
+	var eventLog *changeLog
+	if watchLog != "" {
+		var err error
+		eventLog, err = openChangeLog(watchLog, watchLogFormat)
+		if err != nil {
+			// The format was checked above, so this is an I/O failure
+			color.Red("Error opening change log: %v", err)
+			os.Exit(exitCodeError)
+		}
+		defer func() { _ = eventLog.Close() }()
+	}
+
+	pm := newProcessManager()
 	ctx := cmd.Context()
 	state := &watchState{
 		processes: make(map[string]process.Process),
@@ -104,62 +270,119 @@ func runWatch(cmd *cobra.Command, args []string) {
 
 	// Print header
 	printWatchHeader(targetPort, state)
+	if watchExitThresh && len(state.breaches) > 0 {
+		printProcesses(state)
+		exitOnThreshold(state)
+	}
+
+	// A timer re-armed after every refresh, rather than a ticker, so that each
+	// wait can be jittered independently
+	timer := time.NewTimer(jitteredInterval(watchInterval, watchJitter))
+	defer timer.Stop()
 
-	ticker := time.NewTicker(watchInterval)
-	defer ticker.Stop()
+	// In event-driven mode socket table changes trigger an immediate refresh;
+	// the timer keeps CPU and memory figures current between events
+	var events <-chan struct{}
+	if watchEvents {
+		var err error
+		events, err = process.WatchSocketTables(ctx)
+		if err != nil {
+			color.Yellow("⚠️  %v; falling back to polling every %s", err, watchInterval)
+		}
+	}
 
 	updateCycles := 0
 
-	go func() {
-		for {
-			select {
-			case <-ticker.C:
-				if !watchContinuous {
-					s.Start()
-				}
+	refresh := func() {
+		if !watchContinuous {
+			s.Start()
+		}
 
-				if err := updateProcesses(ctx, pm, state, targetPort, true); err != nil {
-					if !watchContinuous {
-						s.Stop()
-					}
-					color.Red("\nError updating processes: %v", err)
-					continue
-				}
+		if err := updateProcesses(ctx, pm, state, targetPort, true); err != nil {
+			if !watchContinuous {
+				s.Stop()
+			}
+			color.Red("\nError updating processes: %v", err)
+			return
+		}
 
-				if !watchContinuous {
-					s.Stop()
-				}
+		if !watchContinuous {
+			s.Stop()
+		}
+
+		state.hookRuns = runWatchHooks(ctx, hooks, state.events)
+
+		// The change log is written regardless of what is shown on screen
+		if eventLog != nil {
+			if err := eventLog.write(state.events); err != nil {
+				color.Red("\nError writing change log: %v", err)
+			}
+			if err := eventLog.writeHookRuns(state.hookRuns); err != nil {
+				color.Red("\nError writing change log: %v", err)
+			}
+		}
+
+		// Only print if we have changes or not in changes-only mode
+		if !watchChanges || len(state.changes) > 0 {
+			// Clear screen and reprint
+			fmt.Print("\033[2J\033[H")
+			printWatchHeader(targetPort, state)
+			printProcesses(state)
 
-				// Only print if we have changes or not in changes-only mode
-				if !watchChanges || len(state.changes) > 0 {
-					// Clear screen and reprint
-					fmt.Print("\033[2J\033[H")
-					printWatchHeader(targetPort, state)
-					printProcesses(state)
-
-					if len(state.changes) > 0 {
-						printChanges(state)
-
-						// Send notification if enabled
-						if watchNotify {
-							sendNotification(state.changes, targetPort)
-						}
-					}
+			if len(state.changes) > 0 {
+				printChanges(state)
+				printHookRuns(state)
+
+				// Send notification if enabled
+				if watchNotify {
+					sendNotification(state.changes, targetPort)
 				}
+			}
+			if watchFlaps {
+				printFlapReport(state)
+			}
+		}
+
+		if watchExitThresh && len(state.breaches) > 0 {
+			if !watchContinuous {
+				s.Stop()
+			}
+			exitOnThreshold(state)
+		}
+
+		updateCycles++
+		if watchCount > 0 && updateCycles >= watchCount {
+			if !watchContinuous {
+				s.Stop()
+			}
+			printFlapReport(state)
+			color.Green("\n👋 Watch stopped after %d updates.", updateCycles)
+			os.Exit(0)
+		}
+	}
 
-				updateCycles++
-				if watchCount > 0 && updateCycles >= watchCount {
-					if !watchContinuous {
-						s.Stop()
-					}
-					color.Green("\n👋 Watch stopped after %d updates.", updateCycles)
-					os.Exit(0)
+	go func() {
+		for {
+			select {
+			case <-timer.C:
+				refresh()
+				timer.Reset(jitteredInterval(watchInterval, watchJitter))
+
+			case _, ok := <-events:
+				if !ok {
+					// The socket watcher stopped; keep polling on the timer
+					events = nil
+					continue
 				}
+				refresh()
+				// Postpone the next poll; the data was just refreshed
+				timer.Reset(jitteredInterval(watchInterval, watchJitter))
 
 			case <-c:
 				if !watchContinuous {
 					s.Stop()
 				}
+				printFlapReport(state)
 				color.Green("\n👋 Watch stopped. Total updates: %d", state.totalUpdates)
 				os.Exit(0)
 			}
@@ -178,6 +401,7 @@ func runWatch(cmd *cobra.Command, args []string) {
 	if !watchContinuous {
 		s.Stop()
 	}
+	printFlapReport(state)
 	color.Green("\n👋 Watch stopped. Total updates: %d", state.totalUpdates)
 }
 
@@ -195,9 +419,29 @@ func updateProcesses(ctx context.Context, pm *process.ProcessManager, state *wat
 		return err
 	}
 
+	// Check resource thresholds
+	state.breaches = nil
+	for _, proc := range processes {
+		if reason := thresholdBreach(proc); reason != "" {
+			state.breaches = append(state.breaches, reason)
+		}
+	}
+
 	// Detect changes if this is an update
 	if detectChanges {
-		state.changes = detectProcessChanges(state.processes, processes)
+		previous := make([]process.Process, 0, len(state.processes))
+		for _, proc := range state.processes {
+			previous = append(previous, proc)
+		}
+		now := time.Now()
+		state.events = changeEvents(process.DiffSnapshots(previous, processes), now)
+		state.recordTransitions(previous, processes, state.events, now)
+		state.totalChanges += len(state.events)
+		state.changes = nil
+		for _, event := range state.events {
+			state.changes = append(state.changes, event.String())
+		}
+		state.changes = append(state.changes, state.breachChanges(processes)...)
 		state.totalUpdates++
 	}
 
@@ -213,35 +457,340 @@ func updateProcesses(ctx context.Context, pm *process.ProcessManager, state *wat
 	return nil
 }
 
-func detectProcessChanges(oldProcs map[string]process.Process, newProcs []process.Process) []string {
-	var changes []string
+// jitteredInterval returns interval randomly scaled into
+// [interval*(1-jitter), interval*(1+jitter)]
+func jitteredInterval(interval time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return interval
+	}
+	factor := 1 + jitter*(2*rand.Float64()-1)
+	return time.Duration(float64(interval) * factor)
+}
 
-	// Create new process map
-	newProcMap := make(map[string]process.Process)
-	for _, proc := range newProcs {
-		key := fmt.Sprintf("%d:%d", proc.PID, proc.Port)
-		newProcMap[key] = proc
+// watchEvent is a process appearing on, disappearing from, or changing on a port
+type watchEvent struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"` // "NEW", "GONE" or "CHANGED"
+	PID     int       `json:"pid"`
+	Port    int       `json:"port"`
+	Command string    `json:"command"`
+	OldPID  int       `json:"old_pid,omitempty"` // CHANGED only
+	Fields  []string  `json:"fields,omitempty"`  // CHANGED only: fields that differ
+}
+
+// String renders the event the way it is shown on screen
+func (e watchEvent) String() string {
+	switch e.Type {
+	case "NEW":
+		return fmt.Sprintf("➕ NEW: %s (PID %d) on port %d", e.Command, e.PID, e.Port)
+	case "CHANGED":
+		pid := strconv.Itoa(e.PID)
+		if e.OldPID != e.PID {
+			pid = fmt.Sprintf("%d → %d", e.OldPID, e.PID)
+		}
+		return fmt.Sprintf("🔄 CHANGED: %s (PID %s) on port %d [%s]", e.Command, pid, e.Port, strings.Join(e.Fields, ", "))
+	default:
+		return fmt.Sprintf("➖ GONE: %s (PID %d) from port %d", e.Command, e.PID, e.Port)
 	}
+}
 
-	// Check for new processes
-	for key, proc := range newProcMap {
-		if _, exists := oldProcs[key]; !exists {
-			changes = append(changes, fmt.Sprintf("➕ NEW: %s (PID %d) on port %d",
-				proc.Command, proc.PID, proc.Port))
+// changeEvents converts a change set into timestamped events: new, then changed, then gone
+func changeEvents(changes process.ChangeSet, now time.Time) []watchEvent {
+	var events []watchEvent
+	for _, proc := range changes.Added {
+		events = append(events, watchEvent{Time: now, Type: "NEW", PID: proc.PID, Port: proc.Port, Command: proc.Command})
+	}
+	for _, change := range changes.Changed {
+		events = append(events, watchEvent{
+			Time:    now,
+			Type:    "CHANGED",
+			PID:     change.New.PID,
+			Port:    change.New.Port,
+			Command: change.New.Command,
+			OldPID:  change.Old.PID,
+			Fields:  change.Fields,
+		})
+	}
+	for _, proc := range changes.Removed {
+		events = append(events, watchEvent{Time: now, Type: "GONE", PID: proc.PID, Port: proc.Port, Command: proc.Command})
+	}
+	return events
+}
+
+// changeLog appends watch events to a file, one per line, as text or JSON
+type changeLog struct {
+	file *os.File
+	json bool
+}
+
+func openChangeLog(path, format string) (*changeLog, error) {
+	if format != "text" && format != "json" {
+		return nil, fmt.Errorf("invalid log format %q (use text or json)", format)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &changeLog{file: file, json: format == "json"}, nil
+}
+
+func (l *changeLog) write(events []watchEvent) error {
+	for _, e := range events {
+		var line []byte
+		if l.json {
+			data, err := json.Marshal(e)
+			if err != nil {
+				return err
+			}
+			line = append(data, '\n')
+		} else {
+			text := fmt.Sprintf("%s %-4s %s (PID %d) port %d",
+				e.Time.Format(time.RFC3339), e.Type, e.Command, e.PID, e.Port)
+			if e.Type == "CHANGED" {
+				text += fmt.Sprintf(" was PID %d [%s]", e.OldPID, strings.Join(e.Fields, ", "))
+			}
+			line = []byte(text + "\n")
+		}
+		if _, err := l.file.Write(line); err != nil {
+			return err
 		}
 	}
+	return nil
+}
 
-	// Check for removed processes
-	for key, proc := range oldProcs {
-		if _, exists := newProcMap[key]; !exists {
-			changes = append(changes, fmt.Sprintf("➖ GONE: %s (PID %d) from port %d",
-				proc.Command, proc.PID, proc.Port))
+// writeHookRuns logs each hook run after the events that triggered it
+func (l *changeLog) writeHookRuns(runs []hookRun) error {
+	for _, run := range runs {
+		var line []byte
+		if l.json {
+			data, err := json.Marshal(run)
+			if err != nil {
+				return err
+			}
+			line = append(data, '\n')
+		} else {
+			text := fmt.Sprintf("%s HOOK %s (PID %d) port %d: %s: %s",
+				run.Time.Format(time.RFC3339), run.Event, run.PID, run.Port, strings.Join(run.Args, " "), run.status())
+			if run.Output != "" {
+				text += "\n" + indentLines(run.Output, "  ")
+			}
+			line = []byte(text + "\n")
 		}
+		if _, err := l.file.Write(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *changeLog) Close() error {
+	return l.file.Close()
+}
+
+// watchHook is a command run for every watch event of one type
+type watchHook struct {
+	event string   // "NEW" or "GONE"
+	args  []string // command words, with placeholders still in them
+}
+
+// hookMetacharacters are rejected in hook commands: they are never run
+// through a shell, so a command relying on them would not do what it says
+var hookMetacharacters = ";&|><`$"
+
+// parseWatchHook splits a hook command into words. Single and double quotes
+// group words; there is no other shell syntax.
+func parseWatchHook(event, command string) (watchHook, error) {
+	if strings.ContainsAny(command, hookMetacharacters) {
+		return watchHook{}, fmt.Errorf("%q contains shell metacharacters (%s); hooks do not run through a shell, so use a script instead", command, hookMetacharacters)
+	}
+
+	var args []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range command {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return watchHook{}, fmt.Errorf("%q has an unterminated %c quote", command, quote)
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	if len(args) == 0 {
+		return watchHook{}, fmt.Errorf("command must not be empty")
+	}
+
+	return watchHook{event: event, args: args}, nil
+}
+
+// expand substitutes the event's details into each word separately, so a
+// command name containing spaces or quotes stays a single argument
+func (h watchHook) expand(e watchEvent) []string {
+	replacer := strings.NewReplacer(
+		"{port}", strconv.Itoa(e.Port),
+		"{pid}", strconv.Itoa(e.PID),
+		"{command}", e.Command,
+		"{event}", e.Type,
+	)
+	args := make([]string, len(h.args))
+	for i, arg := range h.args {
+		args[i] = replacer.Replace(arg)
+	}
+	return args
+}
+
+// hookRun records one execution of a hook
+type hookRun struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"` // always "HOOK", to tell runs from events in the log
+	Event    string    `json:"event"`
+	PID      int       `json:"pid"`
+	Port     int       `json:"port"`
+	Command  string    `json:"command"`
+	Args     []string  `json:"args"`
+	ExitCode int       `json:"exit_code"` // -1 if the command could not be run or timed out
+	Error    string    `json:"error,omitempty"`
+	Output   string    `json:"output,omitempty"` // combined stdout and stderr
+}
+
+// status describes how the run ended, e.g. "exit 0" or "exit -1 (timed out)"
+func (r hookRun) status() string {
+	if r.Error != "" {
+		return fmt.Sprintf("exit %d (%s)", r.ExitCode, r.Error)
+	}
+	return fmt.Sprintf("exit %d", r.ExitCode)
+}
+
+// runWatchHooks runs the matching hooks for each event, in event order, and
+// waits for each to finish or time out
+func runWatchHooks(ctx context.Context, hooks []watchHook, events []watchEvent) []hookRun {
+	var runs []hookRun
+	for _, event := range events {
+		for _, hook := range hooks {
+			if hook.event == event.Type {
+				runs = append(runs, runWatchHook(ctx, hook, event))
+			}
+		}
+	}
+	return runs
+}
+
+func runWatchHook(ctx context.Context, hook watchHook, event watchEvent) hookRun {
+	args := hook.expand(event)
+	run := hookRun{
+		Time:    time.Now(),
+		Type:    "HOOK",
+		Event:   event.Type,
+		PID:     event.PID,
+		Port:    event.Port,
+		Command: event.Command,
+		Args:    args,
 	}
 
+	ctx, cancel := context.WithTimeout(ctx, watchHookTimeout)
+	defer cancel()
+
+	// #nosec G204: The user chose the command; event details are passed as
+	// separate arguments and never interpreted by a shell
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Env = append(os.Environ(),
+		"PORTCTL_EVENT="+event.Type,
+		"PORTCTL_PORT="+strconv.Itoa(event.Port),
+		"PORTCTL_PID="+strconv.Itoa(event.PID),
+		"PORTCTL_COMMAND="+event.Command,
+	)
+	output, err := cmd.CombinedOutput()
+	run.Output = strings.TrimRight(string(output), "\n")
+
+	var exitErr *exec.ExitError
+	switch {
+	case ctx.Err() == context.DeadlineExceeded:
+		run.ExitCode = -1
+		run.Error = fmt.Sprintf("timed out after %s", watchHookTimeout)
+	case errors.As(err, &exitErr):
+		run.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		run.ExitCode = -1
+		run.Error = err.Error()
+	}
+	return run
+}
+
+// indentLines prefixes every line of s with indent
+func indentLines(s, indent string) string {
+	return indent + strings.ReplaceAll(s, "\n", "\n"+indent)
+}
+
+// thresholdBreach describes how a process exceeds the configured CPU or memory
+// thresholds, or returns an empty string if it is within limits
+func thresholdBreach(proc process.Process) string {
+	var reasons []string
+	if watchCPUThresh > 0 && proc.CPUPercent > watchCPUThresh {
+		reasons = append(reasons, fmt.Sprintf("CPU %.1f%% > %.1f%%", proc.CPUPercent, watchCPUThresh))
+	}
+	if watchMemThresh > 0 && float64(proc.MemoryMB) > watchMemThresh {
+		reasons = append(reasons, fmt.Sprintf("Mem %.1fMB > %.1fMB", proc.MemoryMB, watchMemThresh))
+	}
+	if len(reasons) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("⚠️  THRESHOLD: %s (PID %d) on port %d: %s",
+		proc.Command, proc.PID, proc.Port, strings.Join(reasons, ", "))
+}
+
+// breachChanges returns a THRESHOLD line for each process that went over a
+// threshold since the last call and a RECOVERED line for each that came back
+// under, so a lasting breach is reported (and notified) once rather than on
+// every refresh. A process that left its port is covered by its GONE event.
+func (s *watchState) breachChanges(processes []process.Process) []string {
+	var changes []string
+	current := make(map[string]bool)
+	for _, proc := range processes {
+		key := fmt.Sprintf("%d:%d", proc.PID, proc.Port)
+		reason := thresholdBreach(proc)
+		switch {
+		case reason != "":
+			current[key] = true
+			if !s.inBreach[key] {
+				changes = append(changes, reason)
+			}
+		case s.inBreach[key]:
+			changes = append(changes, fmt.Sprintf("✅ RECOVERED: %s (PID %d) on port %d is back under the thresholds",
+				proc.Command, proc.PID, proc.Port))
+		}
+	}
+	s.inBreach = current
 	return changes
 }
 
+func exitOnThreshold(state *watchState) {
+	color.Red("\n🚨 Resource threshold breached by %d process(es):", len(state.breaches))
+	for _, breach := range state.breaches {
+		color.Red("  %s", breach)
+	}
+	os.Exit(exitCodeError)
+}
+
 func printWatchHeader(targetPort int, state *watchState) {
 	// Title
 	title := "🔍 portctl Watch Mode"
@@ -250,14 +799,35 @@ func printWatchHeader(targetPort int, state *watchState) {
 	}
 	color.Cyan(title)
 
-	// Status line
-	status := fmt.Sprintf("Last Update: %s | Processes: %d | Updates: %d",
-		state.lastUpdate.Format("15:04:05"),
-		len(state.processes),
-		state.totalUpdates)
+	// Status line. state.processes holds one entry per PID and port, so count
+	// distinct PIDs and ports separately.
+	pids := make(map[int]bool)
+	ports := make(map[int]bool)
+	for _, proc := range state.processes {
+		pids[proc.PID] = true
+		ports[proc.Port] = true
+	}
+
+	status := fmt.Sprintf("Last Update: %s | ", state.lastUpdate.Format("15:04:05"))
+	if targetPort > 0 {
+		if len(ports) > 0 {
+			status += fmt.Sprintf("Port %d: busy (%d process(es))", targetPort, len(pids))
+		} else {
+			status += fmt.Sprintf("Port %d: free", targetPort)
+		}
+	} else {
+		status += fmt.Sprintf("Processes: %d | Ports: %d", len(pids), len(ports))
+	}
+	status += fmt.Sprintf(" | Updates: %d | Changes: %d", state.totalUpdates, state.totalChanges)
 
 	if watchInterval > 0 {
 		status += fmt.Sprintf(" | Interval: %s", watchInterval)
+		if watchJitter > 0 {
+			status += fmt.Sprintf(" ±%.0f%%", watchJitter*100)
+		}
+	}
+	if watchEvents {
+		status += " | Event-driven"
 	}
 
 	color.White(status)
@@ -283,7 +853,7 @@ func printProcesses(state *watchState) {
 	t := tablepretty.NewWriter()
 	t.SetOutputMirror(os.Stdout)
 	t.SetStyle(tablepretty.StyleColoredBright)
-	t.AppendHeader(tablepretty.Row{"PID", "Port", "Protocol", "Service", "Command", "CPU%", "Mem(MB)", "User"})
+	appendTableHeader(t, tablepretty.Row{"PID", "Port", "Protocol", "Service", "Command", "CPU%", "Memory", "User"})
 	t.Style().Color.Header = text.Colors{text.FgHiBlue, text.Bold}
 	t.SetColumnConfigs([]tablepretty.ColumnConfig{
 		{Number: 1, Align: text.AlignRight},                                              // PID
@@ -292,19 +862,35 @@ func printProcesses(state *watchState) {
 		{Number: 4, Align: text.AlignCenter},                                             // Service
 		{Number: 5, Align: text.AlignLeft},                                               // Command
 		{Number: 6, Align: text.AlignRight},                                              // CPU%
-		{Number: 7, Align: text.AlignRight},                                              // Mem(MB)
+		{Number: 7, Align: text.AlignRight},                                              // Memory
 		{Number: 8, Align: text.AlignLeft},                                               // User
 	})
 
+	// Crossing an alert threshold always shows red; otherwise cells are
+	// graded against the display thresholds
+	cpuWarn, memWarn := usageThresholds()
 	for _, proc := range processes {
+		cpu := fmt.Sprintf("%.1f", proc.CPUPercent)
+		if watchCPUThresh > 0 && proc.CPUPercent > watchCPUThresh {
+			cpu = text.FgHiRed.Sprint(cpu)
+		} else {
+			cpu = usageCell(cpu, proc.CPUPercent, cpuWarn)
+		}
+		mem := process.FormatMemory(float64(proc.MemoryMB))
+		if watchMemThresh > 0 && float64(proc.MemoryMB) > watchMemThresh {
+			mem = text.FgHiRed.Sprint(mem)
+		} else {
+			mem = usageCell(mem, float64(proc.MemoryMB), memWarn)
+		}
+
 		row := tablepretty.Row{
 			proc.PID,
 			proc.Port,
 			proc.Protocol,
 			proc.ServiceType,
 			proc.Command,
-			fmt.Sprintf("%.1f", proc.CPUPercent),
-			fmt.Sprintf("%.1f", proc.MemoryMB),
+			cpu,
+			mem,
 			proc.User,
 		}
 		t.AppendRow(row)
@@ -319,14 +905,50 @@ func printChanges(state *watchState) {
 
 	fmt.Println("\n📊 Changes Detected:")
 	for _, change := range state.changes {
-		if strings.Contains(change, "NEW") {
+		switch {
+		case strings.Contains(change, "NEW"):
 			color.Green("  %s", change)
-		} else {
+		case strings.Contains(change, "CHANGED"):
+			color.Yellow("  %s", change)
+		default:
 			color.Red("  %s", change)
 		}
 	}
 }
 
+// printHookRuns shows the outcome and output of the hooks run for the last changes
+func printHookRuns(state *watchState) {
+	if len(state.hookRuns) == 0 {
+		return
+	}
+
+	fmt.Println("\n🪝 Hooks:")
+	for _, run := range state.hookRuns {
+		print := color.Green
+		if run.ExitCode != 0 {
+			print = color.Red
+		}
+		print("  %s (%s on port %d): %s", strings.Join(run.Args, " "), run.Event, run.Port, run.status())
+		if run.Output != "" {
+			fmt.Println(indentLines(run.Output, "    "))
+		}
+	}
+}
+
+// printFlapReport lists the ports that flapped most since the watch started
+func printFlapReport(state *watchState) {
+	flaps := state.topFlaps(flapReportSize)
+	if len(flaps) == 0 {
+		return
+	}
+
+	fmt.Println("\n🔁 Flapping Ports:")
+	for _, f := range flaps {
+		color.Yellow("  Port %d: %d busy/free transitions (last %s)",
+			f.Port, f.Transitions, f.LastChange.Format("15:04:05"))
+	}
+}
+
 func sendNotification(changes []string, targetPort int) {
 	if len(changes) == 0 {
 		return
@@ -359,4 +981,26 @@ func init() {
 		"Continuous output without clearing screen")
 	watchCmd.Flags().IntVar(&watchCount, "count", 0,
 		"Number of update cycles before exiting (default: unlimited)")
+	watchCmd.Flags().Float64Var(&watchCPUThresh, "cpu-threshold", 0,
+		"Flag processes using more than X% CPU")
+	watchCmd.Flags().Float64Var(&watchMemThresh, "mem-threshold", 0,
+		"Flag processes using more than X MB of memory")
+	watchCmd.Flags().BoolVar(&watchExitThresh, "exit-on-threshold", false,
+		"Exit with a non-zero code when a threshold is breached")
+	watchCmd.Flags().BoolVar(&watchEvents, "event-driven", false,
+		"Refresh immediately on socket table changes where supported (Linux), polling otherwise")
+	watchCmd.Flags().StringVar(&watchLog, "log", "",
+		"Append every NEW/GONE/CHANGED event with a timestamp to this file")
+	watchCmd.Flags().StringVar(&watchLogFormat, "log-format", "text",
+		"Format of the --log change log: text or json")
+	watchCmd.Flags().Float64Var(&watchJitter, "jitter", 0,
+		"Randomize each interval by up to this fraction (e.g. 0.2 for ±20%) to spread out polling")
+	watchCmd.Flags().StringVar(&watchOnNew, "on-new", "",
+		"Command to run for each NEW event; {port}, {pid}, {command} and {event} are substituted (no shell)")
+	watchCmd.Flags().StringVar(&watchOnGone, "on-gone", "",
+		"Command to run for each GONE event; {port}, {pid}, {command} and {event} are substituted (no shell)")
+	watchCmd.Flags().BoolVar(&watchFlaps, "flap-report", false,
+		"Show the ports that most often switched between busy and free after every refresh, not only on exit")
+	watchCmd.Flags().BoolVar(&tableNoHeader, "no-header", false,
+		"Omit the table header row, for scripts")
 }
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	process "dagger/portctl/pkg"
+)
+
+func TestParseWatchHook(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{"systemctl restart proxy", []string{"systemctl", "restart", "proxy"}, false},
+		{`notify-send "{command} left port {port}"`, []string{"notify-send", "{command} left port {port}"}, false},
+		{`echo 'a  b' ""`, []string{"echo", "a  b", ""}, false},
+		{"  echo\tx  ", []string{"echo", "x"}, false},
+		{"", nil, true},
+		{"   ", nil, true},
+		{`echo "unterminated`, nil, true},
+		{"echo hi; rm -rf /", nil, true},
+		{"echo $HOME", nil, true},
+		{"echo `id`", nil, true},
+		{"cat < /etc/passwd", nil, true},
+	}
+	for _, tt := range tests {
+		hook, err := parseWatchHook("NEW", tt.command)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseWatchHook(%q) = %q, want error", tt.command, hook.args)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseWatchHook(%q) error: %v", tt.command, err)
+			continue
+		}
+		if !slices.Equal(hook.args, tt.want) {
+			t.Errorf("parseWatchHook(%q) = %q, want %q", tt.command, hook.args, tt.want)
+		}
+	}
+}
+
+func TestWatchHookExpandKeepsArguments(t *testing.T) {
+	hook, err := parseWatchHook("GONE", "echo {event} {port} {pid} {command}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A hostile command name must stay a single argument
+	event := watchEvent{Type: "GONE", PID: 42, Port: 8080, Command: "evil; rm -rf ~"}
+	want := []string{"echo", "GONE", "8080", "42", "evil; rm -rf ~"}
+	if got := hook.expand(event); !slices.Equal(got, want) {
+		t.Errorf("expand() = %q, want %q", got, want)
+	}
+}
+
+func TestRunWatchHooks(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	// Built directly, as parseWatchHook rejects the $ and ; a script would use
+	onNew := watchHook{event: "NEW", args: []string{"sh", "-c", `echo "$PORTCTL_EVENT $PORTCTL_PORT $PORTCTL_COMMAND {pid}"; exit 3`}}
+	onGone := watchHook{event: "GONE", args: []string{"portctl-no-such-command"}}
+
+	events := []watchEvent{
+		{Type: "NEW", PID: 7, Port: 3000, Command: "node"},
+		{Type: "CHANGED", PID: 8, OldPID: 7, Port: 3000, Command: "node"},
+		{Type: "GONE", PID: 8, Port: 3000, Command: "node"},
+	}
+	runs := runWatchHooks(context.Background(), []watchHook{onNew, onGone}, events)
+	if len(runs) != 2 {
+		t.Fatalf("got %d runs, want 2 (CHANGED has no hook)", len(runs))
+	}
+
+	if runs[0].Event != "NEW" || runs[0].ExitCode != 3 || runs[0].Output != "NEW 3000 node 7" {
+		t.Errorf("NEW run = %+v, want exit 3 and output %q", runs[0], "NEW 3000 node 7")
+	}
+	if runs[1].Event != "GONE" || runs[1].ExitCode != -1 || runs[1].Error == "" {
+		t.Errorf("GONE run = %+v, want exit -1 with an error", runs[1])
+	}
+}
+
+func TestWatchStateRecordTransitions(t *testing.T) {
+	state := &watchState{}
+	node := process.Process{PID: 10, Port: 3000, Command: "node"}
+	api := process.Process{PID: 20, Port: 8080, Command: "api"}
+	snapshots := [][]process.Process{
+		{node, api},
+		{api},       // 3000 goes free
+		{node, api}, // and comes back
+		{{PID: 11, Port: 3000, Command: "node"}, api}, // restarted between refreshes
+		{}, // everything stops
+	}
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := 1; i < len(snapshots); i++ {
+		events := changeEvents(process.DiffSnapshots(snapshots[i-1], snapshots[i]), now)
+		state.recordTransitions(snapshots[i-1], snapshots[i], events, now)
+	}
+
+	if got := state.flaps[3000].Transitions; got != 5 {
+		t.Errorf("port 3000 transitions = %d, want 5", got)
+	}
+	if got := state.flaps[8080].Transitions; got != 1 {
+		t.Errorf("port 8080 transitions = %d, want 1", got)
+	}
+
+	// A port that only went away once is not flapping
+	top := state.topFlaps(flapReportSize)
+	if len(top) != 1 || top[0].Port != 3000 {
+		t.Errorf("topFlaps() = %+v, want only port 3000", top)
+	}
+}
+
+func TestWatchBreachReportedOnce(t *testing.T) {
+	saved := watchCPUThresh
+	watchCPUThresh = 50
+	t.Cleanup(func() { watchCPUThresh = saved })
+
+	state := &watchState{}
+	hot := process.Process{PID: 10, Port: 3000, Command: "node", CPUPercent: 90}
+	cool := hot
+	cool.CPUPercent = 5
+
+	refreshes := []struct {
+		processes []process.Process
+		want      []string // prefixes of the expected change lines
+	}{
+		{[]process.Process{hot}, []string{"⚠️  THRESHOLD"}},
+		{[]process.Process{hot}, nil}, // still over: not reported again
+		{[]process.Process{hot}, nil},
+		{[]process.Process{cool}, []string{"✅ RECOVERED"}},
+		{[]process.Process{cool}, nil},
+		{[]process.Process{hot}, []string{"⚠️  THRESHOLD"}}, // a new breach
+		{nil, nil}, // gone: left to the GONE event
+		{[]process.Process{hot}, []string{"⚠️  THRESHOLD"}},
+	}
+	for i, r := range refreshes {
+		got := state.breachChanges(r.processes)
+		if len(got) != len(r.want) {
+			t.Fatalf("refresh %d: changes = %q, want %d line(s)", i, got, len(r.want))
+		}
+		for j, prefix := range r.want {
+			if !strings.HasPrefix(got[j], prefix) {
+				t.Errorf("refresh %d: change %q, want prefix %q", i, got[j], prefix)
+			}
+		}
+	}
+}
+
+func TestWatchLogExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	if got := runPortctl(t, "watch", "--log", filepath.Join(dir, "missing", "changes.log"), "--count", "1"); got != exitCodeError {
+		t.Errorf("watch with an unwritable --log exited %d, want %d", got, exitCodeError)
+	}
+	if got := runPortctl(t, "watch", "--log", filepath.Join(dir, "changes.log"), "--log-format", "xml"); got != exitCodeUsage {
+		t.Errorf("watch with an invalid --log-format exited %d, want %d", got, exitCodeUsage)
+	}
+}
@@ -0,0 +1,162 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severity ranks how serious an audit finding is
+type Severity int
+
+// Audit severities, from least to most serious
+const (
+	SeverityLow Severity = iota + 1
+	SeverityMedium
+	SeverityHigh
+	SeverityCritical
+)
+
+var severityNames = map[Severity]string{
+	SeverityLow:      "low",
+	SeverityMedium:   "medium",
+	SeverityHigh:     "high",
+	SeverityCritical: "critical",
+}
+
+// String returns the lowercase severity name
+func (s Severity) String() string {
+	if name, ok := severityNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// MarshalText encodes the severity by name so JSON output stays readable
+func (s Severity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// ParseSeverity parses a severity name such as "high" (case-insensitive)
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if strings.EqualFold(name, n) {
+			return s, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid severity %q (must be one of: low, medium, high, critical)", name)
+}
+
+// AuditRule flags a sensitive port when its listener is bound to all interfaces
+type AuditRule struct {
+	Port     int
+	Service  string
+	Category string
+	Severity Severity
+}
+
+// DefaultAuditRules are the sensitive ports checked by Audit
+var DefaultAuditRules = []AuditRule{
+	// Debuggers allow arbitrary code execution
+	{Port: 9229, Service: "Node.js inspector", Category: "debug", Severity: SeverityCritical},
+	{Port: 5005, Service: "JVM debugger (JDWP)", Category: "debug", Severity: SeverityCritical},
+	{Port: 5678, Service: "Python debugger (debugpy)", Category: "debug", Severity: SeverityCritical},
+	{Port: 2345, Service: "Go debugger (Delve)", Category: "debug", Severity: SeverityCritical},
+	{Port: 2375, Service: "Docker API (unencrypted)", Category: "admin", Severity: SeverityCritical},
+
+	// Databases and caches
+	{Port: 3306, Service: "MySQL", Category: "database", Severity: SeverityHigh},
+	{Port: 5432, Service: "PostgreSQL", Category: "database", Severity: SeverityHigh},
+	{Port: 1433, Service: "SQL Server", Category: "database", Severity: SeverityHigh},
+	{Port: 1521, Service: "Oracle", Category: "database", Severity: SeverityHigh},
+	{Port: 27017, Service: "MongoDB", Category: "database", Severity: SeverityHigh},
+	{Port: 6379, Service: "Redis", Category: "database", Severity: SeverityHigh},
+	{Port: 11211, Service: "Memcached", Category: "database", Severity: SeverityHigh},
+	{Port: 9200, Service: "Elasticsearch", Category: "database", Severity: SeverityHigh},
+	{Port: 5984, Service: "CouchDB", Category: "database", Severity: SeverityHigh},
+	{Port: 9042, Service: "Cassandra", Category: "database", Severity: SeverityHigh},
+
+	// Admin panels and remote access
+	{Port: 2376, Service: "Docker API (TLS)", Category: "admin", Severity: SeverityMedium},
+	{Port: 10250, Service: "Kubelet API", Category: "admin", Severity: SeverityHigh},
+	{Port: 8500, Service: "Consul", Category: "admin", Severity: SeverityMedium},
+	{Port: 15672, Service: "RabbitMQ management", Category: "admin", Severity: SeverityMedium},
+	{Port: 5601, Service: "Kibana", Category: "admin", Severity: SeverityMedium},
+	{Port: 9090, Service: "Prometheus", Category: "admin", Severity: SeverityMedium},
+	{Port: 5900, Service: "VNC", Category: "admin", Severity: SeverityMedium},
+	{Port: 3389, Service: "RDP", Category: "admin", Severity: SeverityMedium},
+	{Port: 23, Service: "Telnet", Category: "admin", Severity: SeverityHigh},
+	{Port: 21, Service: "FTP", Category: "admin", Severity: SeverityLow},
+}
+
+// AuditFinding is a sensitive service reachable from other hosts
+type AuditFinding struct {
+	Severity  Severity `json:"severity"`
+	Category  string   `json:"category"`
+	Service   string   `json:"service"`
+	Port      int      `json:"port"`
+	PID       int      `json:"pid"`
+	Command   string   `json:"command"`
+	LocalAddr string   `json:"local_addr"`
+	Message   string   `json:"message"`
+}
+
+// Audit checks processes against rules and returns one finding per exposed
+// port and PID, most severe first. Only listeners bound to all interfaces are
+// reported; loopback and address-specific listeners are not flagged.
+func Audit(processes []Process, rules []AuditRule) []AuditFinding {
+	byPort := make(map[int]AuditRule, len(rules))
+	for _, rule := range rules {
+		byPort[rule.Port] = rule
+	}
+
+	type key struct{ pid, port int }
+	seen := make(map[key]bool)
+	var findings []AuditFinding
+
+	for _, proc := range processes {
+		rule, ok := byPort[proc.Port]
+		if !ok || DetectBindScope(proc.LocalAddr) != BindScopeAll {
+			continue
+		}
+		k := key{proc.PID, proc.Port}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+
+		findings = append(findings, AuditFinding{
+			Severity:  rule.Severity,
+			Category:  rule.Category,
+			Service:   rule.Service,
+			Port:      proc.Port,
+			PID:       proc.PID,
+			Command:   proc.Command,
+			LocalAddr: proc.LocalAddr,
+			Message:   fmt.Sprintf("%s on port %d is reachable from all interfaces", rule.Service, proc.Port),
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return findings[i].Severity > findings[j].Severity
+		}
+		if findings[i].Port != findings[j].Port {
+			return findings[i].Port < findings[j].Port
+		}
+		return findings[i].PID < findings[j].PID
+	})
+
+	return findings
+}
+
+// CountFindings returns how many findings are at least as severe as min
+func CountFindings(findings []AuditFinding, min Severity) int {
+	count := 0
+	for _, f := range findings {
+		if f.Severity >= min {
+			count++
+		}
+	}
+	return count
+}
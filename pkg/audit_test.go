@@ -0,0 +1,85 @@
+package process
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	processes := []Process{
+		{PID: 10, Port: 5432, Command: "postgres", LocalAddr: "*:5432"},
+		{PID: 10, Port: 5432, Command: "postgres", LocalAddr: "[::]:5432"},
+		{PID: 11, Port: 6379, Command: "redis-server", LocalAddr: "127.0.0.1:6379"},
+		{PID: 12, Port: 9229, Command: "node", LocalAddr: "0.0.0.0:9229"},
+		{PID: 13, Port: 3306, Command: "mysqld", LocalAddr: "192.168.1.5:3306"},
+		{PID: 14, Port: 8080, Command: "java", LocalAddr: "*:8080"},
+		{PID: 15, Port: 21, Command: "vsftpd", LocalAddr: "*:21"},
+	}
+
+	findings := Audit(processes, DefaultAuditRules)
+	if len(findings) != 3 {
+		t.Fatalf("Expected 3 findings, got %d: %+v", len(findings), findings)
+	}
+
+	wantPorts := []int{9229, 5432, 21}
+	for i, port := range wantPorts {
+		if findings[i].Port != port {
+			t.Errorf("Finding %d: expected port %d, got %d", i, port, findings[i].Port)
+		}
+	}
+	if findings[0].Severity != SeverityCritical || findings[0].Category != "debug" {
+		t.Errorf("Expected critical debug finding first, got %+v", findings[0])
+	}
+}
+
+func TestCountFindings(t *testing.T) {
+	findings := []AuditFinding{
+		{Severity: SeverityCritical},
+		{Severity: SeverityHigh},
+		{Severity: SeverityMedium},
+		{Severity: SeverityLow},
+	}
+
+	tests := []struct {
+		min  Severity
+		want int
+	}{
+		{SeverityLow, 4},
+		{SeverityMedium, 3},
+		{SeverityHigh, 2},
+		{SeverityCritical, 1},
+	}
+	for _, tt := range tests {
+		if got := CountFindings(findings, tt.min); got != tt.want {
+			t.Errorf("CountFindings(%s) = %d, want %d", tt.min, got, tt.want)
+		}
+	}
+}
+
+func TestParseSeverity(t *testing.T) {
+	for _, name := range []string{"low", "Medium", "HIGH", "critical"} {
+		if _, err := ParseSeverity(name); err != nil {
+			t.Errorf("ParseSeverity(%q) returned error: %v", name, err)
+		}
+	}
+	if s, _ := ParseSeverity("high"); s != SeverityHigh {
+		t.Errorf("ParseSeverity(high) = %v, want %v", s, SeverityHigh)
+	}
+	if _, err := ParseSeverity("severe"); err == nil {
+		t.Error("Expected error for unknown severity")
+	}
+}
+
+func TestAuditFindingJSON(t *testing.T) {
+	data, err := json.Marshal(AuditFinding{Severity: SeverityHigh, Port: 5432})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded["severity"] != "high" {
+		t.Errorf("Expected severity to encode as \"high\", got %v", decoded["severity"])
+	}
+}
@@ -0,0 +1,180 @@
+package process
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+	"strings"
+)
+
+// AnyOtherAddress is the ListenerRoute destination for connections to an
+// address of this host that no listener is bound to specifically
+const AnyOtherAddress = "any other address"
+
+// ListenerRoute says which listening sockets receive connections to one
+// destination address
+type ListenerRoute struct {
+	Destination string    `json:"destination"` // Address the client dials, or AnyOtherAddress
+	Winners     []Process `json:"winners"`     // Several when sockets tie, e.g. with SO_REUSEPORT
+	Reason      string    `json:"reason"`
+	Shadowed    []Process `json:"shadowed"` // Other listeners on the port that do not get this traffic
+}
+
+// bindAddr is the parsed local address of a listener
+type bindAddr struct {
+	addr     netip.Addr // Invalid for "*"
+	wildcard bool
+}
+
+// parseBindAddr parses the host part of a local address such as "*:8080",
+// "0.0.0.0:80", "[::1]:3000" or "192.168.1.5:22"
+func parseBindAddr(localAddr string) (bindAddr, bool) {
+	host := localAddrHost(localAddr)
+	switch host {
+	case "*":
+		return bindAddr{wildcard: true}, true
+	case "localhost":
+		return bindAddr{addr: netip.MustParseAddr("127.0.0.1")}, true
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return bindAddr{}, false
+	}
+	addr = addr.Unmap()
+	return bindAddr{addr: addr, wildcard: addr.IsUnspecified()}, true
+}
+
+// bindScore ranks how specifically a listener matches a connection to dst,
+// following the Linux socket lookup: an exact address beats a wildcard, and
+// for IPv4 traffic an IPv4 wildcard beats a dual-stack IPv6 one. With
+// anyOther set, dst stands for an address no listener is bound to. A
+// negative score means the listener cannot receive the connection.
+func bindScore(bind bindAddr, dst netip.Addr, anyOther bool) int {
+	if !bind.wildcard {
+		if !anyOther && bind.addr == dst {
+			return 2
+		}
+		return -1
+	}
+	switch {
+	case !bind.addr.IsValid(): // "*": lsof does not say which family
+		return 1
+	case bind.addr.Is4() && dst.Is4(), bind.addr.Is6() && dst.Is6():
+		return 1
+	case bind.addr.Is6() && dst.Is4():
+		return 0 // Accepted through v4-mapped addresses unless IPV6_V6ONLY is set
+	default:
+		return -1
+	}
+}
+
+// portListeners returns the listening sockets on port for protocol ("tcp" or
+// "udp"), with IPv6 variants such as "tcp6" included
+func portListeners(processes []Process, port int, protocol string) []Process {
+	var listeners []Process
+	for _, proc := range processes {
+		if proc.Port == port && strings.TrimSuffix(proc.Protocol, "6") == protocol && isListener(proc) {
+			listeners = append(listeners, proc)
+		}
+	}
+	return listeners
+}
+
+// ResolveListener reports which listeners on port receive connections to dst
+func ResolveListener(processes []Process, port int, protocol string, dst netip.Addr) ListenerRoute {
+	dst = dst.Unmap()
+	return resolveRoute(portListeners(processes, port, protocol), dst.String(), dst, false)
+}
+
+// ResolveTraffic reports which listeners on port receive traffic from a
+// client at from. A loopback client is assumed to dial its own address. A
+// remote client can only dial this host's non-loopback addresses, so there is
+// one route for each such address a listener is bound to, and one for
+// AnyOtherAddress, which only wildcard listeners receive.
+func ResolveTraffic(processes []Process, port int, protocol string, from netip.Addr) []ListenerRoute {
+	from = from.Unmap()
+	listeners := portListeners(processes, port, protocol)
+	if from.IsLoopback() {
+		return []ListenerRoute{resolveRoute(listeners, from.String(), from, false)}
+	}
+
+	seen := make(map[netip.Addr]bool)
+	var specific []netip.Addr
+	for _, proc := range listeners {
+		bind, ok := parseBindAddr(proc.LocalAddr)
+		if !ok || bind.wildcard || bind.addr.IsLoopback() || bind.addr.Is4() != from.Is4() || seen[bind.addr] {
+			continue
+		}
+		seen[bind.addr] = true
+		specific = append(specific, bind.addr)
+	}
+	sort.Slice(specific, func(i, j int) bool { return specific[i].Less(specific[j]) })
+
+	var routes []ListenerRoute
+	for _, addr := range specific {
+		routes = append(routes, resolveRoute(listeners, addr.String(), addr, false))
+	}
+	// An unspecified address of the client's family stands for the rest
+	other := netip.IPv4Unspecified()
+	if !from.Is4() {
+		other = netip.IPv6Unspecified()
+	}
+	return append(routes, resolveRoute(listeners, AnyOtherAddress, other, true))
+}
+
+// resolveRoute picks the best-scoring listeners for dst
+func resolveRoute(listeners []Process, destination string, dst netip.Addr, anyOther bool) ListenerRoute {
+	route := ListenerRoute{Destination: destination, Winners: []Process{}, Shadowed: []Process{}}
+
+	best := -1
+	scores := make([]int, len(listeners))
+	for i, proc := range listeners {
+		scores[i] = -1
+		if bind, ok := parseBindAddr(proc.LocalAddr); ok {
+			scores[i] = bindScore(bind, dst, anyOther)
+		}
+		if scores[i] > best {
+			best = scores[i]
+		}
+	}
+
+	for i, proc := range listeners {
+		if best >= 0 && scores[i] == best {
+			route.Winners = append(route.Winners, proc)
+		} else {
+			route.Shadowed = append(route.Shadowed, proc)
+		}
+	}
+
+	route.Reason = routeReason(route.Winners, best)
+	return route
+}
+
+// routeReason explains why the winners were chosen
+func routeReason(winners []Process, score int) string {
+	if len(winners) == 0 {
+		return "no listener accepts this address"
+	}
+
+	var reason string
+	switch score {
+	case 2:
+		reason = fmt.Sprintf("bound to exactly %s, which beats any wildcard bind", localAddrHost(winners[0].LocalAddr))
+	case 1:
+		reason = fmt.Sprintf("wildcard bind %s accepts any local address", winners[0].LocalAddr)
+		if strings.HasPrefix(winners[0].LocalAddr, "*:") {
+			reason += " (assumed to cover IPv4 and IPv6, since lsof does not say which)"
+		}
+	default:
+		reason = fmt.Sprintf("dual-stack IPv6 wildcard %s accepts IPv4 traffic unless IPV6_V6ONLY is set", winners[0].LocalAddr)
+	}
+
+	pids := make(map[int]bool)
+	for _, proc := range winners {
+		pids[proc.PID] = true
+	}
+	if len(pids) > 1 {
+		reason += fmt.Sprintf("; %d processes tie, so the kernel spreads connections between them (SO_REUSEPORT)", len(pids))
+	}
+	return reason
+}
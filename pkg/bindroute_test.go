@@ -0,0 +1,139 @@
+package process
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func winnerPIDs(route ListenerRoute) []int {
+	pids := make([]int, len(route.Winners))
+	for i, proc := range route.Winners {
+		pids[i] = proc.PID
+	}
+	return pids
+}
+
+func samePIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestResolveListener(t *testing.T) {
+	listener := func(pid int, addr, protocol string) Process {
+		return Process{PID: pid, Port: 8080, Protocol: protocol, State: "LISTEN", LocalAddr: addr}
+	}
+
+	tests := []struct {
+		name      string
+		listeners []Process
+		dst       string
+		want      []int
+	}{
+		{
+			name:      "specific beats wildcard",
+			listeners: []Process{listener(1, "0.0.0.0:8080", "tcp"), listener(2, "127.0.0.1:8080", "tcp")},
+			dst:       "127.0.0.1",
+			want:      []int{2},
+		},
+		{
+			name:      "wildcard for an unbound address",
+			listeners: []Process{listener(1, "0.0.0.0:8080", "tcp"), listener(2, "127.0.0.1:8080", "tcp")},
+			dst:       "192.168.1.5",
+			want:      []int{1},
+		},
+		{
+			name:      "IPv4 wildcard beats dual-stack IPv6 wildcard",
+			listeners: []Process{listener(1, "[::]:8080", "tcp6"), listener(2, "0.0.0.0:8080", "tcp")},
+			dst:       "10.0.0.1",
+			want:      []int{2},
+		},
+		{
+			name:      "dual-stack IPv6 wildcard accepts IPv4",
+			listeners: []Process{listener(1, "[::]:8080", "tcp6")},
+			dst:       "127.0.0.1",
+			want:      []int{1},
+		},
+		{
+			name:      "IPv4 listeners do not get IPv6 traffic",
+			listeners: []Process{listener(1, "0.0.0.0:8080", "tcp"), listener(2, "127.0.0.1:8080", "tcp")},
+			dst:       "::1",
+			want:      []int{},
+		},
+		{
+			name:      "IPv6 loopback",
+			listeners: []Process{listener(1, "*:8080", "tcp"), listener(2, "[::1]:8080", "tcp6")},
+			dst:       "::1",
+			want:      []int{2},
+		},
+		{
+			name:      "v4-mapped bind matches IPv4",
+			listeners: []Process{listener(1, "*:8080", "tcp"), listener(2, "[::ffff:127.0.0.1]:8080", "tcp6")},
+			dst:       "127.0.0.1",
+			want:      []int{2},
+		},
+		{
+			name:      "SO_REUSEPORT tie",
+			listeners: []Process{listener(1, "*:8080", "tcp"), listener(2, "*:8080", "tcp")},
+			dst:       "127.0.0.1",
+			want:      []int{1, 2},
+		},
+		{
+			name: "other ports, protocols and connections are ignored",
+			listeners: []Process{
+				{PID: 1, Port: 9090, Protocol: "tcp", State: "LISTEN", LocalAddr: "*:9090"},
+				{PID: 2, Port: 8080, Protocol: "udp", LocalAddr: "*:8080"},
+				{PID: 3, Port: 8080, Protocol: "tcp", State: "ESTABLISHED", LocalAddr: "127.0.0.1:8080", RemoteAddr: "127.0.0.1:50000"},
+			},
+			dst:  "127.0.0.1",
+			want: []int{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := ResolveListener(tt.listeners, 8080, "tcp", netip.MustParseAddr(tt.dst))
+			if got := winnerPIDs(route); !samePIDs(got, tt.want) {
+				t.Errorf("winners = %v, want %v (reason %q)", got, tt.want, route.Reason)
+			}
+			if route.Reason == "" {
+				t.Error("Expected a reason")
+			}
+		})
+	}
+}
+
+func TestResolveTraffic(t *testing.T) {
+	processes := []Process{
+		{PID: 1, Port: 8080, Protocol: "tcp", State: "LISTEN", LocalAddr: "0.0.0.0:8080"},
+		{PID: 2, Port: 8080, Protocol: "tcp", State: "LISTEN", LocalAddr: "127.0.0.1:8080"},
+		{PID: 3, Port: 8080, Protocol: "tcp", State: "LISTEN", LocalAddr: "192.168.1.5:8080"},
+	}
+
+	// A loopback client dials its own address
+	routes := ResolveTraffic(processes, 8080, "tcp", netip.MustParseAddr("127.0.0.1"))
+	if len(routes) != 1 || !samePIDs(winnerPIDs(routes[0]), []int{2}) {
+		t.Fatalf("loopback routes = %+v, want PID 2 only", routes)
+	}
+	if len(routes[0].Shadowed) != 2 {
+		t.Errorf("Expected 2 shadowed listeners, got %d", len(routes[0].Shadowed))
+	}
+
+	// A remote client reaches the specific bind or the wildcard, never loopback
+	routes = ResolveTraffic(processes, 8080, "tcp", netip.MustParseAddr("10.1.2.3"))
+	if len(routes) != 2 {
+		t.Fatalf("Expected 2 routes for a remote client, got %+v", routes)
+	}
+	if routes[0].Destination != "192.168.1.5" || !samePIDs(winnerPIDs(routes[0]), []int{3}) {
+		t.Errorf("route 0 = %+v, want 192.168.1.5 to PID 3", routes[0])
+	}
+	if routes[1].Destination != AnyOtherAddress || !samePIDs(winnerPIDs(routes[1]), []int{1}) {
+		t.Errorf("route 1 = %+v, want any other address to PID 1", routes[1])
+	}
+}
@@ -0,0 +1,123 @@
+package process
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Bind scopes describe which interfaces a listener accepts connections on
+const (
+	BindScopeAll      = "all"      // Wildcard address, reachable from any interface
+	BindScopeLoopback = "loopback" // Loopback only, reachable from this host
+	BindScopeSpecific = "specific" // A single non-loopback address
+)
+
+// BindScopes lists the valid bind scope values
+var BindScopes = []string{BindScopeAll, BindScopeLoopback, BindScopeSpecific}
+
+// DetectBindScope classifies a local address such as "*:8080", "0.0.0.0:80",
+// "[::1]:3000" or "192.168.1.5:22". It returns an empty string when the
+// address cannot be classified.
+func DetectBindScope(localAddr string) string {
+	host := localAddrHost(localAddr)
+
+	switch host {
+	case "":
+		return ""
+	case "*":
+		return BindScopeAll
+	case "localhost":
+		return BindScopeLoopback
+	}
+
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return BindScopeSpecific
+	case ip.IsUnspecified():
+		return BindScopeAll
+	case ip.IsLoopback():
+		return BindScopeLoopback
+	default:
+		return BindScopeSpecific
+	}
+}
+
+// localAddrHost returns the host part of a local address such as "*:8080",
+// "[::1]:3000" or "[fe80::1%eth0]:22", without brackets or IPv6 zone
+func localAddrHost(localAddr string) string {
+	host := localAddr
+	if i := strings.LastIndex(localAddr, ":"); i != -1 {
+		host = localAddr[:i]
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+
+	// Strip an IPv6 zone such as "fe80::1%eth0"
+	if i := strings.Index(host, "%"); i != -1 {
+		host = host[:i]
+	}
+	return host
+}
+
+// InterfaceAddrs returns the IP addresses assigned to the named network
+// interface. It fails if there is no such interface or it has no addresses.
+func InterfaceAddrs(name string) ([]net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown network interface %q", name)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("reading addresses of %s: %v", name, err)
+	}
+
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok {
+			ips = append(ips, ipNet.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("network interface %s has no addresses", name)
+	}
+	return ips, nil
+}
+
+// ListensOn reports whether a socket with the given local address is bound to
+// one of addrs. Wildcard binds are reachable on every address of their
+// family: "0.0.0.0" on IPv4 addresses, "::" and lsof's "*" on any.
+func ListensOn(localAddr string, addrs []net.IP) bool {
+	host := localAddrHost(localAddr)
+	if host == "*" {
+		return len(addrs) > 0
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, addr := range addrs {
+		switch {
+		case ip.Equal(net.IPv4zero):
+			if addr.To4() != nil {
+				return true
+			}
+		case ip.IsUnspecified():
+			return true
+		case ip.Equal(addr):
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidBindScope reports whether scope is one of BindScopes
+func IsValidBindScope(scope string) bool {
+	for _, s := range BindScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package process
+
+import (
+	"net"
+	"testing"
+)
+
+func TestDetectBindScope(t *testing.T) {
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"*:8080", BindScopeAll},
+		{"0.0.0.0:80", BindScopeAll},
+		{"[::]:443", BindScopeAll},
+		{":::22", BindScopeAll},
+		{"127.0.0.1:3000", BindScopeLoopback},
+		{"127.0.1.1:53", BindScopeLoopback},
+		{"[::1]:5432", BindScopeLoopback},
+		{"localhost:6379", BindScopeLoopback},
+		{"192.168.1.5:22", BindScopeSpecific},
+		{"[fe80::1%eth0]:8080", BindScopeSpecific},
+		{"myhost:9000", BindScopeSpecific},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := DetectBindScope(tt.addr); got != tt.want {
+			t.Errorf("DetectBindScope(%q) = %q, want %q", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestFilterProcessesByBindScope(t *testing.T) {
+	pm := NewProcessManager()
+	processes := []Process{
+		{PID: 1, Port: 80, BindScope: BindScopeAll},
+		{PID: 2, Port: 3000, BindScope: BindScopeLoopback},
+		{PID: 3, Port: 22, BindScope: BindScopeSpecific},
+	}
+
+	filtered := pm.FilterProcesses(processes, FilterOptions{BindScope: BindScopeAll})
+	if len(filtered) != 1 || filtered[0].PID != 1 {
+		t.Errorf("Expected only PID 1 for bind scope %q, got %v", BindScopeAll, filtered)
+	}
+}
+
+func TestListensOn(t *testing.T) {
+	v4 := []net.IP{net.ParseIP("192.168.1.5")}
+	v6 := []net.IP{net.ParseIP("fe80::1")}
+
+	tests := []struct {
+		addr  string
+		addrs []net.IP
+		want  bool
+	}{
+		{"192.168.1.5:22", v4, true},
+		{"192.168.1.6:22", v4, false},
+		{"127.0.0.1:3000", v4, false},
+		{"*:8080", v4, true},
+		{"0.0.0.0:80", v4, true},
+		{"0.0.0.0:80", v6, false},
+		{"[::]:443", v4, true},
+		{"[fe80::1%eth0]:8080", v6, true},
+		{"[fe80::1%eth0]:8080", v4, false},
+		{"myhost:9000", v4, false},
+		{"*:8080", nil, false},
+	}
+
+	for _, tt := range tests {
+		if got := ListensOn(tt.addr, tt.addrs); got != tt.want {
+			t.Errorf("ListensOn(%q, %v) = %v, want %v", tt.addr, tt.addrs, got, tt.want)
+		}
+	}
+}
+
+func TestFilterProcessesByAddrs(t *testing.T) {
+	pm := NewProcessManager()
+	processes := []Process{
+		{PID: 1, Port: 80, LocalAddr: "*:80"},
+		{PID: 2, Port: 3000, LocalAddr: "127.0.0.1:3000"},
+		{PID: 3, Port: 22, LocalAddr: "10.0.0.2:22"},
+	}
+
+	filtered := pm.FilterProcesses(processes, FilterOptions{Addrs: []net.IP{net.ParseIP("10.0.0.2")}})
+	if len(filtered) != 2 || filtered[0].PID != 1 || filtered[1].PID != 3 {
+		t.Errorf("Expected PIDs 1 and 3 for 10.0.0.2, got %v", filtered)
+	}
+}
@@ -0,0 +1,79 @@
+package process
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// snapshotCache holds the most recent full process enumeration for a limited time.
+// The mutex is held while loading so concurrent callers share one enumeration.
+type snapshotCache struct {
+	mu        sync.Mutex
+	ttl       time.Duration
+	processes []Process
+	fetched   time.Time
+	hits      uint64
+	misses    uint64
+}
+
+// get returns a copy of the cached snapshot, calling load when it is missing or expired
+func (c *snapshotCache) get(ctx context.Context, load func(context.Context) ([]Process, error)) ([]Process, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.fetched.IsZero() && time.Since(c.fetched) < c.ttl {
+		c.hits++
+		slog.Debug("process cache hit", "age", time.Since(c.fetched), "ttl", c.ttl, "hits", c.hits, "misses", c.misses)
+		return copyProcesses(c.processes), nil
+	}
+
+	c.misses++
+	slog.Debug("process cache miss", "ttl", c.ttl, "hits", c.hits, "misses", c.misses)
+
+	processes, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.processes = processes
+	c.fetched = time.Now()
+
+	return copyProcesses(processes), nil
+}
+
+// invalidate drops the cached snapshot so the next call re-enumerates
+func (c *snapshotCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.processes = nil
+	c.fetched = time.Time{}
+}
+
+// setTTL changes how long snapshots are reused, including the current one
+func (c *snapshotCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+func (c *snapshotCache) getTTL() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttl
+}
+
+// stats returns the number of cache hits and misses so far
+func (c *snapshotCache) stats() (hits, misses uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// copyProcesses protects the cached slice from callers that sort or filter in place
+func copyProcesses(processes []Process) []Process {
+	if processes == nil {
+		return nil
+	}
+	return append([]Process(nil), processes...)
+}
@@ -0,0 +1,179 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSnapshotCache(t *testing.T) {
+	cache := &snapshotCache{ttl: time.Minute}
+	loads := 0
+	load := func(context.Context) ([]Process, error) {
+		loads++
+		return []Process{{PID: 1, Port: 80}, {PID: 2, Port: 443}}, nil
+	}
+
+	first, err := cache.get(context.Background(), load)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Mutating the returned slice must not affect the cache
+	first[0].Port = 9999
+
+	second, _ := cache.get(context.Background(), load)
+	if loads != 1 {
+		t.Errorf("Expected 1 load within the TTL, got %d", loads)
+	}
+	if second[0].Port != 80 {
+		t.Errorf("Cached snapshot was modified by a caller: %+v", second[0])
+	}
+
+	if hits, misses := cache.stats(); hits != 1 || misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d hits and %d misses", hits, misses)
+	}
+
+	cache.invalidate()
+	_, _ = cache.get(context.Background(), load)
+	if loads != 2 {
+		t.Errorf("Expected a reload after invalidation, got %d loads", loads)
+	}
+}
+
+func TestSnapshotCacheExpiry(t *testing.T) {
+	cache := &snapshotCache{ttl: 10 * time.Millisecond}
+	loads := 0
+	load := func(context.Context) ([]Process, error) {
+		loads++
+		return nil, nil
+	}
+
+	_, _ = cache.get(context.Background(), load)
+	time.Sleep(20 * time.Millisecond)
+	_, _ = cache.get(context.Background(), load)
+
+	if loads != 2 {
+		t.Errorf("Expected the snapshot to expire after the TTL, got %d loads", loads)
+	}
+}
+
+func TestSnapshotCacheErrorNotCached(t *testing.T) {
+	cache := &snapshotCache{ttl: time.Minute}
+	fail := true
+	load := func(context.Context) ([]Process, error) {
+		if fail {
+			return nil, errors.New("boom")
+		}
+		return []Process{{PID: 1}}, nil
+	}
+
+	if _, err := cache.get(context.Background(), load); err == nil {
+		t.Fatal("Expected the load error to be returned")
+	}
+
+	fail = false
+	processes, err := cache.get(context.Background(), load)
+	if err != nil || len(processes) != 1 {
+		t.Errorf("Expected a successful reload after an error, got %v, %v", processes, err)
+	}
+}
+
+func TestSnapshotCacheConcurrent(t *testing.T) {
+	cache := &snapshotCache{ttl: time.Minute}
+	var mu sync.Mutex
+	loads := 0
+	load := func(context.Context) ([]Process, error) {
+		mu.Lock()
+		loads++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		return []Process{{PID: 1}}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _ = cache.get(context.Background(), load)
+		}()
+	}
+	wg.Wait()
+
+	if loads != 1 {
+		t.Errorf("Expected concurrent callers to share one load, got %d", loads)
+	}
+}
+
+func TestProcessManagerReconfigureCache(t *testing.T) {
+	pm := NewProcessManager().WithCache(time.Minute)
+	cache := pm.cache.Load()
+	if _, err := cache.get(context.Background(), func(context.Context) ([]Process, error) {
+		return []Process{{PID: 1}}, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new ttl applies to the existing cache without dropping the snapshot
+	pm.WithCache(5 * time.Second)
+	if pm.cache.Load() != cache || pm.CacheTTL() != 5*time.Second {
+		t.Errorf("WithCache replaced the cache or ignored the ttl: ttl %s", pm.CacheTTL())
+	}
+	if cache.fetched.IsZero() {
+		t.Error("Changing the ttl dropped the cached snapshot")
+	}
+
+	// Toggling metrics invalidates the snapshot, which was taken with the old setting
+	pm.WithMetrics(true)
+	if cache.fetched.IsZero() {
+		t.Error("Leaving metrics unchanged dropped the cached snapshot")
+	}
+	pm.WithMetrics(false)
+	if !cache.fetched.IsZero() || pm.MetricsEnabled() {
+		t.Error("Disabling metrics kept the cached snapshot")
+	}
+
+	_, _ = cache.get(context.Background(), func(context.Context) ([]Process, error) {
+		return []Process{{PID: 1}}, nil
+	})
+	pm.InvalidateCache()
+	if !cache.fetched.IsZero() {
+		t.Error("InvalidateCache kept the cached snapshot")
+	}
+
+	pm.WithCache(0)
+	if pm.CacheTTL() != 0 {
+		t.Errorf("CacheTTL() = %s after disabling the cache, want 0", pm.CacheTTL())
+	}
+	pm.InvalidateCache() // no cache: nothing to drop
+}
+
+func TestProcessManagerReconfigureConcurrently(t *testing.T) {
+	pm := NewProcessManager().WithCache(time.Millisecond)
+	load := func(context.Context) ([]Process, error) { return []Process{{PID: 1}}, nil }
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if cache := pm.cache.Load(); cache != nil {
+					_, _ = cache.get(context.Background(), load)
+				}
+				_ = pm.MetricsEnabled()
+				_, _ = pm.CacheStats()
+			}
+		}()
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				pm.WithCache(time.Duration(j%3) * time.Millisecond).WithMetrics(j%2 == i%2)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,98 @@
+package process
+
+import (
+	"sort"
+	"strings"
+)
+
+// Kinds of port conflict, from most to least suspicious
+const (
+	// ConflictMultiplePIDs means different processes listen on the same port,
+	// e.g. via SO_REUSEPORT or separate IPv4 and IPv6 sockets, which often
+	// points to a stale server or one process stealing another's traffic
+	ConflictMultiplePIDs = "multiple-pids"
+	// ConflictMultipleListeners means one process has several listening
+	// sockets on the port, typically one for IPv4 and one for IPv6
+	ConflictMultipleListeners = "multiple-listeners"
+)
+
+// PortConflict is a port with more than one listening socket
+type PortConflict struct {
+	Port      int       `json:"port"`
+	Protocol  string    `json:"protocol"`
+	Kind      string    `json:"kind"` // ConflictMultiplePIDs or ConflictMultipleListeners
+	PIDs      []int     `json:"pids"`
+	Listeners []Process `json:"listeners"`
+}
+
+// FindPortConflicts groups listening sockets by port and protocol and returns
+// the ports held by more than one listener. Each entry counts as a socket, so
+// a dual-stack listener shows up even where lsof prints both as "*:port".
+// Established connections are ignored. Conflicts between processes come
+// first, then by port.
+func FindPortConflicts(processes []Process) []PortConflict {
+	type portKey struct {
+		port     int
+		protocol string
+	}
+	groups := make(map[portKey][]Process)
+	for _, proc := range processes {
+		if !isListener(proc) {
+			continue
+		}
+		// netstat reports IPv6 sockets as tcp6/udp6; they share the port with IPv4
+		k := portKey{proc.Port, strings.TrimSuffix(proc.Protocol, "6")}
+		groups[k] = append(groups[k], proc)
+	}
+
+	var conflicts []PortConflict
+	for k, listeners := range groups {
+		if len(listeners) < 2 {
+			continue
+		}
+
+		var pids []int
+		pidSeen := make(map[int]bool)
+		for _, proc := range listeners {
+			if !pidSeen[proc.PID] {
+				pidSeen[proc.PID] = true
+				pids = append(pids, proc.PID)
+			}
+		}
+		sort.Ints(pids)
+		sortByPortPID(listeners)
+
+		kind := ConflictMultipleListeners
+		if len(pids) > 1 {
+			kind = ConflictMultiplePIDs
+		}
+		conflicts = append(conflicts, PortConflict{
+			Port:      k.port,
+			Protocol:  k.protocol,
+			Kind:      kind,
+			PIDs:      pids,
+			Listeners: listeners,
+		})
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		a, b := conflicts[i], conflicts[j]
+		if a.Kind != b.Kind {
+			return a.Kind == ConflictMultiplePIDs
+		}
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.Protocol < b.Protocol
+	})
+	return conflicts
+}
+
+// isListener reports whether proc is a listening (or unconnected UDP) socket
+// rather than one end of an established connection
+func isListener(proc Process) bool {
+	if remoteIP(proc.RemoteAddr) != "" {
+		return false
+	}
+	return proc.State == "" || proc.State == "LISTEN" || strings.HasPrefix(proc.Protocol, "udp")
+}
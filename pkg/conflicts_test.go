@@ -0,0 +1,58 @@
+package process
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindPortConflicts(t *testing.T) {
+	processes := []Process{
+		// Dual-stack listener owned by one process
+		{PID: 10, Port: 8080, Protocol: "tcp", State: "LISTEN", LocalAddr: "0.0.0.0:8080"},
+		{PID: 10, Port: 8080, Protocol: "tcp6", State: "LISTEN", LocalAddr: "[::]:8080"},
+		// Two processes sharing a port
+		{PID: 21, Port: 3000, Protocol: "tcp", State: "LISTEN", LocalAddr: "127.0.0.1:3000"},
+		{PID: 20, Port: 3000, Protocol: "tcp", State: "LISTEN", LocalAddr: "*:3000"},
+		// A lone listener with an accepted connection, which lsof also marks LISTEN
+		{PID: 30, Port: 5432, Protocol: "tcp", State: "LISTEN", LocalAddr: "*:5432"},
+		{PID: 30, Port: 5432, Protocol: "tcp", State: "LISTEN", LocalAddr: "127.0.0.1:5432", RemoteAddr: "127.0.0.1:50001"},
+		{PID: 31, Port: 5432, Protocol: "tcp", State: "ESTABLISHED", LocalAddr: "127.0.0.1:5432", RemoteAddr: "127.0.0.1:50000"},
+		// The same port number over TCP and UDP is not a conflict
+		{PID: 40, Port: 53, Protocol: "tcp", State: "LISTEN", LocalAddr: "*:53"},
+		{PID: 41, Port: 53, Protocol: "udp", LocalAddr: "*:53"},
+	}
+
+	conflicts := FindPortConflicts(processes)
+	if len(conflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts, got %d: %+v", len(conflicts), conflicts)
+	}
+
+	first := conflicts[0]
+	if first.Port != 3000 || first.Kind != ConflictMultiplePIDs {
+		t.Errorf("Expected the multi-PID conflict on 3000 first, got port %d (%s)", first.Port, first.Kind)
+	}
+	if !reflect.DeepEqual(first.PIDs, []int{20, 21}) {
+		t.Errorf("PIDs = %v, want [20 21]", first.PIDs)
+	}
+	if first.Listeners[0].PID != 20 {
+		t.Errorf("Expected listeners sorted by PID, got %+v", first.Listeners)
+	}
+
+	second := conflicts[1]
+	if second.Port != 8080 || second.Kind != ConflictMultipleListeners || second.Protocol != "tcp" {
+		t.Errorf("Expected a tcp multiple-listeners conflict on 8080, got %+v", second)
+	}
+	if len(second.Listeners) != 2 || !reflect.DeepEqual(second.PIDs, []int{10}) {
+		t.Errorf("Expected two listeners from PID 10, got %+v", second)
+	}
+}
+
+func TestFindPortConflictsNone(t *testing.T) {
+	processes := []Process{
+		{PID: 1, Port: 80, Protocol: "tcp", State: "LISTEN", LocalAddr: "*:80"},
+		{PID: 2, Port: 443, Protocol: "tcp", State: "LISTEN", LocalAddr: "*:443"},
+	}
+	if conflicts := FindPortConflicts(processes); len(conflicts) != 0 {
+		t.Errorf("Expected no conflicts, got %+v", conflicts)
+	}
+}
@@ -40,7 +40,11 @@ var ServiceMap = map[int]string{
 }
 
 // GetServiceName returns the common service name for a port, or "Unknown" if not found.
+// Custom service definitions take precedence over ServiceMap.
 func GetServiceName(port int) string {
+	if name, ok := customServiceName(port, ""); ok {
+		return name
+	}
 	if name, ok := ServiceMap[port]; ok {
 		return name
 	}
@@ -0,0 +1,131 @@
+package process
+
+import (
+	"sort"
+)
+
+// ProcessChange is a listener present in both snapshots whose details differ
+type ProcessChange struct {
+	Old    Process  `json:"old"`
+	New    Process  `json:"new"`
+	Fields []string `json:"fields"` // Differing fields, by JSON name (e.g. "pid", "command")
+}
+
+// ChangeSet describes the differences between two process snapshots
+type ChangeSet struct {
+	Added   []Process       `json:"added"`
+	Removed []Process       `json:"removed"`
+	Changed []ProcessChange `json:"changed"`
+}
+
+// Empty reports whether the snapshots were equivalent
+func (c ChangeSet) Empty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// DiffSnapshots compares two process snapshots. Listeners are matched by PID
+// and port; a match whose command, user or start time differs is reported as
+// changed (a different start time means the PID was reused). Unmatched
+// listeners left on the same port on both sides are paired as a "pid" change,
+// e.g. a server restarted under a new PID. Everything else is added or
+// removed. Results are ordered by port, then PID.
+func DiffSnapshots(old, new []Process) ChangeSet {
+	type key struct{ pid, port int }
+
+	oldByKey := make(map[key]Process, len(old))
+	for _, proc := range old {
+		k := key{proc.PID, proc.Port}
+		if _, dup := oldByKey[k]; !dup {
+			oldByKey[k] = proc
+		}
+	}
+	newByKey := make(map[key]Process, len(new))
+	for _, proc := range new {
+		k := key{proc.PID, proc.Port}
+		if _, dup := newByKey[k]; !dup {
+			newByKey[k] = proc
+		}
+	}
+
+	var changes ChangeSet
+	var added, removed []Process
+
+	for k, n := range newByKey {
+		o, ok := oldByKey[k]
+		if !ok {
+			added = append(added, n)
+			continue
+		}
+		if fields := changedFields(o, n); len(fields) > 0 {
+			changes.Changed = append(changes.Changed, ProcessChange{Old: o, New: n, Fields: fields})
+		}
+	}
+	for k, o := range oldByKey {
+		if _, ok := newByKey[k]; !ok {
+			removed = append(removed, o)
+		}
+	}
+	sortByPortPID(added)
+	sortByPortPID(removed)
+
+	// Pair leftovers on the same port: the listener survived under a new PID
+	removedByPort := make(map[int][]Process)
+	for _, o := range removed {
+		removedByPort[o.Port] = append(removedByPort[o.Port], o)
+	}
+	for _, n := range added {
+		if candidates := removedByPort[n.Port]; len(candidates) > 0 {
+			o := candidates[0]
+			removedByPort[n.Port] = candidates[1:]
+			changes.Changed = append(changes.Changed, ProcessChange{
+				Old:    o,
+				New:    n,
+				Fields: append([]string{"pid"}, changedFields(o, n)...),
+			})
+			continue
+		}
+		changes.Added = append(changes.Added, n)
+	}
+	for _, o := range removed {
+		if remaining := removedByPort[o.Port]; len(remaining) > 0 && remaining[0].PID == o.PID {
+			changes.Removed = append(changes.Removed, o)
+			removedByPort[o.Port] = remaining[1:]
+		}
+	}
+
+	sort.Slice(changes.Changed, func(i, j int) bool {
+		a, b := changes.Changed[i].New, changes.Changed[j].New
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		return a.PID < b.PID
+	})
+
+	return changes
+}
+
+// changedFields lists the identity fields that differ between two snapshots
+// of the same listener. Start times are only compared for the same PID, where
+// a difference means the PID was reused.
+func changedFields(o, n Process) []string {
+	var fields []string
+	if o.Command != n.Command {
+		fields = append(fields, "command")
+	}
+	if o.User != n.User && o.User != "" && n.User != "" {
+		fields = append(fields, "user")
+	}
+	if o.PID == n.PID && !o.StartTime.Equal(n.StartTime) && !o.StartTime.IsZero() && !n.StartTime.IsZero() {
+		fields = append(fields, "start_time")
+	}
+	return fields
+}
+
+func sortByPortPID(processes []Process) {
+	sort.Slice(processes, func(i, j int) bool {
+		if processes[i].Port != processes[j].Port {
+			return processes[i].Port < processes[j].Port
+		}
+		return processes[i].PID < processes[j].PID
+	})
+}
@@ -0,0 +1,95 @@
+package process
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshotsAddedRemoved(t *testing.T) {
+	old := []Process{
+		{PID: 10, Port: 3000, Command: "node"},
+		{PID: 20, Port: 5432, Command: "postgres"},
+	}
+	new := []Process{
+		{PID: 10, Port: 3000, Command: "node"},
+		{PID: 30, Port: 8080, Command: "java"},
+	}
+
+	changes := DiffSnapshots(old, new)
+	if len(changes.Added) != 1 || changes.Added[0].PID != 30 {
+		t.Errorf("Expected PID 30 added, got %+v", changes.Added)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0].PID != 20 {
+		t.Errorf("Expected PID 20 removed, got %+v", changes.Removed)
+	}
+	if len(changes.Changed) != 0 {
+		t.Errorf("Expected no changes, got %+v", changes.Changed)
+	}
+}
+
+func TestDiffSnapshotsUnchanged(t *testing.T) {
+	snapshot := []Process{
+		{PID: 10, Port: 3000, Command: "node", CPUPercent: 1},
+		{PID: 10, Port: 3000, Command: "node", LocalAddr: "[::]:3000"},
+	}
+	updated := []Process{{PID: 10, Port: 3000, Command: "node", CPUPercent: 50}}
+
+	if changes := DiffSnapshots(snapshot, updated); !changes.Empty() {
+		t.Errorf("Expected no changes for metric-only differences, got %+v", changes)
+	}
+	if changes := DiffSnapshots(nil, nil); !changes.Empty() {
+		t.Errorf("Expected empty change set, got %+v", changes)
+	}
+}
+
+func TestDiffSnapshotsPIDChangedOnSamePort(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	old := []Process{{PID: 10, Port: 3000, Command: "node", StartTime: start}}
+	new := []Process{{PID: 11, Port: 3000, Command: "node", StartTime: start.Add(time.Minute)}}
+
+	changes := DiffSnapshots(old, new)
+	if len(changes.Added) != 0 || len(changes.Removed) != 0 {
+		t.Fatalf("Expected a restart to be a change, got added %+v removed %+v", changes.Added, changes.Removed)
+	}
+	if len(changes.Changed) != 1 {
+		t.Fatalf("Expected 1 change, got %d", len(changes.Changed))
+	}
+	change := changes.Changed[0]
+	if change.Old.PID != 10 || change.New.PID != 11 {
+		t.Errorf("Expected PID 10 -> 11, got %d -> %d", change.Old.PID, change.New.PID)
+	}
+	if !reflect.DeepEqual(change.Fields, []string{"pid"}) {
+		t.Errorf("Expected fields [pid], got %v", change.Fields)
+	}
+}
+
+func TestDiffSnapshotsPIDReused(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	old := []Process{{PID: 10, Port: 3000, Command: "node", User: "dev", StartTime: start}}
+	new := []Process{{PID: 10, Port: 3000, Command: "python3", User: "dev", StartTime: start.Add(time.Hour)}}
+
+	changes := DiffSnapshots(old, new)
+	if len(changes.Changed) != 1 {
+		t.Fatalf("Expected 1 change, got %+v", changes)
+	}
+	if want := []string{"command", "start_time"}; !reflect.DeepEqual(changes.Changed[0].Fields, want) {
+		t.Errorf("Expected fields %v, got %v", want, changes.Changed[0].Fields)
+	}
+}
+
+func TestDiffSnapshotsUnpairedOnSamePort(t *testing.T) {
+	old := []Process{
+		{PID: 10, Port: 3000, Command: "node"},
+		{PID: 11, Port: 3000, Command: "node"},
+	}
+	new := []Process{{PID: 12, Port: 3000, Command: "node"}}
+
+	changes := DiffSnapshots(old, new)
+	if len(changes.Changed) != 1 || changes.Changed[0].Old.PID != 10 || changes.Changed[0].New.PID != 12 {
+		t.Errorf("Expected PID 10 -> 12 change, got %+v", changes.Changed)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0].PID != 11 {
+		t.Errorf("Expected PID 11 removed, got %+v", changes.Removed)
+	}
+}
@@ -0,0 +1,76 @@
+package process
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestParseEnviron(t *testing.T) {
+	vars := parseEnviron([]string{"PATH=/usr/bin", "EMPTY=", "", "A=b=c", "NOVALUE"})
+	want := []EnvVar{
+		{Name: "A", Value: "b=c"},
+		{Name: "EMPTY", Value: ""},
+		{Name: "NOVALUE", Value: ""},
+		{Name: "PATH", Value: "/usr/bin"},
+	}
+	if !reflect.DeepEqual(vars, want) {
+		t.Errorf("parseEnviron() = %+v, want %+v", vars, want)
+	}
+}
+
+func TestMaskSecrets(t *testing.T) {
+	vars := []EnvVar{
+		{Name: "API_KEY", Value: "abc123"},
+		{Name: "DB_PASSWORD", Value: "hunter2"},
+		{Name: "GITHUB_TOKEN", Value: "ghp_x"},
+		{Name: "aws_secret_access_key", Value: "s3cr3t"},
+		{Name: "DATABASE_URL", Value: "postgres://app:hunter2@db:5432/app"},
+		{Name: "REDIS_URL", Value: "redis://db:6379"},
+		{Name: "HOME", Value: "/home/dev"},
+		{Name: "EMPTY_TOKEN", Value: ""},
+	}
+
+	masked := MaskSecrets(vars)
+	want := map[string]string{
+		"API_KEY":               maskedValue,
+		"DB_PASSWORD":           maskedValue,
+		"GITHUB_TOKEN":          maskedValue,
+		"aws_secret_access_key": maskedValue,
+		"DATABASE_URL":          "postgres://app:" + maskedValue + "@db:5432/app",
+		"REDIS_URL":             "redis://db:6379",
+		"HOME":                  "/home/dev",
+		"EMPTY_TOKEN":           "",
+	}
+	for _, v := range masked {
+		if v.Value != want[v.Name] {
+			t.Errorf("%s: got %q, want %q", v.Name, v.Value, want[v.Name])
+		}
+	}
+	if vars[0].Value != "abc123" {
+		t.Error("MaskSecrets modified its input")
+	}
+}
+
+func TestGetEnvironSelf(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("environment reading is only exercised on Linux")
+	}
+	// /proc/<pid>/environ holds the environment the process started with,
+	// so later os.Setenv calls are not visible here
+	vars, err := NewProcessManager().GetEnviron(context.Background(), os.Getpid())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(vars) == 0 && len(os.Environ()) > 1 {
+		t.Error("Expected the test process environment to be non-empty")
+	}
+}
+
+func TestGetEnvironInvalidPID(t *testing.T) {
+	if _, err := NewProcessManager().GetEnviron(context.Background(), -1); err == nil {
+		t.Error("Expected error for invalid PID")
+	}
+}
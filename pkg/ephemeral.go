@@ -0,0 +1,104 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// PortRange is an inclusive range of ports
+type PortRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Contains reports whether port lies within the range
+func (r PortRange) Contains(port int) bool {
+	return port >= r.Start && port <= r.End
+}
+
+// String formats the range as "start-end"
+func (r PortRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// DefaultEphemeralRange is the IANA dynamic port range, assumed when the
+// operating system's range cannot be read
+var DefaultEphemeralRange = PortRange{Start: 49152, End: 65535}
+
+// linuxPortRangeFile holds the local port range Linux assigns to outbound connections
+const linuxPortRangeFile = "/proc/sys/net/ipv4/ip_local_port_range"
+
+// EphemeralPortRange returns the range the operating system picks local
+// ports from for outbound connections. A server started on one of these ports
+// can randomly find it taken by a client connection.
+func EphemeralPortRange(ctx context.Context) (PortRange, error) {
+	switch runtime.GOOS {
+	case "linux":
+		data, err := os.ReadFile(linuxPortRangeFile)
+		if err != nil {
+			return PortRange{}, err
+		}
+		return parsePortRangeFields(string(data))
+	case "darwin":
+		out, err := exec.CommandContext(ctx, "sysctl", "-n",
+			"net.inet.ip.portrange.first", "net.inet.ip.portrange.last").Output()
+		if err != nil {
+			return PortRange{}, fmt.Errorf("sysctl failed: %v", err)
+		}
+		return parsePortRangeFields(string(out))
+	case "windows":
+		out, err := exec.CommandContext(ctx, "netsh", "int", "ipv4", "show", "dynamicport", "tcp").Output()
+		if err != nil {
+			return PortRange{}, fmt.Errorf("netsh failed: %v", err)
+		}
+		return parseNetshDynamicPorts(string(out))
+	default:
+		return PortRange{}, fmt.Errorf("unsupported operating system: %s", runtime.GOOS)
+	}
+}
+
+// parsePortRangeFields parses two whitespace-separated ports, as found in
+// ip_local_port_range or printed by sysctl for two keys
+func parsePortRangeFields(s string) (PortRange, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return PortRange{}, fmt.Errorf("unexpected port range format: %q", s)
+	}
+	start, err1 := strconv.Atoi(fields[0])
+	end, err2 := strconv.Atoi(fields[1])
+	if err1 != nil || err2 != nil || start < MinPort || end > MaxPort || start > end {
+		return PortRange{}, fmt.Errorf("invalid port range: %q", s)
+	}
+	return PortRange{Start: start, End: end}, nil
+}
+
+// parseNetshDynamicPorts parses the "Start Port" and "Number of Ports" lines
+// of "netsh int ipv4 show dynamicport tcp"
+func parseNetshDynamicPorts(s string) (PortRange, error) {
+	start, count := -1, -1
+	for _, line := range strings.Split(s, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "start port":
+			start = n
+		case "number of ports":
+			count = n
+		}
+	}
+	if start < MinPort || count < 1 || start+count-1 > MaxPort {
+		return PortRange{}, fmt.Errorf("unexpected netsh output: %q", s)
+	}
+	return PortRange{Start: start, End: start + count - 1}, nil
+}
@@ -0,0 +1,81 @@
+package process
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParsePortRangeFields(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    PortRange
+		wantErr bool
+	}{
+		{"32768\t60999\n", PortRange{32768, 60999}, false},
+		{"49152\n65535\n", PortRange{49152, 65535}, false},
+		{"1024 1024", PortRange{1024, 1024}, false},
+		{"60999 32768", PortRange{}, true},
+		{"0 1000", PortRange{}, true},
+		{"32768", PortRange{}, true},
+		{"a b", PortRange{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parsePortRangeFields(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePortRangeFields(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parsePortRangeFields(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseNetshDynamicPorts(t *testing.T) {
+	output := `
+Protocol tcp Dynamic Port Range
+---------------------------------
+Start Port      : 49152
+Number of Ports : 16384
+`
+	got, err := parseNetshDynamicPorts(output)
+	if err != nil {
+		t.Fatalf("parseNetshDynamicPorts failed: %v", err)
+	}
+	if want := (PortRange{49152, 65535}); got != want {
+		t.Errorf("parseNetshDynamicPorts = %v, want %v", got, want)
+	}
+
+	if _, err := parseNetshDynamicPorts("The requested operation requires elevation."); err == nil {
+		t.Error("Expected an error for unrelated output")
+	}
+}
+
+func TestFindAvailablePortsSkipsEphemeralRange(t *testing.T) {
+	ctx := context.Background()
+	ephemeral, err := EphemeralPortRange(ctx)
+	if err != nil {
+		ephemeral = DefaultEphemeralRange
+	}
+	start, end := ephemeral.Start, ephemeral.Start+9
+
+	pm := NewProcessManager().WithMetrics(false)
+	available, err := pm.FindAvailablePorts(ctx, start, end, 5)
+	if err != nil {
+		t.Fatalf("FindAvailablePorts failed: %v", err)
+	}
+	if len(available) != 0 {
+		t.Errorf("Expected ephemeral ports %d-%d to be skipped, got %v", start, end, available)
+	}
+	if _, err := pm.FindAvailableBlock(ctx, start, end, 2); err == nil {
+		t.Error("Expected no block inside the ephemeral range")
+	}
+
+	available, err = pm.WithEphemeral(true).FindAvailablePorts(ctx, start, end, 5)
+	if err != nil {
+		t.Fatalf("FindAvailablePorts failed: %v", err)
+	}
+	if len(available) == 0 {
+		t.Errorf("Expected free ports in %d-%d with WithEphemeral(true)", start, end)
+	}
+}
@@ -0,0 +1,40 @@
+package process
+
+import (
+	"context"
+	"math"
+	"runtime"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// FDLimitWarnRatio is the share of its soft RLIMIT_NOFILE a process may have
+// open before NearFDLimit flags it
+const FDLimitWarnRatio = 0.8
+
+// openFDs returns how many file descriptors p has open and its soft limit,
+// either 0 if unknown. Only Unix is supported: on Windows gopsutil counts
+// handles, which are not comparable, and other users' processes are usually
+// unreadable without root.
+func openFDs(ctx context.Context, p *process.Process) (open int, limit uint64) {
+	if runtime.GOOS == "windows" {
+		return 0, 0
+	}
+	if n, err := p.NumFDsWithContext(ctx); err == nil {
+		open = int(n)
+	}
+	if rlimits, err := p.RlimitWithContext(ctx); err == nil {
+		for _, rlimit := range rlimits {
+			if rlimit.Resource == process.RLIMIT_NOFILE && rlimit.Soft != math.MaxUint64 {
+				limit = rlimit.Soft
+			}
+		}
+	}
+	return open, limit
+}
+
+// NearFDLimit reports whether proc has at least FDLimitWarnRatio of its soft
+// file descriptor limit open, a common sign of a descriptor leak
+func NearFDLimit(proc Process) bool {
+	return proc.FDLimit > 0 && float64(proc.OpenFDs) >= FDLimitWarnRatio*float64(proc.FDLimit)
+}
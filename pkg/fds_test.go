@@ -0,0 +1,67 @@
+package process
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestNearFDLimit(t *testing.T) {
+	tests := []struct {
+		open  int
+		limit uint64
+		want  bool
+	}{
+		{10, 1024, false},
+		{819, 1024, false},
+		{820, 1024, true},
+		{1024, 1024, true},
+		{5000, 0, false}, // Unknown or unlimited
+	}
+
+	for _, tt := range tests {
+		proc := Process{OpenFDs: tt.open, FDLimit: tt.limit}
+		if got := NearFDLimit(proc); got != tt.want {
+			t.Errorf("NearFDLimit(%d/%d) = %v, want %v", tt.open, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestFilterProcessesByFDLimit(t *testing.T) {
+	pm := NewProcessManager()
+	processes := []Process{
+		{PID: 1, Port: 80, OpenFDs: 12},
+		{PID: 2, Port: 8080, OpenFDs: 900},
+		{PID: 3, Port: 9000}, // Not readable
+	}
+
+	filtered := pm.FilterProcesses(processes, FilterOptions{FDLimit: 100})
+	if len(filtered) != 1 || filtered[0].PID != 2 {
+		t.Errorf("Expected only PID 2 above 100 open descriptors, got %+v", filtered)
+	}
+}
+
+func TestEnhanceProcessCountsFDs(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("descriptor counts are read from /proc")
+	}
+
+	// Hold a few extra descriptors open so the count is clearly non-zero
+	for i := 0; i < 3; i++ {
+		f, err := os.Open(os.DevNull)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = f.Close() }()
+	}
+
+	proc := Process{PID: os.Getpid(), Port: 1}
+	NewProcessManager().enhanceProcess(context.Background(), &proc)
+	if proc.OpenFDs < 3 {
+		t.Errorf("Expected at least 3 open descriptors, got %d", proc.OpenFDs)
+	}
+	if proc.FDLimit != 0 && uint64(proc.OpenFDs) > proc.FDLimit {
+		t.Errorf("Open descriptors %d exceed the soft limit %d", proc.OpenFDs, proc.FDLimit)
+	}
+}
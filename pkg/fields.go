@@ -0,0 +1,75 @@
+package process
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// processFieldIndex maps Process JSON field names to their struct field index
+var processFieldIndex = buildFieldIndex(reflect.TypeOf(Process{}))
+
+func buildFieldIndex(t reflect.Type) map[string]int {
+	index := make(map[string]int)
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			index[name] = i
+		}
+	}
+	return index
+}
+
+// ProcessFieldNames returns the names of all selectable Process fields, sorted
+func ProcessFieldNames() []string {
+	names := make([]string, 0, len(processFieldIndex))
+	for name := range processFieldIndex {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SelectFields splits requested field names into known Process fields and
+// unrecognised names. Entries may themselves be comma-separated lists.
+// Names are matched case-insensitively and duplicates are dropped.
+func SelectFields(requested []string) (valid []string, ignored []string) {
+	seen := make(map[string]bool)
+
+	for _, entry := range requested {
+		for _, name := range strings.Split(entry, ",") {
+			name = strings.ToLower(strings.TrimSpace(name))
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			if _, ok := processFieldIndex[name]; ok {
+				valid = append(valid, name)
+			} else {
+				ignored = append(ignored, name)
+			}
+		}
+	}
+
+	return valid, ignored
+}
+
+// ProjectProcesses returns each process as a map holding only the given fields.
+// Unknown field names are skipped; use SelectFields to report them.
+func ProjectProcesses(processes []Process, fields []string) []map[string]interface{} {
+	projected := make([]map[string]interface{}, len(processes))
+
+	for i, proc := range processes {
+		value := reflect.ValueOf(proc)
+		entry := make(map[string]interface{}, len(fields))
+		for _, name := range fields {
+			if idx, ok := processFieldIndex[name]; ok {
+				entry[name] = value.Field(idx).Interface()
+			}
+		}
+		projected[i] = entry
+	}
+
+	return projected
+}
@@ -0,0 +1,46 @@
+package process
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectFields(t *testing.T) {
+	valid, ignored := SelectFields([]string{"port, Command", "bogus", "port", " pid "})
+
+	if !reflect.DeepEqual(valid, []string{"port", "command", "pid"}) {
+		t.Errorf("Unexpected valid fields: %v", valid)
+	}
+	if !reflect.DeepEqual(ignored, []string{"bogus"}) {
+		t.Errorf("Unexpected ignored fields: %v", ignored)
+	}
+}
+
+func TestProjectProcesses(t *testing.T) {
+	processes := []Process{
+		{PID: 42, Port: 8080, Command: "node", User: "alice"},
+	}
+
+	projected := ProjectProcesses(processes, []string{"port", "command", "unknown"})
+	if len(projected) != 1 {
+		t.Fatalf("Expected 1 projected process, got %d", len(projected))
+	}
+
+	entry := projected[0]
+	if len(entry) != 2 {
+		t.Errorf("Expected only 2 fields, got %v", entry)
+	}
+	if entry["port"] != 8080 || entry["command"] != "node" {
+		t.Errorf("Unexpected projected values: %v", entry)
+	}
+	if _, ok := entry["user"]; ok {
+		t.Error("Unselected field should not be present")
+	}
+}
+
+func TestProcessFieldNames(t *testing.T) {
+	names := ProcessFieldNames()
+	if len(names) != reflect.TypeOf(Process{}).NumField() {
+		t.Errorf("Expected every Process field to be selectable, got %v", names)
+	}
+}
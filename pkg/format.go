@@ -0,0 +1,149 @@
+package process
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// FormatMemory formats a size in megabytes using the most readable unit (KB, MB or GB)
+func FormatMemory(mb float64) string {
+	switch {
+	case mb <= 0:
+		return "0 KB"
+	case mb < 1:
+		return fmt.Sprintf("%.0f KB", mb*1024)
+	case mb < 1024:
+		return fmt.Sprintf("%.1f MB", mb)
+	default:
+		return fmt.Sprintf("%.2f GB", mb/1024)
+	}
+}
+
+// FormatBytes formats a byte count using binary units (B, KB, MB, GB, TB)
+func FormatBytes(b uint64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%d B", b)
+	}
+
+	value := float64(b)
+	suffixes := []string{"KB", "MB", "GB", "TB"}
+	i := -1
+	for value >= unit && i < len(suffixes)-1 {
+		value /= unit
+		i++
+	}
+	return fmt.Sprintf("%.1f %s", value, suffixes[i])
+}
+
+// FormatUptime formats a duration compactly using its two most significant
+// units, e.g. "3d4h", "2h15m", "5m30s" or "45s"
+func FormatUptime(d time.Duration) string {
+	if d < time.Second {
+		return "0s"
+	}
+
+	d = d.Round(time.Second)
+	days := int(d / (24 * time.Hour))
+	hours := int(d % (24 * time.Hour) / time.Hour)
+	minutes := int(d % time.Hour / time.Minute)
+	seconds := int(d % time.Minute / time.Second)
+
+	switch {
+	case days > 0:
+		return fmt.Sprintf("%dd%dh", days, hours)
+	case hours > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case minutes > 0:
+		return fmt.Sprintf("%dm%ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
+
+// ParseDuration parses a positive Go duration such as "500ms", "2m" or
+// "1h30m", as accepted by the duration configuration keys
+func ParseDuration(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %q (use e.g. '500ms', '2s', '1h30m')", s)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %q", s)
+	}
+	return d, nil
+}
+
+// UsageLevel grades a resource reading against a warning threshold
+type UsageLevel int
+
+const (
+	UsageUngraded UsageLevel = iota // No threshold configured
+	UsageOK                         // Below the warning threshold
+	UsageWarn                       // At or above the warning threshold
+	UsageCritical                   // At or above twice the warning threshold
+)
+
+// ClassifyUsage grades value against warn. Readings reach UsageCritical at
+// twice the warning level; a warn of zero or less disables grading.
+func ClassifyUsage(value, warn float64) UsageLevel {
+	switch {
+	case warn <= 0:
+		return UsageUngraded
+	case value >= 2*warn:
+		return UsageCritical
+	case value >= warn:
+		return UsageWarn
+	default:
+		return UsageOK
+	}
+}
+
+// StartTimeLayout is how start times are shown in tables and details. It
+// includes the zone so times from remote or containerized hosts are clear.
+const StartTimeLayout = "2006-01-02 15:04:05 MST"
+
+// StartTimeFromMillis converts a creation time in milliseconds since the
+// epoch, as gopsutil reports it, to local time without dropping the
+// milliseconds. Non-positive values give the zero time.
+func StartTimeFromMillis(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms).Local()
+}
+
+// Uptime returns how long a process started at start has been running at
+// now. A start after now, from clock skew or a clock stepped back, clamps to
+// zero and reports skewed.
+func Uptime(start, now time.Time) (d time.Duration, skewed bool) {
+	d = now.Sub(start)
+	if d < 0 {
+		return 0, true
+	}
+	return d, false
+}
+
+// FormatSince formats the time elapsed since start, or "-" if start is unknown
+func FormatSince(start time.Time) string {
+	if start.IsZero() {
+		return "-"
+	}
+	d, skewed := Uptime(start, time.Now())
+	if skewed {
+		return "0s (clock skew)"
+	}
+	return FormatUptime(d)
+}
+
+// TemplateFuncs returns the formatting helpers for use in output templates
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"memory": func(mb float32) string { return FormatMemory(float64(mb)) },
+		"bytes":  FormatBytes,
+		"uptime": FormatUptime,
+		"since":  FormatSince,
+	}
+}
@@ -0,0 +1,183 @@
+package process
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestFormatMemory(t *testing.T) {
+	tests := []struct {
+		mb   float64
+		want string
+	}{
+		{0, "0 KB"},
+		{0.5, "512 KB"},
+		{12.34, "12.3 MB"},
+		{1023.9, "1023.9 MB"},
+		{1536, "1.50 GB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatMemory(tt.mb); got != tt.want {
+			t.Errorf("FormatMemory(%v) = %q, want %q", tt.mb, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		b    uint64
+		want string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024, "3.0 GB"},
+		{2 * 1024 * 1024 * 1024 * 1024, "2.0 TB"},
+		{4096 * 1024 * 1024 * 1024 * 1024, "4096.0 TB"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatBytes(tt.b); got != tt.want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFormatUptime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{5*time.Minute + 30*time.Second, "5m30s"},
+		{2*time.Hour + 15*time.Minute + 10*time.Second, "2h15m"},
+		{3*24*time.Hour + 4*time.Hour + 59*time.Minute, "3d4h"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatUptime(tt.d); got != tt.want {
+			t.Errorf("FormatUptime(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+
+	if got := FormatSince(time.Time{}); got != "-" {
+		t.Errorf("FormatSince(zero) = %q, want \"-\"", got)
+	}
+	if got := FormatSince(time.Now().Add(time.Minute)); got != "0s (clock skew)" {
+		t.Errorf("FormatSince(future) = %q, want \"0s (clock skew)\"", got)
+	}
+}
+
+func TestStartTimeFromMillis(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("TEST", 2*60*60)
+	defer func() { time.Local = local }()
+
+	start := StartTimeFromMillis(1700000000123)
+	if start.UnixMilli() != 1700000000123 {
+		t.Errorf("StartTimeFromMillis lost precision: got %d ms", start.UnixMilli())
+	}
+	if start.Location() != time.Local {
+		t.Errorf("StartTimeFromMillis location = %v, want local", start.Location())
+	}
+	if !StartTimeFromMillis(0).IsZero() || !StartTimeFromMillis(-5).IsZero() {
+		t.Error("Expected the zero time for non-positive create times")
+	}
+
+	data, err := json.Marshal(Process{StartTime: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"start_time":"2023-11-15T00:13:20.123+02:00"`) {
+		t.Errorf("start_time is not RFC3339 with the local offset: %s", data)
+	}
+}
+
+func TestUptime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	d, skewed := Uptime(now.Add(-90*time.Second), now)
+	if d != 90*time.Second || skewed {
+		t.Errorf("Uptime(90s ago) = %v, %v; want 1m30s, false", d, skewed)
+	}
+
+	// A start slightly in the future, as after a clock step, clamps to zero
+	d, skewed = Uptime(now.Add(2*time.Second), now)
+	if d != 0 || !skewed {
+		t.Errorf("Uptime(future) = %v, %v; want 0s, true", d, skewed)
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl := template.Must(template.New("t").Funcs(TemplateFuncs()).Parse(`{{memory .MemoryMB}} {{since .StartTime}}`))
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, Process{MemoryMB: 2048}); err != nil {
+		t.Fatalf("Unexpected template error: %v", err)
+	}
+	if out.String() != "2.00 GB -" {
+		t.Errorf("Unexpected template output: %q", out.String())
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"500ms", 500 * time.Millisecond, false},
+		{"2s", 2 * time.Second, false},
+		{"2m", 2 * time.Minute, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"2h30m", 150 * time.Minute, false}, // Rejected by the old suffix check
+		{" 3s ", 3 * time.Second, false},
+		{"5x", 0, true},
+		{"abc s", 0, true}, // Accepted by the old suffix check
+		{"ms", 0, true},
+		{"10", 0, true},
+		{"", 0, true},
+		{"0s", 0, true},
+		{"-5s", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDuration(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestClassifyUsage(t *testing.T) {
+	tests := []struct {
+		value, warn float64
+		want        UsageLevel
+	}{
+		{10, 50, UsageOK},
+		{49.9, 50, UsageOK},
+		{50, 50, UsageWarn},
+		{99, 50, UsageWarn},
+		{100, 50, UsageCritical},
+		{800, 50, UsageCritical},
+		{0, 50, UsageOK},
+		{90, 0, UsageUngraded},
+		{90, -1, UsageUngraded},
+	}
+
+	for _, tt := range tests {
+		if got := ClassifyUsage(tt.value, tt.warn); got != tt.want {
+			t.Errorf("ClassifyUsage(%v, %v) = %v, want %v", tt.value, tt.warn, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GroupFieldNames lists the fields processes can be grouped by
+var GroupFieldNames = []string{"service", "user", "protocol", "bind-scope"}
+
+// ProcessGroup is a set of processes sharing the value of a field
+type ProcessGroup struct {
+	Key       string    `json:"key"`
+	Processes []Process `json:"processes"`
+}
+
+// groupFields extracts the grouping value for each field name
+var groupFields = map[string]func(Process) string{
+	"service":    func(p Process) string { return p.ServiceType },
+	"user":       func(p Process) string { return p.User },
+	"protocol":   func(p Process) string { return p.Protocol },
+	"bind-scope": func(p Process) string { return p.BindScope },
+}
+
+// GroupProcesses groups processes by field (one of GroupFieldNames). Groups
+// are ordered by key, and processes keep their input order within a group.
+// Processes with no value for the field are grouped under "unknown".
+func GroupProcesses(processes []Process, field string) ([]ProcessGroup, error) {
+	value, ok := groupFields[strings.ToLower(strings.TrimSpace(field))]
+	if !ok {
+		return nil, fmt.Errorf("invalid group-by field %q (must be one of: %s)",
+			field, strings.Join(GroupFieldNames, ", "))
+	}
+
+	index := make(map[string]int)
+	var groups []ProcessGroup
+	for _, proc := range processes {
+		key := value(proc)
+		if key == "" {
+			key = "unknown"
+		}
+		i, seen := index[key]
+		if !seen {
+			i = len(groups)
+			index[key] = i
+			groups = append(groups, ProcessGroup{Key: key})
+		}
+		groups[i].Processes = append(groups[i].Processes, proc)
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool { return groups[i].Key < groups[j].Key })
+	return groups, nil
+}
+
+// ServiceCount is how many processes and ports one service type accounts for
+type ServiceCount struct {
+	Service   string `json:"service"`
+	Processes int    `json:"processes"` // Distinct PIDs
+	Ports     int    `json:"ports"`     // Distinct ports
+}
+
+// CountByService tallies distinct PIDs and ports per service type, most
+// processes first, then most ports, then by name. Processes with no service
+// type are counted under "unknown".
+func CountByService(processes []Process) []ServiceCount {
+	groups, _ := GroupProcesses(processes, "service")
+
+	counts := make([]ServiceCount, 0, len(groups))
+	for _, group := range groups {
+		pids := make(map[int]bool)
+		ports := make(map[int]bool)
+		for _, proc := range group.Processes {
+			pids[proc.PID] = true
+			if proc.Port > 0 {
+				ports[proc.Port] = true
+			}
+		}
+		counts = append(counts, ServiceCount{Service: group.Key, Processes: len(pids), Ports: len(ports)})
+	}
+
+	sort.SliceStable(counts, func(i, j int) bool {
+		if counts[i].Processes != counts[j].Processes {
+			return counts[i].Processes > counts[j].Processes
+		}
+		return counts[i].Ports > counts[j].Ports
+	})
+	return counts
+}
@@ -0,0 +1,93 @@
+package process
+
+import (
+	"testing"
+)
+
+func TestGroupProcesses(t *testing.T) {
+	processes := []Process{
+		{PID: 1, User: "root", Protocol: "tcp", BindScope: "all", ServiceType: "Nginx"},
+		{PID: 2, User: "alice", Protocol: "udp", BindScope: "loopback", ServiceType: "DNS"},
+		{PID: 3, User: "root", Protocol: "tcp", BindScope: "loopback", ServiceType: "Redis"},
+		{PID: 4, User: "", Protocol: "tcp", BindScope: "all", ServiceType: "Nginx"},
+	}
+
+	groups, err := GroupProcesses(processes, "user")
+	if err != nil {
+		t.Fatalf("GroupProcesses: %v", err)
+	}
+	want := []struct {
+		key  string
+		pids []int
+	}{
+		{"alice", []int{2}},
+		{"root", []int{1, 3}},
+		{"unknown", []int{4}},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("Expected %d groups, got %+v", len(want), groups)
+	}
+	for i, w := range want {
+		if groups[i].Key != w.key || len(groups[i].Processes) != len(w.pids) {
+			t.Errorf("group %d = %s with %d processes, want %s with %d", i, groups[i].Key, len(groups[i].Processes), w.key, len(w.pids))
+			continue
+		}
+		for j, pid := range w.pids {
+			if groups[i].Processes[j].PID != pid {
+				t.Errorf("group %s process %d = PID %d, want %d", w.key, j, groups[i].Processes[j].PID, pid)
+			}
+		}
+	}
+
+	for _, field := range GroupFieldNames {
+		groups, err := GroupProcesses(processes, field)
+		if err != nil {
+			t.Errorf("GroupProcesses(%s): %v", field, err)
+			continue
+		}
+		total := 0
+		for _, g := range groups {
+			total += len(g.Processes)
+		}
+		if total != len(processes) {
+			t.Errorf("GroupProcesses(%s) kept %d of %d processes", field, total, len(processes))
+		}
+	}
+}
+
+func TestGroupProcessesInvalidField(t *testing.T) {
+	if _, err := GroupProcesses(nil, "color"); err == nil {
+		t.Error("Expected an error for an unknown field")
+	}
+}
+
+func TestCountByService(t *testing.T) {
+	processes := []Process{
+		{PID: 1, Port: 3000, ServiceType: "Node.js"},
+		{PID: 1, Port: 3001, ServiceType: "Node.js"},
+		{PID: 2, Port: 5000, ServiceType: "Python"},
+		{PID: 3, Port: 4000, ServiceType: "Node.js"},
+		{PID: 4, Port: 8000, ServiceType: "Docker"},
+		{PID: 5, Port: 9999},
+	}
+
+	got := CountByService(processes)
+	want := []ServiceCount{
+		{Service: "Node.js", Processes: 2, Ports: 3},
+		{Service: "Docker", Processes: 1, Ports: 1},
+		{Service: "Python", Processes: 1, Ports: 1},
+		{Service: "unknown", Processes: 1, Ports: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("CountByService() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CountByService()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if got := CountByService(nil); len(got) != 0 {
+		t.Errorf("CountByService(nil) = %+v, want empty", got)
+	}
+}
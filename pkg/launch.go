@@ -0,0 +1,144 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// LaunchSpec captures how a process was started so that it can be re-launched
+type LaunchSpec struct {
+	PID  int      `json:"pid"`
+	Args []string `json:"args"`
+	Dir  string   `json:"dir,omitempty"`
+}
+
+// String returns the command line of the spec
+func (s *LaunchSpec) String() string {
+	return strings.Join(s.Args, " ")
+}
+
+// CaptureLaunchSpec records the command line and working directory of a running
+// process. The working directory is best-effort and left empty if unreadable.
+func (pm *ProcessManager) CaptureLaunchSpec(ctx context.Context, pid int) (*LaunchSpec, error) {
+	if pid <= 0 || pid > 2147483647 {
+		return nil, fmt.Errorf("invalid PID: %d", pid)
+	}
+
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return nil, fmt.Errorf("process %d not found: %v", pid, err)
+	}
+
+	args, err := p.CmdlineSliceWithContext(ctx)
+	if err != nil || len(args) == 0 {
+		return nil, fmt.Errorf("cannot read command line of process %d", pid)
+	}
+
+	spec := &LaunchSpec{PID: pid, Args: args}
+	if cwd, err := p.CwdWithContext(ctx); err == nil {
+		spec.Dir = cwd
+	}
+
+	return spec, nil
+}
+
+// Start launches the captured command in its original working directory with
+// the current environment, attached to the current stdout and stderr. It does
+// not wait for the command to exit.
+func (s *LaunchSpec) Start() (*os.Process, error) {
+	// #nosec G204: Re-launching a command line the user chose to restart
+	cmd := exec.Command(s.Args[0], s.Args[1:]...)
+	cmd.Dir = s.Dir
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd.Process, nil
+}
+
+// WaitForExit polls until the process exits or ctx is done. The start time of
+// the process is recorded on the first poll, so if the PID is reused by a new
+// process in the meantime the original is still reported as exited.
+func WaitForExit(ctx context.Context, pid int, poll time.Duration) error {
+	if pid <= 0 || pid > 2147483647 {
+		return fmt.Errorf("invalid PID: %d", pid)
+	}
+	return waitForExit(ctx, pid, processCreateTime(ctx, pid), poll)
+}
+
+// WaitForExitSince is like WaitForExit but guards against PID reuse with a
+// start time the caller already observed, so a process that exited and had
+// its PID reused before the first poll is still reported as exited. With a
+// zero start time it behaves exactly like WaitForExit.
+func WaitForExitSince(ctx context.Context, pid int, started time.Time, poll time.Duration) error {
+	if started.IsZero() {
+		return WaitForExit(ctx, pid, poll)
+	}
+	if pid <= 0 || pid > 2147483647 {
+		return fmt.Errorf("invalid PID: %d", pid)
+	}
+	return waitForExit(ctx, pid, started.UnixMilli(), poll)
+}
+
+// waitForExit polls pid until it exits, its creation time stops matching
+// started (milliseconds since the epoch, 0 to skip the check) or ctx is done
+func waitForExit(ctx context.Context, pid int, started int64, poll time.Duration) error {
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		if !processAlive(pid) || isZombie(ctx, pid) {
+			return nil
+		}
+		if started != 0 && processCreateTime(ctx, pid) != started {
+			// Same PID, different process
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("process %d still running: %w", pid, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// processCreateTime returns the creation time of pid in milliseconds since the
+// epoch, or 0 if it cannot be determined
+func processCreateTime(ctx context.Context, pid int) int64 {
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return 0
+	}
+	created, err := p.CreateTimeWithContext(ctx)
+	if err != nil {
+		return 0
+	}
+	return created
+}
+
+// isZombie reports whether pid has exited but not yet been reaped by its parent
+func isZombie(ctx context.Context, pid int) bool {
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return false
+	}
+	status, err := p.StatusWithContext(ctx)
+	if err != nil {
+		return false
+	}
+	for _, s := range status {
+		if s == process.Zombie {
+			return true
+		}
+	}
+	return false
+}
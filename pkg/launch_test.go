@@ -0,0 +1,71 @@
+package process
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCaptureLaunchSpec(t *testing.T) {
+	pm := NewProcessManager()
+	spec, err := pm.CaptureLaunchSpec(context.Background(), os.Getpid())
+	if err != nil {
+		t.Fatalf("CaptureLaunchSpec returned error: %v", err)
+	}
+
+	if len(spec.Args) == 0 || spec.PID != os.Getpid() {
+		t.Errorf("Unexpected launch spec: %+v", spec)
+	}
+
+	if runtime.GOOS == "linux" {
+		wd, _ := os.Getwd()
+		if spec.Dir != wd {
+			t.Errorf("Expected working directory %s, got %s", wd, spec.Dir)
+		}
+	}
+
+	if _, err := pm.CaptureLaunchSpec(context.Background(), -1); err == nil {
+		t.Error("Expected an error for an invalid PID")
+	}
+}
+
+func TestWaitForExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires sleep command")
+	}
+
+	cmd := exec.Command("sleep", "5")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("Cannot start sleep: %v", err)
+	}
+	pid := cmd.Process.Pid
+
+	// Still running: the wait should time out
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := WaitForExit(ctx, pid, 20*time.Millisecond); err == nil {
+		t.Error("Expected WaitForExit to time out for a running process")
+	}
+
+	_ = cmd.Process.Kill()
+
+	ctx2, cancel2 := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel2()
+	if err := WaitForExit(ctx2, pid, 20*time.Millisecond); err != nil {
+		t.Errorf("Expected WaitForExit to return after kill, got %v", err)
+	}
+	_ = cmd.Wait()
+}
+
+func TestWaitForExitSinceReusedPID(t *testing.T) {
+	// A start time that does not match the live process means the PID has
+	// been reused, so the original is reported as exited straight away
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := WaitForExitSince(ctx, os.Getpid(), time.Unix(1, 0), 20*time.Millisecond); err != nil {
+		t.Errorf("Expected a reused PID to count as exited, got %v", err)
+	}
+}
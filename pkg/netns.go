@@ -0,0 +1,103 @@
+package process
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// netnsDir is where "ip netns add" creates named network namespaces
+const netnsDir = "/var/run/netns"
+
+// ErrNetNSUnsupported is returned when a network namespace is requested on a
+// platform other than Linux
+var ErrNetNSUnsupported = errors.New("network namespaces are only supported on Linux")
+
+// WithNetNS makes enumeration list the sockets of another network namespace
+// (Linux only). The target is a PID whose namespace to use, a name created
+// with "ip netns add", or a path to a namespace file such as
+// /proc/1234/ns/net. Owning processes are still resolved through the host's
+// /proc, so PIDs are reported as seen from the host.
+func (pm *ProcessManager) WithNetNS(target string) *ProcessManager {
+	pm.netns = target
+	return pm
+}
+
+// getProcessesNetNS enumerates the sockets of the configured network namespace
+func (pm *ProcessManager) getProcessesNetNS(ctx context.Context, port int) ([]Process, error) {
+	if runtime.GOOS != "linux" {
+		return nil, ErrNetNSUnsupported
+	}
+
+	sockets, err := pm.readNetNSSockets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return socketOwners(ctx, sockets, port)
+}
+
+// readNetNSSockets reads the socket tables of the configured namespace. For a
+// PID the tables are read from /proc/<pid>/net, which needs no privileges;
+// named and path namespaces are entered with nsenter, which usually needs root.
+func (pm *ProcessManager) readNetNSSockets(ctx context.Context) ([]procSocket, error) {
+	pid, path := resolveNetNS(pm.netns)
+	if pid > 0 {
+		root := filepath.Join(procRoot, strconv.Itoa(pid))
+		if _, err := os.Stat(filepath.Join(root, "net")); err != nil {
+			return nil, fmt.Errorf("network namespace of process %d not found: %v", pid, err)
+		}
+		return readProcSockets(root)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("network namespace %q not found: %v", pm.netns, err)
+	}
+	if _, err := exec.LookPath("nsenter"); err != nil {
+		return nil, fmt.Errorf("nsenter is required to enter network namespace %q", pm.netns)
+	}
+
+	var sockets []procSocket
+	var firstErr error
+	read := 0
+	for _, f := range procNetFiles {
+		output, err := pm.runEnumeration(ctx, "nsenter", "--net="+path, "cat", "/proc/net/"+f.name)
+		if err != nil {
+			// tcp6/udp6 are absent when IPv6 is disabled
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		parsed, err := parseProcNet(bytes.NewReader(output), f.protocol)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse /proc/net/%s in namespace %q: %v", f.name, pm.netns, err)
+		}
+		sockets = append(sockets, parsed...)
+		read++
+	}
+
+	if read == 0 {
+		return nil, fmt.Errorf("cannot read sockets in network namespace %q (root is usually required): %w", pm.netns, firstErr)
+	}
+	return sockets, nil
+}
+
+// resolveNetNS interprets a namespace target as a PID, a path, or a name
+// under /var/run/netns. Exactly one of the results is set.
+func resolveNetNS(target string) (pid int, path string) {
+	if n, err := strconv.Atoi(target); err == nil && n > 0 {
+		return n, ""
+	}
+	if strings.Contains(target, "/") {
+		return 0, target
+	}
+	return 0, filepath.Join(netnsDir, target)
+}
@@ -0,0 +1,65 @@
+package process
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+func TestResolveNetNS(t *testing.T) {
+	tests := []struct {
+		target string
+		pid    int
+		path   string
+	}{
+		{"1234", 1234, ""},
+		{"/proc/1234/ns/net", 0, "/proc/1234/ns/net"},
+		{"./ns", 0, "./ns"},
+		{"blue", 0, filepath.Join(netnsDir, "blue")},
+		{"0", 0, filepath.Join(netnsDir, "0")},
+	}
+
+	for _, tt := range tests {
+		pid, path := resolveNetNS(tt.target)
+		if pid != tt.pid || path != tt.path {
+			t.Errorf("resolveNetNS(%q) = (%d, %q), want (%d, %q)", tt.target, pid, path, tt.pid, tt.path)
+		}
+	}
+}
+
+func TestGetProcessesNetNSByPID(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		pm := NewProcessManager().WithNetNS("1")
+		if _, err := pm.GetAllProcesses(context.Background()); err != ErrNetNSUnsupported {
+			t.Errorf("Expected ErrNetNSUnsupported, got %v", err)
+		}
+		return
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	pm := NewProcessManager().WithNetNS(strconv.Itoa(os.Getpid())).WithMetrics(false)
+	processes, err := pm.GetProcessesOnPort(context.Background(), port)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(processes) == 0 || processes[0].PID != os.Getpid() {
+		t.Errorf("Expected this process listening on port %d, got %+v", port, processes)
+	}
+}
+
+func TestGetProcessesNetNSMissing(t *testing.T) {
+	pm := NewProcessManager().WithNetNS("portctl-test-missing-namespace")
+	if _, err := pm.GetAllProcesses(context.Background()); err == nil {
+		t.Error("Expected error for a missing namespace")
+	}
+}
@@ -0,0 +1,54 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+)
+
+// CurrentUsername returns the user portctl is acting for. Under sudo this is
+// the invoking user from SUDO_USER rather than root, so bulk operations stay
+// scoped to that user's processes.
+func CurrentUsername() (string, error) {
+	if os.Geteuid() == 0 {
+		if name := os.Getenv("SUDO_USER"); name != "" && name != "root" {
+			return name, nil
+		}
+	}
+	u, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current user: %w", err)
+	}
+	return u.Username, nil
+}
+
+// OwnedBy reports whether proc belongs to username. Names are compared without
+// case and without a Windows "DOMAIN\" prefix. A process whose owner could not
+// be read is never considered owned.
+func OwnedBy(proc Process, username string) bool {
+	owner := stripDomain(proc.User)
+	if owner == "" {
+		return false
+	}
+	return strings.EqualFold(owner, stripDomain(username))
+}
+
+// FilterByOwner splits processes into those owned by username and the rest
+func FilterByOwner(processes []Process, username string) (owned, others []Process) {
+	for _, proc := range processes {
+		if OwnedBy(proc, username) {
+			owned = append(owned, proc)
+		} else {
+			others = append(others, proc)
+		}
+	}
+	return owned, others
+}
+
+func stripDomain(name string) string {
+	if i := strings.LastIndex(name, `\`); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.TrimSpace(name)
+}
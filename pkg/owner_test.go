@@ -0,0 +1,52 @@
+package process
+
+import "testing"
+
+func TestOwnedBy(t *testing.T) {
+	tests := []struct {
+		owner    string
+		username string
+		want     bool
+	}{
+		{"alice", "alice", true},
+		{"alice", "bob", false},
+		{"Alice", "alice", true},
+		{`CORP\alice`, "alice", true},
+		{"alice", `CORP\alice`, true},
+		{`CORP\alice`, `OTHER\alice`, true},
+		{"alicex", "alice", false},
+		{"", "alice", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		if got := OwnedBy(Process{User: tt.owner}, tt.username); got != tt.want {
+			t.Errorf("OwnedBy(%q, %q) = %v, want %v", tt.owner, tt.username, got, tt.want)
+		}
+	}
+}
+
+func TestFilterByOwner(t *testing.T) {
+	processes := []Process{
+		{PID: 1, User: "alice"},
+		{PID: 2, User: "bob"},
+		{PID: 3, User: ""},
+		{PID: 4, User: "alice"},
+	}
+	owned, others := FilterByOwner(processes, "alice")
+	if len(owned) != 2 || owned[0].PID != 1 || owned[1].PID != 4 {
+		t.Errorf("owned = %+v, want PIDs 1 and 4", owned)
+	}
+	if len(others) != 2 || others[0].PID != 2 || others[1].PID != 3 {
+		t.Errorf("others = %+v, want PIDs 2 and 3", others)
+	}
+}
+
+func TestCurrentUsername(t *testing.T) {
+	name, err := CurrentUsername()
+	if err != nil {
+		t.Skipf("current user unavailable: %v", err)
+	}
+	if name == "" {
+		t.Error("CurrentUsername returned an empty name")
+	}
+}
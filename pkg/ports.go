@@ -0,0 +1,98 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Valid TCP/UDP port bounds
+const (
+	MinPort = 1
+	MaxPort = 65535
+)
+
+// ParsePorts parses a port specification such as "80,443,3000-3010" into a
+// sorted list of unique ports. Lists and ranges may be mixed and may overlap.
+func ParsePorts(spec string) ([]int, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, fmt.Errorf("empty port specification")
+	}
+
+	seen := make(map[int]bool)
+	var ports []int
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return nil, fmt.Errorf("empty entry in port specification: %q", spec)
+		}
+
+		start, end, err := parsePortEntry(part)
+		if err != nil {
+			return nil, err
+		}
+
+		for port := start; port <= end; port++ {
+			if !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+
+	sort.Ints(ports)
+	return ports, nil
+}
+
+// ParsePortRange parses an inclusive range such as "3000-8999"
+func ParsePortRange(spec string) (PortRange, error) {
+	spec = strings.TrimSpace(spec)
+	if !strings.Contains(spec, "-") {
+		return PortRange{}, fmt.Errorf("invalid range %q: expected start-end", spec)
+	}
+	start, end, err := parsePortEntry(spec)
+	if err != nil {
+		return PortRange{}, err
+	}
+	return PortRange{Start: start, End: end}, nil
+}
+
+// parsePortEntry parses a single port ("80") or range ("80-90") entry
+func parsePortEntry(entry string) (int, int, error) {
+	bounds := strings.Split(entry, "-")
+	switch len(bounds) {
+	case 1:
+		port, err := parsePort(bounds[0])
+		return port, port, err
+	case 2:
+		start, err := parsePort(bounds[0])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid start port in %q: %v", entry, err)
+		}
+		end, err := parsePort(bounds[1])
+		if err != nil {
+			return 0, 0, fmt.Errorf("invalid end port in %q: %v", entry, err)
+		}
+		if start > end {
+			return 0, 0, fmt.Errorf("invalid range %q: start port is greater than end port", entry)
+		}
+		return start, end, nil
+	default:
+		return 0, 0, fmt.Errorf("invalid range format: %q", entry)
+	}
+}
+
+// parsePort parses a single port number and checks it is within bounds
+func parsePort(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port: %q", s)
+	}
+	if port < MinPort || port > MaxPort {
+		return 0, fmt.Errorf("port %d out of range (%d-%d)", port, MinPort, MaxPort)
+	}
+	return port, nil
+}
@@ -0,0 +1,102 @@
+package process
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePorts(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []int
+	}{
+		{"80", []int{80}},
+		{"443,80", []int{80, 443}},
+		{" 80 , 443 ", []int{80, 443}},
+		{"3000-3003", []int{3000, 3001, 3002, 3003}},
+		{"8080-8080", []int{8080}},
+		{"80,443,1000-1002", []int{80, 443, 1000, 1001, 1002}},
+		{"1-3,2-4", []int{1, 2, 3, 4}},
+		{"5,5,4-5", []int{4, 5}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePorts(tt.spec)
+		if err != nil {
+			t.Errorf("ParsePorts(%q) returned error: %v", tt.spec, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParsePorts(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParsePortsLargeRange(t *testing.T) {
+	ports, err := ParsePorts("1-65535,80,1000-2000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(ports) != MaxPort {
+		t.Errorf("Expected %d ports, got %d", MaxPort, len(ports))
+	}
+	if ports[0] != MinPort || ports[len(ports)-1] != MaxPort {
+		t.Errorf("Unexpected bounds: %d-%d", ports[0], ports[len(ports)-1])
+	}
+}
+
+func TestParsePortsInvalid(t *testing.T) {
+	specs := []string{
+		"",
+		"   ",
+		"abc",
+		"80,",
+		",80",
+		"80,,443",
+		"3010-3000",
+		"1-2-3",
+		"-80",
+		"80-",
+		"0",
+		"65536",
+		"1-70000",
+		"80-abc",
+	}
+
+	for _, spec := range specs {
+		if ports, err := ParsePorts(spec); err == nil {
+			t.Errorf("ParsePorts(%q) = %v, expected error", spec, ports)
+		}
+	}
+}
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    PortRange
+		wantErr bool
+	}{
+		{"3000-8999", PortRange{3000, 8999}, false},
+		{" 8080-8080 ", PortRange{8080, 8080}, false},
+		{"1-65535", PortRange{MinPort, MaxPort}, false},
+		{"3000", PortRange{}, true},
+		{"3000,4000-5000", PortRange{}, true},
+		{"5000-4000", PortRange{}, true},
+		{"0-100", PortRange{}, true},
+		{"3000-70000", PortRange{}, true},
+		{"a-b", PortRange{}, true},
+		{"1-2-3", PortRange{}, true},
+		{"", PortRange{}, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParsePortRange(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParsePortRange(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParsePortRange(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// ErrPriorityPermission is returned when the caller may not change a process's priority
+var ErrPriorityPermission = errors.New("permission denied changing process priority")
+
+// Nice values accepted by Renice, following the Unix convention where lower
+// values mean higher priority
+const (
+	MinNice = -20
+	MaxNice = 19
+)
+
+// Windows priority classes, as accepted by SetPriorityClass
+const (
+	idlePriorityClass        uint32 = 0x00000040
+	belowNormalPriorityClass uint32 = 0x00004000
+	normalPriorityClass      uint32 = 0x00000020
+	aboveNormalPriorityClass uint32 = 0x00008000
+	highPriorityClass        uint32 = 0x00000080
+)
+
+// Renice sets the scheduling priority of a process to the given nice value.
+// On Windows the value is mapped to the nearest priority class (realtime is
+// never used). Raising priority (lowering nice) usually requires root; that
+// case is reported as ErrPriorityPermission.
+func (pm *ProcessManager) Renice(ctx context.Context, pid, nice int) error {
+	if pid <= 0 || pid > 2147483647 {
+		return fmt.Errorf("invalid PID: %d", pid)
+	}
+	if nice < MinNice || nice > MaxNice {
+		return fmt.Errorf("nice value %d out of range (%d to %d)", nice, MinNice, MaxNice)
+	}
+
+	exists, err := process.PidExistsWithContext(ctx, int32(pid))
+	if err != nil {
+		return fmt.Errorf("cannot look up PID %d: %v", pid, err)
+	}
+	if !exists {
+		return fmt.Errorf("process %d not found", pid)
+	}
+
+	if err := setPriority(pid, nice); err != nil {
+		if errors.Is(err, os.ErrPermission) {
+			return fmt.Errorf("%w of PID %d (raising priority or changing another user's process needs root)", ErrPriorityPermission, pid)
+		}
+		return fmt.Errorf("failed to set priority of PID %d: %v", pid, err)
+	}
+	return nil
+}
+
+// windowsPriorityClass maps a nice value to the closest Windows priority class
+func windowsPriorityClass(nice int) uint32 {
+	switch {
+	case nice <= -15:
+		return highPriorityClass
+	case nice <= -5:
+		return aboveNormalPriorityClass
+	case nice < 5:
+		return normalPriorityClass
+	case nice < 15:
+		return belowNormalPriorityClass
+	default:
+		return idlePriorityClass
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package process
+
+import "syscall"
+
+// setPriority sets the nice value of pid with setpriority(2)
+func setPriority(pid, nice int) error {
+	return syscall.Setpriority(syscall.PRIO_PROCESS, pid, nice)
+}
@@ -0,0 +1,76 @@
+package process
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestReniceLowersPriority(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("nice values are not reported on Windows")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	ctx := context.Background()
+	pm := NewProcessManager()
+	if err := pm.Renice(ctx, cmd.Process.Pid, 5); err != nil {
+		t.Fatalf("Renice failed: %v", err)
+	}
+
+	proc := Process{PID: cmd.Process.Pid}
+	pm.enhanceProcess(ctx, &proc)
+	if proc.Nice != 5 {
+		t.Errorf("Expected nice 5 after renice, got %d", proc.Nice)
+	}
+}
+
+func TestReniceRejectsInvalidInput(t *testing.T) {
+	pm := NewProcessManager()
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		pid  int
+		nice int
+	}{
+		{"zero pid", 0, 0},
+		{"negative pid", -5, 0},
+		{"nice too low", 1, MinNice - 1},
+		{"nice too high", 1, MaxNice + 1},
+		{"missing process", 2147483647, 5},
+	}
+	for _, tt := range tests {
+		if err := pm.Renice(ctx, tt.pid, tt.nice); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestWindowsPriorityClass(t *testing.T) {
+	tests := []struct {
+		nice int
+		want uint32
+	}{
+		{MinNice, highPriorityClass},
+		{-10, aboveNormalPriorityClass},
+		{0, normalPriorityClass},
+		{4, normalPriorityClass},
+		{5, belowNormalPriorityClass},
+		{MaxNice, idlePriorityClass},
+	}
+	for _, tt := range tests {
+		if got := windowsPriorityClass(tt.nice); got != tt.want {
+			t.Errorf("windowsPriorityClass(%d) = %#x, want %#x", tt.nice, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package process
+
+import "golang.org/x/sys/windows"
+
+// setPriority sets the priority class of pid closest to the given nice value
+func setPriority(pid, nice int) error {
+	handle, err := windows.OpenProcess(windows.PROCESS_SET_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = windows.CloseHandle(handle)
+	}()
+
+	return windows.SetPriorityClass(handle, windowsPriorityClass(nice))
+}
@@ -0,0 +1,90 @@
+package process
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"sort"
+)
+
+// PrivilegeLevel describes how much of the system an enumeration can see
+type PrivilegeLevel string
+
+const (
+	// PrivilegeFull means processes owned by all users are visible
+	PrivilegeFull PrivilegeLevel = "full"
+	// PrivilegeLimited means processes owned by other users may be hidden
+	PrivilegeLimited PrivilegeLevel = "limited"
+	// PrivilegeUnknown means the privilege level could not be determined
+	PrivilegeUnknown PrivilegeLevel = "unknown"
+)
+
+// PrivilegeHint reports whether a process listing is likely incomplete
+type PrivilegeHint struct {
+	Level PrivilegeLevel `json:"level"`
+	// HiddenPorts are listening ports the kernel reports but no visible process owns
+	HiddenPorts []int `json:"hidden_ports,omitempty"`
+}
+
+// Incomplete reports whether the listing is missing processes because of privileges
+func (h PrivilegeHint) Incomplete() bool {
+	return h.Level == PrivilegeLimited && len(h.HiddenPorts) > 0
+}
+
+// PrivilegeLevel returns the privilege level of the current process
+func (pm *ProcessManager) PrivilegeLevel() PrivilegeLevel {
+	if runtime.GOOS == "windows" {
+		return PrivilegeUnknown
+	}
+	if os.Geteuid() == 0 {
+		return PrivilegeFull
+	}
+	return PrivilegeLimited
+}
+
+// PrivilegeHint checks whether processes (enumerated for port, or all ports if 0)
+// are likely incomplete because the current user lacks privileges. Hidden
+// listeners can only be detected on Linux, where the kernel socket tables are
+// world-readable; elsewhere only the privilege level is reported.
+func (pm *ProcessManager) PrivilegeHint(ctx context.Context, processes []Process, port int) PrivilegeHint {
+	hint := PrivilegeHint{Level: pm.PrivilegeLevel()}
+	// The host socket tables say nothing about another network namespace
+	if hint.Level != PrivilegeLimited || runtime.GOOS != "linux" || pm.netns != "" || ctx.Err() != nil {
+		return hint
+	}
+
+	sockets, err := readProcSockets(procRoot)
+	if err != nil {
+		return hint
+	}
+	hint.HiddenPorts = hiddenListeners(sockets, processes, port)
+	return hint
+}
+
+// hiddenListeners returns the TCP listening ports in sockets that none of the
+// visible processes account for, optionally restricted to a single port
+func hiddenListeners(sockets []procSocket, processes []Process, port int) []int {
+	visible := make(map[int]bool, len(processes))
+	for _, proc := range processes {
+		visible[proc.Port] = true
+	}
+
+	seen := make(map[int]bool)
+	var hidden []int
+	for _, sock := range sockets {
+		if sock.Protocol != "tcp" || sock.State != "LISTEN" {
+			continue
+		}
+		if port != 0 && sock.LocalPort != port {
+			continue
+		}
+		if visible[sock.LocalPort] || seen[sock.LocalPort] {
+			continue
+		}
+		seen[sock.LocalPort] = true
+		hidden = append(hidden, sock.LocalPort)
+	}
+
+	sort.Ints(hidden)
+	return hidden
+}
@@ -0,0 +1,41 @@
+package process
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHiddenListeners(t *testing.T) {
+	sockets, err := parseProcNet(strings.NewReader(sampleProcNetTCP), "tcp")
+	if err != nil {
+		t.Fatalf("parseProcNet returned error: %v", err)
+	}
+
+	// Listeners on 8080 and 3000; the ESTABLISHED socket on 3000 is not a listener
+	if got := hiddenListeners(sockets, nil, 0); !reflect.DeepEqual(got, []int{3000, 8080}) {
+		t.Errorf("Expected both listeners to be hidden, got %v", got)
+	}
+
+	visible := []Process{{PID: 1, Port: 3000}}
+	if got := hiddenListeners(sockets, visible, 0); !reflect.DeepEqual(got, []int{8080}) {
+		t.Errorf("Expected only 8080 to be hidden, got %v", got)
+	}
+
+	if got := hiddenListeners(sockets, visible, 3000); len(got) != 0 {
+		t.Errorf("Expected nothing hidden on port 3000, got %v", got)
+	}
+}
+
+func TestPrivilegeHint(t *testing.T) {
+	pm := NewProcessManager()
+	hint := pm.PrivilegeHint(context.Background(), nil, 0)
+
+	if hint.Level != pm.PrivilegeLevel() {
+		t.Errorf("Hint level %q does not match PrivilegeLevel %q", hint.Level, pm.PrivilegeLevel())
+	}
+	if hint.Level != PrivilegeLimited && hint.Incomplete() {
+		t.Error("Only limited privileges should report an incomplete listing")
+	}
+}
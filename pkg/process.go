@@ -3,25 +3,34 @@ package process
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
-	"os"
+	"log/slog"
+	"net"
 	"os/exec"
 	"regexp"
 	"runtime"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/mem"
+	psnet "github.com/shirou/gopsutil/v3/net"
 	"github.com/shirou/gopsutil/v3/process"
 )
 
+// ErrProcessNotFound is returned when no process with the requested PID exists
+var ErrProcessNotFound = errors.New("process not found")
+
 // Process represents a process listening on a port with enhanced details
 type Process struct {
 	PID         int       `json:"pid"`
+	PPID        int       `json:"ppid,omitempty"` // Parent PID; set by enrichment, so 0 with metrics disabled
 	Port        int       `json:"port"`
 	Command     string    `json:"command"`
 	Protocol    string    `json:"protocol"`
@@ -34,40 +43,195 @@ type Process struct {
 	FullCommand string    `json:"full_command"`
 	LocalAddr   string    `json:"local_addr"`
 	RemoteAddr  string    `json:"remote_addr"`
+	RemoteHost  string    `json:"remote_host,omitempty"`  // Set by Resolver.ResolveProcesses
+	RemoteOwner string    `json:"remote_owner,omitempty"` // Set by Resolver.ResolveProcesses with ASN lookups
+	BindScope   string    `json:"bind_scope"`
+	Nice        int       `json:"nice"`               // Unix nice value; Windows base priority (4 idle to 24 realtime)
+	Inode       uint64    `json:"inode,omitempty"`    // Socket inode; Linux /proc enumeration and GetSockets only
+	OpenFDs     int       `json:"open_fds,omitempty"` // Open file descriptors; Unix enrichment only
+	FDLimit     uint64    `json:"fd_limit,omitempty"` // Soft RLIMIT_NOFILE; 0 if unknown or unlimited
 }
 
 // SystemStats represents system-wide statistics
 type SystemStats struct {
-	TotalProcesses    int       `json:"total_processes"`
-	ListeningPorts    int       `json:"listening_ports"`
-	CPUUsagePercent   float64   `json:"cpu_usage_percent"`
-	MemoryUsageGB     float64   `json:"memory_usage_gb"`
-	AvailableMemoryGB float64   `json:"available_memory_gb"`
-	TopPortUsers      []Process `json:"top_port_users"`
+	TotalProcesses    int            `json:"total_processes"`
+	ListeningPorts    int            `json:"listening_ports"`
+	CPUUsagePercent   float64        `json:"cpu_usage_percent"`
+	PerCorePercent    []float64      `json:"per_core_percent,omitempty"`
+	MemoryUsageGB     float64        `json:"memory_usage_gb"`
+	AvailableMemoryGB float64        `json:"available_memory_gb"`
+	TotalMemoryGB     float64        `json:"total_memory_gb"` // Installed memory, more than used plus available
+	BytesSent         uint64         `json:"bytes_sent"`
+	BytesRecv         uint64         `json:"bytes_recv"`
+	SendRate          float64        `json:"send_rate_bytes_per_sec"`
+	RecvRate          float64        `json:"recv_rate_bytes_per_sec"`
+	Established       int            `json:"established_connections"`
+	TopPortUsers      []Process      `json:"top_port_users"`
+	Services          []ServiceCount `json:"services"`
 }
 
 // FilterOptions defines criteria for filtering processes
 type FilterOptions struct {
-	Service     string
-	User        string
-	MemoryLimit float64
-	CPULimit    float64
+	Service        string // Substring of the service type or command name
+	Command        string // Exact command name, see MatchesCommand
+	ExcludeService string
+	User           string
+	MemoryLimit    float64
+	CPULimit       float64
+	BindScope      string
+	Addrs          []net.IP // Keep sockets bound to one of these, see ListensOn
+	PPID           int      // Keep direct children of this parent PID
+	FDLimit        int      // Keep processes with more open file descriptors than this
 }
 
-// ProcessManager handles process operations with enhanced features
+// DefaultTopN is the number of top resource users reported in system stats by default
+const DefaultTopN = 5
+
+// StatsOptions controls how system statistics are gathered
+type StatsOptions struct {
+	TopN  int    // Number of top users to report (default: DefaultTopN)
+	TopBy string // Resource to rank top users by: "memory" (default) or "cpu"
+}
+
+// DefaultEnumerationTimeout bounds how long a single external enumeration command may run
+const DefaultEnumerationTimeout = 10 * time.Second
+
+// ErrEnumerationTimeout is returned when an external enumeration command exceeds its timeout
+var ErrEnumerationTimeout = errors.New("enumeration timed out")
+
+// enhanceWorkers bounds how many processes are enriched concurrently
+const enhanceWorkers = 8
+
+// CPUSampleInterval is the window over which per-process CPU usage is measured.
+// All processes share one window, so enumeration costs at most this much extra.
+const CPUSampleInterval = 200 * time.Millisecond
+
+// ProcessManager handles process operations with enhanced features.
+// WithMetrics and WithCache may be called while the manager is in use, so a
+// long-running server can apply a reloaded configuration; the other options
+// must be set before first use.
 type ProcessManager struct {
-	enableMetrics bool
+	enableMetrics atomic.Bool
+	timeout       time.Duration
+	cache         atomic.Pointer[snapshotCache]
+	workers       int
+	deep          bool
+	netns         string
+	ephemeral     bool
 }
 
 // NewProcessManager creates a new ProcessManager
 func NewProcessManager() *ProcessManager {
-	return &ProcessManager{
-		enableMetrics: true,
+	pm := &ProcessManager{
+		timeout: DefaultEnumerationTimeout,
+		workers: enhanceWorkers,
+	}
+	pm.enableMetrics.Store(true)
+	return pm
+}
+
+// WithTimeout sets the maximum duration of each external enumeration command
+// (lsof, netstat, tasklist). A zero duration disables the timeout.
+func (pm *ProcessManager) WithTimeout(timeout time.Duration) *ProcessManager {
+	pm.timeout = timeout
+	return pm
+}
+
+// WithMetrics controls whether enumeration enriches each process with CPU,
+// memory, user, parent PID, start time, open file descriptors and full
+// command line. Disabling it keeps only the fields parsed from lsof/netstat
+// (plus service and bind scope detection), which is much faster on hosts
+// with many listeners. Changing it drops any cached snapshot, which was
+// taken with the old setting.
+func (pm *ProcessManager) WithMetrics(enabled bool) *ProcessManager {
+	if pm.enableMetrics.Swap(enabled) != enabled {
+		if cache := pm.cache.Load(); cache != nil {
+			cache.invalidate()
+		}
 	}
+	return pm
+}
+
+// WithDeep makes enumeration on Linux and macOS query every available source
+// (lsof, netstat and, on Linux, /proc) and merge the results, instead of using
+// only the first available tool. Sources that fail are skipped.
+func (pm *ProcessManager) WithDeep(enabled bool) *ProcessManager {
+	pm.deep = enabled
+	return pm
+}
+
+// WithEphemeral controls whether FindAvailablePorts and FindAvailableBlock may
+// suggest ports in the operating system's ephemeral range (see
+// EphemeralPortRange). They are skipped by default.
+func (pm *ProcessManager) WithEphemeral(include bool) *ProcessManager {
+	pm.ephemeral = include
+	return pm
+}
+
+// WithCache makes GetAllProcesses and GetProcessesOnPort reuse the last full
+// enumeration for up to ttl. It is safe for concurrent use and intended for
+// long-running servers; a zero or negative ttl disables caching. Changing the
+// ttl of an enabled cache keeps the current snapshot and its statistics.
+func (pm *ProcessManager) WithCache(ttl time.Duration) *ProcessManager {
+	if ttl <= 0 {
+		pm.cache.Store(nil)
+		return pm
+	}
+	if cache := pm.cache.Load(); cache != nil {
+		cache.setTTL(ttl)
+		return pm
+	}
+	pm.cache.Store(&snapshotCache{ttl: ttl})
+	return pm
+}
+
+// CacheTTL returns how long listings are reused, or zero when caching is disabled
+func (pm *ProcessManager) CacheTTL() time.Duration {
+	if cache := pm.cache.Load(); cache != nil {
+		return cache.getTTL()
+	}
+	return 0
+}
+
+// InvalidateCache drops any cached snapshot, so the next listing enumerates
+// afresh. Call it before acting on a lookup, such as killing the processes on
+// a port, where a stale snapshot could miss a new listener or name a reused PID.
+func (pm *ProcessManager) InvalidateCache() {
+	if cache := pm.cache.Load(); cache != nil {
+		cache.invalidate()
+	}
+}
+
+// MetricsEnabled reports whether enumeration enriches processes, see WithMetrics
+func (pm *ProcessManager) MetricsEnabled() bool {
+	return pm.enableMetrics.Load()
+}
+
+// CacheStats returns the number of cache hits and misses (zero when caching is disabled)
+func (pm *ProcessManager) CacheStats() (hits, misses uint64) {
+	cache := pm.cache.Load()
+	if cache == nil {
+		return 0, 0
+	}
+	return cache.stats()
 }
 
 // GetProcessesOnPort returns all processes listening on the specified port with enhanced details
 func (pm *ProcessManager) GetProcessesOnPort(ctx context.Context, port int) ([]Process, error) {
+	if pm.cache.Load() != nil {
+		all, err := pm.GetAllProcesses(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var processes []Process
+		for _, proc := range all {
+			if proc.Port == port {
+				processes = append(processes, proc)
+			}
+		}
+		return processes, nil
+	}
+
 	processes, err := pm.getBasicProcesses(ctx, port)
 	if err != nil {
 		return nil, err
@@ -79,6 +243,14 @@ func (pm *ProcessManager) GetProcessesOnPort(ctx context.Context, port int) ([]P
 
 // GetAllProcesses returns all processes with open ports with enhanced details
 func (pm *ProcessManager) GetAllProcesses(ctx context.Context) ([]Process, error) {
+	if cache := pm.cache.Load(); cache != nil {
+		return cache.get(ctx, pm.enumerateAll)
+	}
+	return pm.enumerateAll(ctx)
+}
+
+// enumerateAll performs a full, uncached enumeration
+func (pm *ProcessManager) enumerateAll(ctx context.Context) ([]Process, error) {
 	processes, err := pm.getBasicProcesses(ctx, 0)
 	if err != nil {
 		return nil, err
@@ -96,17 +268,25 @@ func (pm *ProcessManager) GetAllProcesses(ctx context.Context) ([]Process, error
 }
 
 // GetSystemStats returns comprehensive system statistics
-func (pm *ProcessManager) GetSystemStats(ctx context.Context) (*SystemStats, error) {
+func (pm *ProcessManager) GetSystemStats(ctx context.Context, opts StatsOptions) (*SystemStats, error) {
 	processes, err := pm.GetAllProcesses(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get CPU usage
-	cpuPercent, err := cpu.PercentWithContext(ctx, time.Second, false)
+	// Sample network counters around the CPU interval to derive throughput
+	netBefore, _ := psnet.IOCountersWithContext(ctx, false)
+	sampleStart := time.Now()
+
+	// Get per-core CPU usage; the aggregate is derived from the same sample
+	perCore, err := cpu.PercentWithContext(ctx, time.Second, true)
 	if err != nil {
-		cpuPercent = []float64{0}
+		perCore = nil
 	}
+	cpuPercent := averagePercent(perCore)
+
+	netAfter, _ := psnet.IOCountersWithContext(ctx, false)
+	elapsed := time.Since(sampleStart).Seconds()
 
 	// Get memory stats
 	memStats, err := mem.VirtualMemoryWithContext(ctx)
@@ -114,24 +294,41 @@ func (pm *ProcessManager) GetSystemStats(ctx context.Context) (*SystemStats, err
 		return nil, err
 	}
 
-	// Get top port users (by memory usage)
-	topUsers := make([]Process, len(processes))
-	copy(topUsers, processes)
-	sort.Slice(topUsers, func(i, j int) bool {
-		return topUsers[i].MemoryMB > topUsers[j].MemoryMB
-	})
-	if len(topUsers) > 5 {
-		topUsers = topUsers[:5]
-	}
+	// Get top port users
+	topUsers := topProcesses(processes, opts.TopN, opts.TopBy)
 
-	return &SystemStats{
+	stats := &SystemStats{
 		TotalProcesses:    len(processes),
 		ListeningPorts:    pm.countUniquePorts(processes),
-		CPUUsagePercent:   cpuPercent[0],
+		CPUUsagePercent:   cpuPercent,
+		PerCorePercent:    perCore,
 		MemoryUsageGB:     float64(memStats.Used) / 1024 / 1024 / 1024,
 		AvailableMemoryGB: float64(memStats.Available) / 1024 / 1024 / 1024,
+		TotalMemoryGB:     float64(memStats.Total) / 1024 / 1024 / 1024,
 		TopPortUsers:      topUsers,
-	}, nil
+		Services:          CountByService(processes),
+	}
+
+	// Network throughput
+	if len(netAfter) > 0 {
+		stats.BytesSent = netAfter[0].BytesSent
+		stats.BytesRecv = netAfter[0].BytesRecv
+		if len(netBefore) > 0 && elapsed > 0 {
+			stats.SendRate = counterRate(netBefore[0].BytesSent, netAfter[0].BytesSent, elapsed)
+			stats.RecvRate = counterRate(netBefore[0].BytesRecv, netAfter[0].BytesRecv, elapsed)
+		}
+	}
+
+	// Established connections
+	if conns, err := psnet.ConnectionsWithContext(ctx, "inet"); err == nil {
+		for _, conn := range conns {
+			if conn.Status == "ESTABLISHED" {
+				stats.Established++
+			}
+		}
+	}
+
+	return stats, nil
 }
 
 // GetProcessesByService returns processes filtered by service type
@@ -154,19 +351,72 @@ func (pm *ProcessManager) GetProcessesByService(ctx context.Context, serviceType
 	return filtered, nil
 }
 
+// GetProcessByPID describes a process by PID, whether or not it owns a port
+func (pm *ProcessManager) GetProcessByPID(ctx context.Context, pid int) (*Process, error) {
+	if pid <= 0 || pid > 2147483647 {
+		return nil, fmt.Errorf("invalid PID: %d", pid)
+	}
+
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if errors.Is(err, process.ErrorProcessNotRunning) {
+		return nil, fmt.Errorf("%w: PID %d", ErrProcessNotFound, pid)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up PID %d: %v", pid, err)
+	}
+
+	proc := Process{PID: pid, Command: "unknown"}
+	if name, err := p.NameWithContext(ctx); err == nil {
+		proc.Command = name
+	}
+	enhanced := pm.enhanceProcesses(ctx, []Process{proc})
+
+	return &enhanced[0], nil
+}
+
+// GetChildren returns the direct child PIDs of each of the given processes,
+// scanning the process table once. PIDs without children are omitted.
+func (pm *ProcessManager) GetChildren(ctx context.Context, pids ...int) (map[int][]int, error) {
+	want := make(map[int]bool, len(pids))
+	for _, pid := range pids {
+		want[pid] = true
+	}
+
+	all, err := process.PidsWithContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list processes: %v", err)
+	}
+
+	children := make(map[int][]int)
+	for _, pid := range all {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		p, err := process.NewProcessWithContext(ctx, pid)
+		if err != nil {
+			// Exited since the PID list was read
+			continue
+		}
+		ppid, err := p.PpidWithContext(ctx)
+		if err != nil || !want[int(ppid)] {
+			continue
+		}
+		children[int(ppid)] = append(children[int(ppid)], int(pid))
+	}
+
+	for _, kids := range children {
+		sort.Ints(kids)
+	}
+	return children, nil
+}
+
 // FindAvailablePorts suggests available ports in common ranges
 func (pm *ProcessManager) FindAvailablePorts(ctx context.Context, startPort, endPort int, count int) ([]int, error) {
-	processes, err := pm.GetAllProcesses(ctx)
+	usedPorts, err := pm.unavailablePorts(ctx, startPort, endPort)
 	if err != nil {
 		return nil, err
 	}
 
-	// Create a map of used ports
-	usedPorts := make(map[int]bool)
-	for _, proc := range processes {
-		usedPorts[proc.Port] = true
-	}
-
 	var available []int
 	for port := startPort; port <= endPort && len(available) < count; port++ {
 		if !usedPorts[port] {
@@ -177,6 +427,72 @@ func (pm *ProcessManager) FindAvailablePorts(ctx context.Context, startPort, end
 	return available, nil
 }
 
+// ErrNoAvailableBlock is returned when a range has no run of free ports long enough
+var ErrNoAvailableBlock = errors.New("no block of consecutive free ports found")
+
+// FindAvailableBlock returns the first port of the lowest run of size
+// consecutive unused ports within startPort-endPort
+func (pm *ProcessManager) FindAvailableBlock(ctx context.Context, startPort, endPort, size int) (int, error) {
+	if size < 1 {
+		return 0, fmt.Errorf("block size must be at least 1, got %d", size)
+	}
+
+	usedPorts, err := pm.unavailablePorts(ctx, startPort, endPort)
+	if err != nil {
+		return 0, err
+	}
+
+	start := findFreeBlock(usedPorts, startPort, endPort, size)
+	if start < 0 {
+		return 0, fmt.Errorf("%w: %d port(s) in range %d-%d", ErrNoAvailableBlock, size, startPort, endPort)
+	}
+	return start, nil
+}
+
+// findFreeBlock returns the start of the first run of size ports in
+// startPort-endPort that are not in used, or -1 if there is none
+func findFreeBlock(used map[int]bool, startPort, endPort, size int) int {
+	run := 0
+	for port := startPort; port <= endPort; port++ {
+		if used[port] {
+			run = 0
+			continue
+		}
+		run++
+		if run == size {
+			return port - size + 1
+		}
+	}
+	return -1
+}
+
+// unavailablePorts returns the set of ports that must not be suggested: those
+// with a listening process and, unless WithEphemeral(true) was set, the
+// ephemeral ports between startPort and endPort
+func (pm *ProcessManager) unavailablePorts(ctx context.Context, startPort, endPort int) (map[int]bool, error) {
+	processes, err := pm.GetAllProcesses(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	used := make(map[int]bool, len(processes))
+	for _, proc := range processes {
+		used[proc.Port] = true
+	}
+
+	if !pm.ephemeral {
+		ephemeral, err := EphemeralPortRange(ctx)
+		if err != nil {
+			slog.Debug("cannot read ephemeral port range, assuming the IANA default", "error", err)
+			ephemeral = DefaultEphemeralRange
+		}
+		for port := max(startPort, ephemeral.Start); port <= min(endPort, ephemeral.End); port++ {
+			used[port] = true
+		}
+	}
+	return used, nil
+}
+
 // KillProcesses kills multiple processes by PID with enhanced error reporting
 func (pm *ProcessManager) KillProcesses(ctx context.Context, pids []int, force bool) map[int]error {
 	results := make(map[int]error)
@@ -188,32 +504,25 @@ func (pm *ProcessManager) KillProcesses(ctx context.Context, pids []int, force b
 	return results
 }
 
-// KillProcess kills a process by PID
-func (pm *ProcessManager) KillProcess(ctx context.Context, pid int, force bool) error {
-	if runtime.GOOS == "windows" {
-		var cmd *exec.Cmd
-		if force {
-			// #nosec G204: Arguments are constructed from validated integer pid, not user input
-			cmd = exec.CommandContext(ctx, "taskkill", "/F", "/PID", strconv.Itoa(pid))
-		} else {
-			// #nosec G204: Arguments are constructed from validated integer pid, not user input
-			cmd = exec.CommandContext(ctx, "taskkill", "/PID", strconv.Itoa(pid))
-		}
-		return cmd.Run()
-	} else {
-		// Unix-like systems
-		process, err := os.FindProcess(pid)
-		if err != nil {
-			return fmt.Errorf("failed to find process %d: %v", pid, err)
-		}
+// SignalProcesses sends sig to multiple processes
+func (pm *ProcessManager) SignalProcesses(ctx context.Context, pids []int, sig syscall.Signal) map[int]error {
+	results := make(map[int]error)
 
-		signal := syscall.SIGTERM
-		if force {
-			signal = syscall.SIGKILL
-		}
+	for _, pid := range pids {
+		results[pid] = pm.SignalProcess(ctx, pid, sig)
+	}
+
+	return results
+}
 
-		return process.Signal(signal)
+// KillProcess kills a process by PID: SIGTERM, or SIGKILL when force is set
+// (taskkill and taskkill /F on Windows)
+func (pm *ProcessManager) KillProcess(ctx context.Context, pid int, force bool) error {
+	signal := syscall.SIGTERM
+	if force {
+		signal = syscall.SIGKILL
 	}
+	return pm.SignalProcess(ctx, pid, signal)
 }
 
 // FilterProcesses filters a list of processes based on options
@@ -231,6 +540,19 @@ func (pm *ProcessManager) FilterProcesses(processes []Process, opts FilterOption
 			}
 		}
 
+		// Filter by exact command name
+		if opts.Command != "" && !MatchesCommand(proc, opts.Command) {
+			match = false
+		}
+
+		// Exclude by service type
+		if opts.ExcludeService != "" {
+			if strings.Contains(strings.ToLower(proc.ServiceType), strings.ToLower(opts.ExcludeService)) ||
+				strings.Contains(strings.ToLower(proc.Command), strings.ToLower(opts.ExcludeService)) {
+				match = false
+			}
+		}
+
 		// Filter by user
 		if opts.User != "" {
 			if !strings.Contains(strings.ToLower(proc.User), strings.ToLower(opts.User)) {
@@ -238,6 +560,21 @@ func (pm *ProcessManager) FilterProcesses(processes []Process, opts FilterOption
 			}
 		}
 
+		// Filter by bind scope
+		if opts.BindScope != "" && proc.BindScope != opts.BindScope {
+			match = false
+		}
+
+		// Filter by parent process
+		if opts.PPID > 0 && proc.PPID != opts.PPID {
+			match = false
+		}
+
+		// Filter by bound address, e.g. those of one interface
+		if len(opts.Addrs) > 0 && !ListensOn(proc.LocalAddr, opts.Addrs) {
+			match = false
+		}
+
 		// Filter by memory usage
 		if opts.MemoryLimit > 0 && proc.MemoryMB <= float32(opts.MemoryLimit) {
 			match = false
@@ -248,6 +585,11 @@ func (pm *ProcessManager) FilterProcesses(processes []Process, opts FilterOption
 			match = false
 		}
 
+		// Filter by open file descriptors
+		if opts.FDLimit > 0 && proc.OpenFDs <= opts.FDLimit {
+			match = false
+		}
+
 		if match {
 			filtered = append(filtered, proc)
 		}
@@ -256,38 +598,34 @@ func (pm *ProcessManager) FilterProcesses(processes []Process, opts FilterOption
 	return filtered
 }
 
-// SortProcesses sorts a list of processes by a given field
-func (pm *ProcessManager) SortProcesses(processes []Process, sortBy string) []Process {
-	if sortBy == "" {
-		sortBy = "port" // Default sort by port
-	}
-
-	sort.Slice(processes, func(i, j int) bool {
-		switch strings.ToLower(sortBy) {
-		case "pid":
-			return processes[i].PID < processes[j].PID
-		case "port":
-			return processes[i].Port < processes[j].Port
-		case "cpu":
-			return processes[i].CPUPercent > processes[j].CPUPercent // Descending
-		case "memory", "mem":
-			return processes[i].MemoryMB > processes[j].MemoryMB // Descending
-		case "command", "cmd":
-			return processes[i].Command < processes[j].Command
-		case "service":
-			return processes[i].ServiceType < processes[j].ServiceType
-		case "user":
-			return processes[i].User < processes[j].User
-		default:
-			return processes[i].Port < processes[j].Port
+// MatchesCommand reports whether proc's command name is exactly name, so
+// "node" matches node but not nodemon. Both the reported command and the base
+// name of the executable in FullCommand are tried, since lsof truncates long
+// command names; a ".exe" suffix is ignored.
+func MatchesCommand(proc Process, name string) bool {
+	name = strings.TrimSuffix(name, ".exe")
+	if name == "" {
+		return false
+	}
+	if strings.TrimSuffix(proc.Command, ".exe") == name {
+		return true
+	}
+	if fields := strings.Fields(proc.FullCommand); len(fields) > 0 {
+		exe := fields[0]
+		if i := strings.LastIndexAny(exe, `/\`); i >= 0 {
+			exe = exe[i+1:]
 		}
-	})
-
-	return processes
+		return strings.TrimSuffix(exe, ".exe") == name
+	}
+	return false
 }
 
 // getBasicProcesses gets basic process information (original functionality)
 func (pm *ProcessManager) getBasicProcesses(ctx context.Context, targetPort int) ([]Process, error) {
+	if pm.netns != "" {
+		return pm.getProcessesNetNS(ctx, targetPort)
+	}
+
 	switch runtime.GOOS {
 	case "darwin", "linux":
 		return pm.getProcessesUnix(ctx, targetPort)
@@ -298,19 +636,123 @@ func (pm *ProcessManager) getBasicProcesses(ctx context.Context, targetPort int)
 	}
 }
 
-// enhanceProcesses adds detailed metrics to processes
+// enhanceProcesses adds detailed metrics to processes using a bounded pool of
+// workers. Each worker writes only to its own slice element, so order is
+// preserved. If ctx is cancelled, processes not yet started are only classified.
+//
+// CPU usage is measured as the CPU time each process used during a shared
+// CPUSampleInterval window that overlaps the enrichment work.
 func (pm *ProcessManager) enhanceProcesses(ctx context.Context, processes []Process) []Process {
-	if !pm.enableMetrics {
+	if !pm.enableMetrics.Load() {
+		for i := range processes {
+			pm.classifyProcess(&processes[i])
+		}
 		return processes
 	}
 
+	sampleStart := time.Now()
+	cpuBefore := sampleCPUTimes(ctx, processes)
+
+	workers := pm.workers
+	if workers > len(processes) {
+		workers = len(processes)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				pm.enhanceProcess(ctx, &processes[i])
+			}
+		}()
+	}
+
+	next := 0
+dispatch:
+	for ; next < len(processes); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := next; i < len(processes); i++ {
+		pm.classifyProcess(&processes[i])
+	}
+
+	// Finish the CPU sampling window and take the second sample
+	timer := time.NewTimer(time.Until(sampleStart.Add(CPUSampleInterval)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		return processes
+	}
+	cpuAfter := sampleCPUTimes(ctx, processes)
+	elapsed := time.Since(sampleStart)
+
 	for i := range processes {
-		pm.enhanceProcess(ctx, &processes[i])
+		before, ok1 := cpuBefore[processes[i].PID]
+		after, ok2 := cpuAfter[processes[i].PID]
+		if ok1 && ok2 {
+			processes[i].CPUPercent = cpuPercentBetween(before, after, elapsed)
+		}
 	}
 
 	return processes
 }
 
+// sampleCPUTimes returns the total CPU seconds (user + system) consumed so far
+// by each distinct PID in processes
+func sampleCPUTimes(ctx context.Context, processes []Process) map[int]float64 {
+	samples := make(map[int]float64, len(processes))
+	for _, proc := range processes {
+		if _, done := samples[proc.PID]; done {
+			continue
+		}
+		if total, ok := cpuTime(ctx, proc.PID); ok {
+			samples[proc.PID] = total
+		}
+	}
+	return samples
+}
+
+// cpuTime returns the total CPU seconds (user + system) consumed so far by pid
+func cpuTime(ctx context.Context, pid int) (float64, bool) {
+	if pid <= 0 || pid > 2147483647 {
+		return 0, false
+	}
+	p, err := process.NewProcessWithContext(ctx, int32(pid))
+	if err != nil {
+		return 0, false
+	}
+	times, err := p.TimesWithContext(ctx)
+	if err != nil {
+		return 0, false
+	}
+	return times.User + times.System, true
+}
+
+// cpuPercentBetween converts two cumulative CPU time samples taken elapsed
+// apart into a usage percentage, where 100 means one fully busy core (so a
+// multi-threaded process may exceed 100). A decreasing counter, which means
+// the PID was reused between samples, yields 0.
+func cpuPercentBetween(before, after float64, elapsed time.Duration) float64 {
+	if elapsed <= 0 || after < before {
+		return 0
+	}
+	return (after - before) / elapsed.Seconds() * 100
+}
+
 // enhanceProcess adds detailed metrics to a single process
 func (pm *ProcessManager) enhanceProcess(ctx context.Context, proc *Process) {
 	// Get detailed process information
@@ -318,11 +760,6 @@ func (pm *ProcessManager) enhanceProcess(ctx context.Context, proc *Process) {
 		return
 	}
 	if p, err := process.NewProcessWithContext(ctx, int32(proc.PID)); err == nil {
-		// Get CPU percent
-		if cpuPercent, err := p.CPUPercentWithContext(ctx); err == nil {
-			proc.CPUPercent = cpuPercent
-		}
-
 		// Get memory info
 		if memInfo, err := p.MemoryInfoWithContext(ctx); err == nil {
 			proc.MemoryMB = float32(memInfo.RSS) / 1024 / 1024
@@ -333,23 +770,51 @@ func (pm *ProcessManager) enhanceProcess(ctx context.Context, proc *Process) {
 			proc.User = username
 		}
 
+		// Get parent PID
+		if ppid, err := p.PpidWithContext(ctx); err == nil {
+			proc.PPID = int(ppid)
+		}
+
 		// Get start time
 		if createTime, err := p.CreateTimeWithContext(ctx); err == nil {
-			proc.StartTime = time.Unix(createTime/1000, 0)
+			proc.StartTime = StartTimeFromMillis(createTime)
 		}
 
+		// Get open file descriptors and their limit
+		proc.OpenFDs, proc.FDLimit = openFDs(ctx, p)
+
 		// Get full command line
 		if cmdline, err := p.CmdlineWithContext(ctx); err == nil {
 			proc.FullCommand = cmdline
 		}
+
+		// Get scheduling priority
+		if nice, err := p.NiceWithContext(ctx); err == nil {
+			proc.Nice = int(nice)
+			if runtime.GOOS == "linux" {
+				// gopsutil returns the raw getpriority(2) result, which the
+				// Linux kernel reports as 20 - nice
+				proc.Nice = 20 - proc.Nice
+			}
+		}
 	}
 
-	// Detect service type
+	pm.classifyProcess(proc)
+}
+
+// classifyProcess fills in the fields derived from the port, command and address
+func (pm *ProcessManager) classifyProcess(proc *Process) {
 	proc.ServiceType = pm.detectServiceType(proc.Port, proc.Command)
+	proc.BindScope = DetectBindScope(proc.LocalAddr)
 }
 
 // detectServiceType identifies the type of service based on port and command
 func (pm *ProcessManager) detectServiceType(port int, command string) string {
+	// User-defined ports and command patterns win over the built-in rules
+	if service, ok := customServiceName(port, command); ok {
+		return service
+	}
+
 	// Check known service ports
 	if service, exists := ServiceMap[port]; exists {
 		return service
@@ -404,6 +869,47 @@ func (pm *ProcessManager) detectServiceType(port int, command string) string {
 	}
 }
 
+// topProcesses returns the n processes using the most of the given resource ("cpu" or "memory")
+func topProcesses(processes []Process, n int, by string) []Process {
+	if n <= 0 {
+		n = DefaultTopN
+	}
+
+	top := make([]Process, len(processes))
+	copy(top, processes)
+	sort.SliceStable(top, func(i, j int) bool {
+		if strings.ToLower(by) == "cpu" {
+			return top[i].CPUPercent > top[j].CPUPercent
+		}
+		return top[i].MemoryMB > top[j].MemoryMB
+	})
+	if len(top) > n {
+		top = top[:n]
+	}
+
+	return top
+}
+
+// averagePercent returns the mean of per-core percentages, or 0 if none are available
+func averagePercent(percents []float64) float64 {
+	if len(percents) == 0 {
+		return 0
+	}
+	var total float64
+	for _, p := range percents {
+		total += p
+	}
+	return total / float64(len(percents))
+}
+
+// counterRate returns the per-second rate between two samples of a monotonic counter
+func counterRate(before, after uint64, seconds float64) float64 {
+	if after < before || seconds <= 0 {
+		return 0
+	}
+	return float64(after-before) / seconds
+}
+
 // countUniquePorts counts unique ports from process list
 func (pm *ProcessManager) countUniquePorts(processes []Process) int {
 	ports := make(map[int]bool)
@@ -413,32 +919,181 @@ func (pm *ProcessManager) countUniquePorts(processes []Process) int {
 	return len(ports)
 }
 
+// runEnumeration runs an external enumeration command, bounded by the manager's timeout
+func (pm *ProcessManager) runEnumeration(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if pm.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pm.timeout)
+		defer cancel()
+	}
+
+	// #nosec G204: callers pass fixed command names and integer-derived arguments
+	output, err := exec.CommandContext(ctx, name, args...).Output()
+	if err != nil {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: %s did not finish within %s", ErrEnumerationTimeout, name, pm.timeout)
+		}
+		return nil, fmt.Errorf("failed to execute %s: %w", name, err)
+	}
+
+	return output, nil
+}
+
 // getProcessesUnix gets processes on Unix-like systems
 func (pm *ProcessManager) getProcessesUnix(ctx context.Context, port int) ([]Process, error) {
-	var cmd *exec.Cmd
+	if pm.deep {
+		return pm.getProcessesDeep(ctx, port)
+	}
+
+	// A single port on Linux is answered straight from /proc: no external
+	// tool, and no fd scan at all when nothing uses the port
+	if port != 0 && runtime.GOOS == "linux" {
+		if processes, err := pm.getProcessesProc(ctx, port); err == nil {
+			return processes, nil
+		}
+	}
+
+	var output []byte
+	var err error
 
 	// Try lsof first (more reliable)
-	if _, err := exec.LookPath("lsof"); err == nil {
-		// #nosec G204: port is an integer, not user input
-		cmd = exec.CommandContext(ctx, "lsof", "-i", fmt.Sprintf(":%d", port), "-P", "-n")
+	if _, lookErr := exec.LookPath("lsof"); lookErr == nil {
 		if port == 0 {
-			// #nosec G204: no user input
-			cmd = exec.CommandContext(ctx, "lsof", "-i", "-P", "-n")
+			output, err = pm.runEnumeration(ctx, "lsof", "-i", "-P", "-n")
+		} else {
+			output, err = pm.runEnumeration(ctx, "lsof", "-i", fmt.Sprintf(":%d", port), "-P", "-n")
 		}
-	} else {
+		if isLsofNoMatch(err) {
+			return nil, nil
+		}
+	} else if _, lookErr := exec.LookPath("netstat"); lookErr == nil || runtime.GOOS != "linux" {
 		// Fallback to netstat
-		// #nosec G204: no user input
-		cmd = exec.CommandContext(ctx, "netstat", "-tulpn")
+		output, err = pm.runEnumeration(ctx, "netstat", "-tulpn")
+	} else {
+		// Neither tool is installed (e.g. minimal containers): read /proc directly
+		return pm.getProcessesProc(ctx, port)
 	}
 
-	output, err := cmd.Output()
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute command: %v", err)
+		return nil, err
 	}
 
 	return pm.parseUnixOutput(string(output), port)
 }
 
+// getProcessesDeep runs every available enumeration source and merges the
+// results. It fails only when no source succeeds.
+func (pm *ProcessManager) getProcessesDeep(ctx context.Context, port int) ([]Process, error) {
+	var sources [][]Process
+	var errs []error
+
+	// Most authoritative first: merged fields prefer earlier sources
+	if runtime.GOOS == "linux" {
+		if procs, err := pm.getProcessesProc(ctx, port); err == nil {
+			sources = append(sources, procs)
+		} else {
+			errs = append(errs, fmt.Errorf("/proc: %w", err))
+		}
+	}
+
+	if _, lookErr := exec.LookPath("netstat"); lookErr == nil {
+		output, err := pm.runEnumeration(ctx, "netstat", "-tulpn")
+		if err == nil {
+			procs, _ := pm.parseUnixOutput(string(output), port)
+			sources = append(sources, procs)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	if _, lookErr := exec.LookPath("lsof"); lookErr == nil {
+		args := []string{"-i", "-P", "-n"}
+		if port != 0 {
+			args = []string{"-i", fmt.Sprintf(":%d", port), "-P", "-n"}
+		}
+		output, err := pm.runEnumeration(ctx, "lsof", args...)
+		if err == nil {
+			procs, _ := pm.parseUnixOutput(string(output), port)
+			sources = append(sources, procs)
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(sources) == 0 {
+		if len(errs) == 0 {
+			return nil, errors.New("no enumeration source available (need lsof, netstat or /proc)")
+		}
+		return nil, errors.Join(errs...)
+	}
+
+	return mergeProcesses(sources...), nil
+}
+
+// mergeProcesses combines enumeration results from several sources into one
+// entry per PID, port and protocol. Sources are given most authoritative
+// first; each field takes the first non-empty value, except Command, which
+// prefers the longest name when one source truncated it (lsof cuts names to
+// 9 characters, /proc to 15). The result is sorted by port, then PID.
+func mergeProcesses(sources ...[]Process) []Process {
+	type key struct {
+		pid, port int
+		protocol  string
+	}
+
+	index := make(map[key]int)
+	var merged []Process
+
+	for _, procs := range sources {
+		for _, proc := range procs {
+			// netstat reports "tcp6"/"udp6" where lsof and /proc say "tcp"/"udp"
+			k := key{proc.PID, proc.Port, strings.TrimSuffix(proc.Protocol, "6")}
+			i, ok := index[k]
+			if !ok {
+				index[k] = len(merged)
+				merged = append(merged, proc)
+				continue
+			}
+
+			dst := &merged[i]
+			if len(proc.Command) > len(dst.Command) && strings.HasPrefix(proc.Command, dst.Command) {
+				dst.Command = proc.Command
+			}
+			if dst.Command == "" {
+				dst.Command = proc.Command
+			}
+			if dst.State == "" {
+				dst.State = proc.State
+			}
+			if dst.LocalAddr == "" {
+				dst.LocalAddr = proc.LocalAddr
+			}
+			if dst.RemoteAddr == "" {
+				dst.RemoteAddr = proc.RemoteAddr
+			}
+			if dst.Inode == 0 {
+				dst.Inode = proc.Inode
+			}
+		}
+	}
+
+	sort.SliceStable(merged, func(i, j int) bool {
+		if merged[i].Port != merged[j].Port {
+			return merged[i].Port < merged[j].Port
+		}
+		return merged[i].PID < merged[j].PID
+	})
+
+	return merged
+}
+
+// isLsofNoMatch reports whether lsof failed only because no socket matched;
+// lsof exits with status 1 when it finds nothing to list
+func isLsofNoMatch(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 1
+}
+
 // parseUnixOutput parses output from lsof or netstat
 func (pm *ProcessManager) parseUnixOutput(output string, targetPort int) ([]Process, error) {
 	var processes []Process
@@ -597,10 +1252,9 @@ func (pm *ProcessManager) parseNetstatLine(line string, targetPort int) *Process
 }
 
 func (pm *ProcessManager) getProcessesWindows(ctx context.Context, port int) ([]Process, error) {
-	cmd := exec.CommandContext(ctx, "netstat", "-ano")
-	output, err := cmd.Output()
+	output, err := pm.runEnumeration(ctx, "netstat", "-ano")
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute netstat: %v", err)
+		return nil, err
 	}
 
 	return pm.parseWindowsOutput(ctx, string(output), port)
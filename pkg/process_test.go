@@ -2,7 +2,13 @@ package process
 
 import (
 	"context"
+	"errors"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
 	"testing"
+	"time"
 )
 
 func TestNewProcessManager(t *testing.T) {
@@ -61,6 +67,71 @@ func TestGetProcessesOnPort(t *testing.T) {
 	}
 }
 
+func TestFindAvailablePortsSkipsListeners(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	// The listener's port is itself ephemeral, so allow that range
+	pm := NewProcessManager().WithMetrics(false).WithEphemeral(true)
+	if processes, err := pm.GetProcessesOnPort(context.Background(), port); err != nil || len(processes) == 0 {
+		t.Skipf("listener on port %d is not visible to enumeration (err %v)", port, err)
+	}
+
+	// A saturated range yields a short list rather than an error, which is
+	// what available --strict checks for
+	available, err := pm.FindAvailablePorts(context.Background(), port, port, 1)
+	if err != nil {
+		t.Fatalf("FindAvailablePorts failed: %v", err)
+	}
+	if len(available) != 0 {
+		t.Errorf("Expected no free ports in a range holding only a listener, got %v", available)
+	}
+
+	available, err = pm.FindAvailablePorts(context.Background(), port, port+1, 5)
+	if err != nil {
+		t.Fatalf("FindAvailablePorts failed: %v", err)
+	}
+	for _, p := range available {
+		if p == port {
+			t.Errorf("Port %d is in use but was reported available", port)
+		}
+	}
+}
+
+func TestFindFreeBlock(t *testing.T) {
+	used := map[int]bool{3000: true, 3002: true, 3005: true}
+
+	tests := []struct {
+		name       string
+		start, end int
+		size       int
+		want       int
+	}{
+		{"single port", 3000, 3010, 1, 3001},
+		{"gap of two", 3000, 3010, 2, 3003},
+		{"after last used port", 3000, 3010, 3, 3006},
+		{"block ending at range end", 3000, 3008, 3, 3006},
+		{"range too small", 3000, 3007, 3, -1},
+		{"whole range free", 4000, 4002, 3, 4000},
+	}
+	for _, tt := range tests {
+		if got := findFreeBlock(used, tt.start, tt.end, tt.size); got != tt.want {
+			t.Errorf("%s: findFreeBlock(%d-%d, %d) = %d, want %d", tt.name, tt.start, tt.end, tt.size, got, tt.want)
+		}
+	}
+}
+
+func TestFindAvailableBlockRejectsBadSize(t *testing.T) {
+	pm := NewProcessManager()
+	if _, err := pm.FindAvailableBlock(context.Background(), 3000, 4000, 0); err == nil {
+		t.Error("Expected an error for a zero block size")
+	}
+}
+
 // Test parsing functions with sample data
 func TestParseLsofLine(t *testing.T) {
 	pm := NewProcessManager()
@@ -112,6 +183,148 @@ func TestParseNetstatLine(t *testing.T) {
 	}
 }
 
+func TestGetChildren(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep command not available on Windows")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start child process: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	pm := NewProcessManager()
+	children, err := pm.GetChildren(context.Background(), os.Getpid(), cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	found := false
+	for _, pid := range children[os.Getpid()] {
+		if pid == cmd.Process.Pid {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected child PID %d among children %v", cmd.Process.Pid, children[os.Getpid()])
+	}
+	if len(children[cmd.Process.Pid]) != 0 {
+		t.Errorf("Expected sleep to have no children, got %v", children[cmd.Process.Pid])
+	}
+}
+
+func TestIsLsofNoMatch(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh not available on Windows")
+	}
+
+	pm := NewProcessManager()
+	_, err := pm.runEnumeration(context.Background(), "sh", "-c", "exit 1")
+	if !isLsofNoMatch(err) {
+		t.Errorf("Expected exit status 1 to mean no match, got %v", err)
+	}
+	_, err = pm.runEnumeration(context.Background(), "sh", "-c", "exit 2")
+	if isLsofNoMatch(err) {
+		t.Errorf("Expected exit status 2 to be a real failure")
+	}
+	if isLsofNoMatch(nil) {
+		t.Error("Expected nil error not to be a no-match")
+	}
+}
+
+func TestMergeProcesses(t *testing.T) {
+	pm := NewProcessManager()
+
+	procSockets := []Process{
+		{PID: 100, Port: 5432, Command: "postgres", Protocol: "tcp", State: "LISTEN", LocalAddr: "127.0.0.1:5432"},
+		{PID: 300, Port: 9000, Command: "php-fpm: maste", Protocol: "tcp", State: "LISTEN", LocalAddr: "*:9000"},
+	}
+
+	netstat, _ := pm.parseUnixOutput(`Active Internet connections (only servers)
+Proto Recv-Q Send-Q Local Address           Foreign Address         State       PID/Program name
+tcp        0      0 127.0.0.1:5432          0.0.0.0:*               LISTEN      100/postgres
+tcp6       0      0 :::8080                 :::*                    LISTEN      200/java
+`, 0)
+
+	lsof, _ := pm.parseUnixOutput(`COMMAND     PID USER   FD   TYPE DEVICE SIZE/OFF NODE NAME
+postgres    100 pg      5u  IPv4  12345      0t0  TCP 127.0.0.1:5432 (LISTEN)
+java        200 app     7u  IPv6  12346      0t0  TCP *:8080 (LISTEN)
+php-fpm     300 www     8u  IPv4  12347      0t0  TCP *:9000 (LISTEN)
+nginx       400 www     6u  IPv4  12348      0t0  TCP *:80 (LISTEN)
+`, 0)
+
+	merged := mergeProcesses(procSockets, netstat, lsof)
+
+	want := []struct {
+		pid, port int
+		command   string
+	}{
+		{400, 80, "nginx"},
+		{100, 5432, "postgres"},
+		{200, 8080, "java"},
+		{300, 9000, "php-fpm: maste"},
+	}
+	if len(merged) != len(want) {
+		t.Fatalf("Expected %d merged processes, got %d: %+v", len(want), len(merged), merged)
+	}
+	for i, w := range want {
+		got := merged[i]
+		if got.PID != w.pid || got.Port != w.port || got.Command != w.command {
+			t.Errorf("Entry %d: got PID %d port %d command %q, want PID %d port %d command %q",
+				i, got.PID, got.Port, got.Command, w.pid, w.port, w.command)
+		}
+	}
+
+	// Fields come from the first source that reported the socket
+	if merged[1].LocalAddr != "127.0.0.1:5432" {
+		t.Errorf("Expected /proc local address to win, got %q", merged[1].LocalAddr)
+	}
+	if merged[2].LocalAddr != ":::8080" {
+		t.Errorf("Expected netstat local address for PID 200, got %q", merged[2].LocalAddr)
+	}
+}
+
+func TestMergeProcessesPrefersUntruncatedCommand(t *testing.T) {
+	merged := mergeProcesses(
+		[]Process{{PID: 1, Port: 3000, Protocol: "tcp", Command: "webpack-d"}},
+		[]Process{{PID: 1, Port: 3000, Protocol: "tcp", Command: "webpack-dev-ser", State: "LISTEN"}},
+	)
+
+	if len(merged) != 1 {
+		t.Fatalf("Expected 1 merged process, got %d", len(merged))
+	}
+	if merged[0].Command != "webpack-dev-ser" {
+		t.Errorf("Expected the longer command name, got %q", merged[0].Command)
+	}
+	if merged[0].State != "LISTEN" {
+		t.Errorf("Expected missing state to be filled from the second source, got %q", merged[0].State)
+	}
+}
+
+func TestRunEnumerationTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sleep command not available on Windows")
+	}
+	if _, err := exec.LookPath("sleep"); err != nil {
+		t.Skip("sleep command not available")
+	}
+
+	pm := NewProcessManager().WithTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	_, err := pm.runEnumeration(context.Background(), "sleep", "5")
+	if !errors.Is(err, ErrEnumerationTimeout) {
+		t.Fatalf("Expected ErrEnumerationTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Slow command was not interrupted promptly (took %s)", elapsed)
+	}
+}
+
 // Benchmark tests
 func BenchmarkGetAllProcesses(b *testing.B) {
 	pm := NewProcessManager()
@@ -120,9 +333,295 @@ func BenchmarkGetAllProcesses(b *testing.B) {
 	}
 }
 
+func BenchmarkGetAllProcessesNoMetrics(b *testing.B) {
+	pm := NewProcessManager().WithMetrics(false)
+	for i := 0; i < b.N; i++ {
+		_, _ = pm.GetAllProcesses(context.Background())
+	}
+}
+
+func TestWithMetricsDisabled(t *testing.T) {
+	pm := NewProcessManager().WithMetrics(false)
+	processes := pm.enhanceProcesses(context.Background(), []Process{
+		{PID: 1, Port: 5432, Command: "postgres", LocalAddr: "127.0.0.1:5432"},
+	})
+
+	proc := processes[0]
+	if proc.ServiceType != "PostgreSQL" || proc.BindScope != BindScopeLoopback {
+		t.Errorf("Expected service and bind scope to be classified, got %+v", proc)
+	}
+	if proc.User != "" || proc.FullCommand != "" || !proc.StartTime.IsZero() {
+		t.Errorf("Expected metrics to be skipped, got %+v", proc)
+	}
+}
+
+// syntheticProcesses returns n listeners owned by the test process so that
+// enrichment exercises real gopsutil lookups
+func syntheticProcesses(n int) []Process {
+	processes := make([]Process, n)
+	for i := range processes {
+		processes[i] = Process{PID: os.Getpid(), Port: 10000 + i, Command: "test", LocalAddr: "*:10000"}
+	}
+	return processes
+}
+
+func TestEnhanceProcessesPreservesOrder(t *testing.T) {
+	pm := NewProcessManager()
+	processes := pm.enhanceProcesses(context.Background(), syntheticProcesses(50))
+
+	for i, proc := range processes {
+		if proc.Port != 10000+i {
+			t.Fatalf("Process %d: expected port %d, got %d", i, 10000+i, proc.Port)
+		}
+		if proc.BindScope != BindScopeAll {
+			t.Errorf("Process %d was not enhanced: %+v", i, proc)
+		}
+	}
+}
+
+func TestEnhanceProcessesCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pm := NewProcessManager()
+	processes := pm.enhanceProcesses(ctx, syntheticProcesses(20))
+	for i, proc := range processes {
+		if proc.ServiceType == "" {
+			t.Errorf("Process %d was not classified after cancellation", i)
+		}
+	}
+}
+
+func TestCPUPercentBetween(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after float64
+		elapsed       time.Duration
+		want          float64
+	}{
+		{"idle", 12.5, 12.5, time.Second, 0},
+		{"half a core", 10, 10.5, time.Second, 50},
+		{"two full cores", 10, 10.4, 200 * time.Millisecond, 200},
+		{"pid reused between samples", 50, 1, time.Second, 0},
+		{"no elapsed time", 1, 2, 0, 0},
+	}
+
+	for _, tt := range tests {
+		got := cpuPercentBetween(tt.before, tt.after, tt.elapsed)
+		if got < tt.want-0.001 || got > tt.want+0.001 {
+			t.Errorf("%s: cpuPercentBetween(%v, %v, %s) = %v, want %v",
+				tt.name, tt.before, tt.after, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+// TestCPUPercentIsInstantaneous checks that CPU% reflects current usage: a
+// process that burned CPU in the past but is idle now must report near zero
+func TestCPUPercentIsInstantaneous(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	// Busy-loop briefly, then sleep
+	cmd := exec.Command("sh", "-c", `end=$(($(date +%s) + 1)); while [ "$(date +%s)" -lt "$end" ]; do :; done; sleep 30`)
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	time.Sleep(2500 * time.Millisecond)
+
+	pm := NewProcessManager()
+	processes := pm.enhanceProcesses(context.Background(), []Process{{PID: cmd.Process.Pid, Port: 1}})
+	if cpu := processes[0].CPUPercent; cpu > 10 {
+		t.Errorf("Idle process reported %.1f%% CPU; expected usage over the sample window, not since start", cpu)
+	}
+}
+
+func benchmarkEnhanceProcesses(b *testing.B, workers int) {
+	pm := NewProcessManager()
+	pm.workers = workers
+	for i := 0; i < b.N; i++ {
+		pm.enhanceProcesses(context.Background(), syntheticProcesses(200))
+	}
+}
+
+func BenchmarkEnhanceProcessesSerial(b *testing.B) {
+	benchmarkEnhanceProcesses(b, 1)
+}
+
+func BenchmarkEnhanceProcessesParallel(b *testing.B) {
+	benchmarkEnhanceProcesses(b, enhanceWorkers)
+}
+
 func BenchmarkGetProcessesOnPort(b *testing.B) {
 	pm := NewProcessManager()
 	for i := 0; i < b.N; i++ {
 		_, _ = pm.GetProcessesOnPort(context.Background(), 8080)
 	}
 }
+
+func TestFilterProcessesExcludeService(t *testing.T) {
+	pm := NewProcessManager()
+	processes := []Process{
+		{PID: 1, Port: 3000, Command: "node", ServiceType: "Development"},
+		{PID: 2, Port: 3001, Command: "chrome", ServiceType: "Development"},
+		{PID: 3, Port: 5432, Command: "postgres", ServiceType: "PostgreSQL"},
+	}
+
+	filtered := pm.FilterProcesses(processes, FilterOptions{
+		Service:        "development",
+		ExcludeService: "chrome",
+	})
+	if len(filtered) != 1 || filtered[0].PID != 1 {
+		t.Errorf("Expected only PID 1 after include+exclude, got %+v", filtered)
+	}
+
+	filtered = pm.FilterProcesses(processes, FilterOptions{ExcludeService: "node"})
+	if len(filtered) != 2 {
+		t.Errorf("Expected 2 processes after excluding node, got %d", len(filtered))
+	}
+	for _, proc := range filtered {
+		if proc.Command == "node" {
+			t.Error("Excluded process should not be present")
+		}
+	}
+}
+
+func TestAveragePercent(t *testing.T) {
+	if got := averagePercent(nil); got != 0 {
+		t.Errorf("Expected 0 for no cores, got %f", got)
+	}
+	if got := averagePercent([]float64{10, 20, 30, 40}); got != 25 {
+		t.Errorf("Expected 25, got %f", got)
+	}
+}
+
+func TestCounterRate(t *testing.T) {
+	if got := counterRate(1000, 3000, 2); got != 1000 {
+		t.Errorf("Expected 1000 bytes/sec, got %f", got)
+	}
+	// Counter reset (e.g. interface restarted) should not produce a bogus rate
+	if got := counterRate(3000, 1000, 2); got != 0 {
+		t.Errorf("Expected 0 on counter reset, got %f", got)
+	}
+	if got := counterRate(1000, 3000, 0); got != 0 {
+		t.Errorf("Expected 0 for zero interval, got %f", got)
+	}
+}
+
+func TestTopProcesses(t *testing.T) {
+	processes := []Process{
+		{PID: 1, CPUPercent: 5, MemoryMB: 300},
+		{PID: 2, CPUPercent: 50, MemoryMB: 100},
+		{PID: 3, CPUPercent: 20, MemoryMB: 200},
+	}
+
+	top := topProcesses(processes, 2, "memory")
+	if len(top) != 2 || top[0].PID != 1 || top[1].PID != 3 {
+		t.Errorf("Unexpected top memory users: %+v", top)
+	}
+
+	top = topProcesses(processes, 1, "cpu")
+	if len(top) != 1 || top[0].PID != 2 {
+		t.Errorf("Unexpected top CPU users: %+v", top)
+	}
+
+	if top = topProcesses(processes, 0, ""); len(top) != 3 {
+		t.Errorf("Expected default limit to keep all 3 processes, got %d", len(top))
+	}
+
+	// Input order must be preserved
+	if processes[0].PID != 1 {
+		t.Error("topProcesses should not reorder the input slice")
+	}
+}
+
+func TestMatchesCommand(t *testing.T) {
+	tests := []struct {
+		proc Process
+		name string
+		want bool
+	}{
+		{Process{Command: "node"}, "node", true},
+		{Process{Command: "nodemon"}, "node", false},
+		{Process{Command: "node"}, "nodemon", false},
+		{Process{Command: "Node"}, "node", false},
+		{Process{Command: "node.exe"}, "node", true},
+		{Process{Command: "node"}, "node.exe", true},
+		{Process{Command: "my-long-s", FullCommand: "/usr/local/bin/my-long-server --port 80"}, "my-long-server", true},
+		{Process{Command: "my-long-s", FullCommand: "/usr/local/bin/my-long-server --port 80"}, "my-long", false},
+		{Process{Command: "python3", FullCommand: `C:\Python\python3.exe app.py`}, "python3", true},
+		{Process{Command: "node"}, "", false},
+	}
+	for _, tt := range tests {
+		if got := MatchesCommand(tt.proc, tt.name); got != tt.want {
+			t.Errorf("MatchesCommand(%q/%q, %q) = %v, want %v", tt.proc.Command, tt.proc.FullCommand, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFilterProcessesByCommand(t *testing.T) {
+	pm := NewProcessManager()
+	processes := []Process{
+		{PID: 1, Port: 3000, Command: "node", ServiceType: "Node.js"},
+		{PID: 2, Port: 3001, Command: "nodemon", ServiceType: "Node.js"},
+		{PID: 3, Port: 5432, Command: "postgres", ServiceType: "PostgreSQL"},
+	}
+
+	filtered := pm.FilterProcesses(processes, FilterOptions{Command: "node"})
+	if len(filtered) != 1 || filtered[0].PID != 1 {
+		t.Errorf("Expected only PID 1 for an exact command match, got %+v", filtered)
+	}
+
+	// The substring service filter matches both
+	filtered = pm.FilterProcesses(processes, FilterOptions{Service: "node"})
+	if len(filtered) != 2 {
+		t.Errorf("Expected 2 substring matches for service node, got %d", len(filtered))
+	}
+}
+
+func TestFilterProcessesByPPID(t *testing.T) {
+	pm := NewProcessManager()
+	processes := []Process{
+		{PID: 10, PPID: 1, Port: 3000},
+		{PID: 11, PPID: 10, Port: 3001},
+		{PID: 12, PPID: 10, Port: 3002},
+		{PID: 13, PPID: 11, Port: 3003},
+	}
+
+	filtered := pm.FilterProcesses(processes, FilterOptions{PPID: 10})
+	if len(filtered) != 2 || filtered[0].PID != 11 || filtered[1].PID != 12 {
+		t.Errorf("Expected only the direct children 11 and 12, got %+v", filtered)
+	}
+
+	if filtered = pm.FilterProcesses(processes, FilterOptions{}); len(filtered) != len(processes) {
+		t.Errorf("Expected no PPID filtering by default, got %d of %d", len(filtered), len(processes))
+	}
+}
+
+func TestEnhanceProcessSetsPPID(t *testing.T) {
+	proc := Process{PID: os.Getpid(), Port: 1}
+	NewProcessManager().enhanceProcess(context.Background(), &proc)
+	if proc.PPID != os.Getppid() {
+		t.Errorf("Expected PPID %d, got %d", os.Getppid(), proc.PPID)
+	}
+}
+
+func TestGetProcessByPIDNotFound(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires true command")
+	}
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("Cannot run true: %v", err)
+	}
+
+	_, err := NewProcessManager().GetProcessByPID(context.Background(), cmd.Process.Pid)
+	if !errors.Is(err, ErrProcessNotFound) {
+		t.Errorf("Expected ErrProcessNotFound for an exited PID, got %v", err)
+	}
+}
@@ -0,0 +1,416 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// procRoot is the mount point of the proc filesystem used for socket enumeration
+const procRoot = "/proc"
+
+// procNetFiles maps the socket tables under /proc/net to their protocol
+var procNetFiles = []struct {
+	name     string
+	protocol string
+}{
+	{"tcp", "tcp"},
+	{"tcp6", "tcp"},
+	{"udp", "udp"},
+	{"udp6", "udp"},
+}
+
+// tcpStates maps the hex state codes used in /proc/net/tcp to their names
+var tcpStates = map[string]string{
+	"01": "ESTABLISHED",
+	"02": "SYN_SENT",
+	"03": "SYN_RECV",
+	"04": "FIN_WAIT1",
+	"05": "FIN_WAIT2",
+	"06": "TIME_WAIT",
+	"07": "CLOSE",
+	"08": "CLOSE_WAIT",
+	"09": "LAST_ACK",
+	"0A": "LISTEN",
+	"0B": "CLOSING",
+}
+
+// SocketPollInterval is how often the kernel socket tables are checked for changes
+const SocketPollInterval = 250 * time.Millisecond
+
+// ErrSocketsUnsupported is returned by GetSockets on platforms without
+// readable kernel socket tables
+var ErrSocketsUnsupported = errors.New("listing individual sockets is only supported on Linux")
+
+// ErrSocketEventsUnsupported is returned by WatchSocketTables on platforms
+// without readable kernel socket tables
+var ErrSocketEventsUnsupported = errors.New("socket change events are not supported on this platform")
+
+// procSocket is a single socket entry parsed from /proc/net/{tcp,udp}[6]
+type procSocket struct {
+	Protocol   string
+	LocalIP    net.IP
+	LocalPort  int
+	RemoteIP   net.IP
+	RemotePort int
+	State      string
+	Inode      uint64
+}
+
+// getProcessesProc enumerates sockets by reading /proc directly (Linux only).
+// It needs no external tools and is used for single-port queries, and for
+// full listings when lsof and netstat are unavailable.
+func (pm *ProcessManager) getProcessesProc(ctx context.Context, port int) ([]Process, error) {
+	sockets, err := readProcSockets(procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return socketOwners(ctx, sockets, port)
+}
+
+// GetSockets lists sockets individually, with their inodes, by reading /proc
+// directly even where lsof is available. Unlike the other listings, sockets
+// sharing a port within one process stay separate, and every process holding
+// a socket gets its own entry, so SO_REUSEPORT listeners and sockets inherited
+// by pre-forked workers can be told apart. Port 0 lists every port. Entries
+// are ordered by port, inode and PID.
+func (pm *ProcessManager) GetSockets(ctx context.Context, port int) ([]Process, error) {
+	if runtime.GOOS != "linux" {
+		return nil, ErrSocketsUnsupported
+	}
+
+	var sockets []procSocket
+	var err error
+	if pm.netns != "" {
+		sockets, err = pm.readNetNSSockets(ctx)
+	} else {
+		sockets, err = readProcSockets(procRoot)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	processes, err := socketOwners(ctx, sockets, port)
+	if err != nil {
+		return nil, err
+	}
+	processes = pm.enhanceProcesses(ctx, processes)
+
+	sort.SliceStable(processes, func(i, j int) bool {
+		a, b := processes[i], processes[j]
+		if a.Port != b.Port {
+			return a.Port < b.Port
+		}
+		if a.Inode != b.Inode {
+			return a.Inode < b.Inode
+		}
+		return a.PID < b.PID
+	})
+	return processes, nil
+}
+
+// socketOwners resolves the owning processes of each socket, optionally
+// restricted to a single local port. A socket held by several processes is
+// listed once for each. Sockets without a visible owner are dropped.
+func socketOwners(ctx context.Context, sockets []procSocket, port int) ([]Process, error) {
+	if port != 0 {
+		sockets = slices.DeleteFunc(sockets, func(sock procSocket) bool {
+			return sock.LocalPort != port
+		})
+	}
+	// Scanning every process's descriptors is the expensive part
+	if len(sockets) == 0 {
+		return nil, nil
+	}
+
+	inodes, err := mapSocketInodes(ctx, procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	var processes []Process
+	commands := make(map[int]string)
+	for _, sock := range sockets {
+		// Sockets without a visible owner (e.g. TIME_WAIT or another user's
+		// process) have no PIDs and are skipped
+		for _, pid := range inodes[sock.Inode] {
+			command, ok := commands[pid]
+			if !ok {
+				command = readProcComm(procRoot, pid)
+				commands[pid] = command
+			}
+
+			processes = append(processes, Process{
+				PID:        pid,
+				Port:       sock.LocalPort,
+				Command:    command,
+				Protocol:   sock.Protocol,
+				State:      sock.State,
+				LocalAddr:  formatSocketAddr(sock.LocalIP, sock.LocalPort),
+				RemoteAddr: formatRemoteAddr(sock.RemoteIP, sock.RemotePort),
+				Inode:      sock.Inode,
+			})
+		}
+	}
+
+	return processes, nil
+}
+
+// readProcSockets reads every socket from the /proc/net tables under root
+func readProcSockets(root string) ([]procSocket, error) {
+	var sockets []procSocket
+	for _, f := range procNetFiles {
+		file, err := os.Open(filepath.Join(root, "net", f.name))
+		if err != nil {
+			// tcp6/udp6 are absent when IPv6 is disabled
+			continue
+		}
+		parsed, err := parseProcNet(file, f.protocol)
+		_ = file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s/net/%s: %v", root, f.name, err)
+		}
+		sockets = append(sockets, parsed...)
+	}
+	return sockets, nil
+}
+
+// parseProcNet parses the contents of a /proc/net/{tcp,udp}[6] table
+func parseProcNet(r io.Reader, protocol string) ([]procSocket, error) {
+	var sockets []procSocket
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		// Skip header and malformed lines
+		if len(fields) < 10 || fields[0] == "sl" {
+			continue
+		}
+
+		localIP, localPort, err := parseProcNetAddr(fields[1])
+		if err != nil {
+			continue
+		}
+		remoteIP, remotePort, err := parseProcNetAddr(fields[2])
+		if err != nil {
+			continue
+		}
+
+		inode, err := strconv.ParseUint(fields[9], 10, 64)
+		if err != nil {
+			continue
+		}
+
+		state := tcpStates[strings.ToUpper(fields[3])]
+		if protocol == "udp" {
+			state = "UNCONN"
+			if strings.ToUpper(fields[3]) == "01" {
+				state = "ESTABLISHED"
+			}
+		}
+
+		sockets = append(sockets, procSocket{
+			Protocol:   protocol,
+			LocalIP:    localIP,
+			LocalPort:  localPort,
+			RemoteIP:   remoteIP,
+			RemotePort: remotePort,
+			State:      state,
+			Inode:      inode,
+		})
+	}
+
+	return sockets, scanner.Err()
+}
+
+// parseProcNetAddr decodes an address such as "0100007F:1F90" (127.0.0.1:8080).
+// Addresses are stored as host-endian 32-bit words, which is little-endian on
+// all architectures portctl supports.
+func parseProcNetAddr(s string) (net.IP, int, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, 0, fmt.Errorf("invalid address: %s", s)
+	}
+
+	raw, err := hex.DecodeString(parts[0])
+	if err != nil || (len(raw) != net.IPv4len && len(raw) != net.IPv6len) {
+		return nil, 0, fmt.Errorf("invalid IP: %s", parts[0])
+	}
+
+	// Reverse the byte order within each 32-bit word
+	ip := make(net.IP, len(raw))
+	for i := 0; i < len(raw); i += 4 {
+		ip[i], ip[i+1], ip[i+2], ip[i+3] = raw[i+3], raw[i+2], raw[i+1], raw[i]
+	}
+
+	port, err := strconv.ParseUint(parts[1], 16, 16)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid port: %s", parts[1])
+	}
+
+	return ip, int(port), nil
+}
+
+// mapSocketInodes maps socket inodes to the PIDs holding them, in ascending
+// order, by scanning /proc/<pid>/fd. A PID is listed once per inode even if it
+// holds the socket on several descriptors.
+func mapSocketInodes(ctx context.Context, root string) (map[uint64][]int, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", root, err)
+	}
+
+	inodes := make(map[uint64][]int)
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		fdDir := filepath.Join(root, entry.Name(), "fd")
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			// Permission denied for other users' processes, or the process exited
+			continue
+		}
+
+		for _, fd := range fds {
+			link, err := os.Readlink(filepath.Join(fdDir, fd.Name()))
+			if err != nil || !strings.HasPrefix(link, "socket:[") {
+				continue
+			}
+			inode, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimPrefix(link, "socket:["), "]"), 10, 64)
+			if err != nil {
+				continue
+			}
+			if holders := inodes[inode]; !slices.Contains(holders, pid) {
+				inodes[inode] = append(holders, pid)
+			}
+		}
+	}
+
+	// Directory entries are sorted as names, not numbers
+	for _, pids := range inodes {
+		slices.Sort(pids)
+	}
+	return inodes, nil
+}
+
+// readProcComm returns the command name of a process from /proc/<pid>/comm
+func readProcComm(root string, pid int) string {
+	data, err := os.ReadFile(filepath.Join(root, strconv.Itoa(pid), "comm"))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// formatSocketAddr formats a local address the way lsof does ("*:8080" for wildcard binds)
+func formatSocketAddr(ip net.IP, port int) string {
+	if ip == nil || ip.IsUnspecified() {
+		return fmt.Sprintf("*:%d", port)
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port))
+}
+
+// formatRemoteAddr formats a remote address, returning an empty string for unconnected sockets
+func formatRemoteAddr(ip net.IP, port int) string {
+	if port == 0 && (ip == nil || ip.IsUnspecified()) {
+		return ""
+	}
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port))
+}
+
+// WatchSocketTables signals on the returned channel whenever a socket is opened,
+// closed or changes state. It works by cheaply fingerprinting /proc/net/{tcp,udp}[6]
+// every SocketPollInterval, so consumers only run a full enumeration when something
+// actually changed. Bursts of changes are coalesced into a single signal.
+// The channel is closed when ctx is done.
+func WatchSocketTables(ctx context.Context) (<-chan struct{}, error) {
+	if runtime.GOOS != "linux" {
+		return nil, ErrSocketEventsUnsupported
+	}
+	last, err := socketTableFingerprint(procRoot)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSocketEventsUnsupported, err)
+	}
+
+	events := make(chan struct{}, 1)
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(SocketPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := socketTableFingerprint(procRoot)
+				if err != nil || current == last {
+					continue
+				}
+				last = current
+				select {
+				case events <- struct{}{}:
+				default:
+					// A refresh is already pending
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// socketTableFingerprint hashes the identity and state of every socket in the
+// /proc/net tables under root, ignoring counters such as queue sizes and timers
+func socketTableFingerprint(root string) (uint64, error) {
+	h := fnv.New64a()
+	found := false
+
+	for _, f := range procNetFiles {
+		file, err := os.Open(filepath.Join(root, "net", f.name))
+		if err != nil {
+			continue
+		}
+		found = true
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 10 || fields[0] == "sl" {
+				continue
+			}
+			// local address, remote address, state, inode
+			_, _ = fmt.Fprintf(h, "%s|%s %s %s %s\n", f.name, fields[1], fields[2], fields[3], fields[9])
+		}
+		_ = file.Close()
+		if err := scanner.Err(); err != nil {
+			return 0, err
+		}
+	}
+
+	if !found {
+		return 0, fmt.Errorf("no socket tables found under %s", filepath.Join(root, "net"))
+	}
+	return h.Sum64(), nil
+}
@@ -0,0 +1,281 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sampleProcNetTCP = `  sl  local_address rem_address   st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000:1F90 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 123456 1 0000000000000000 100 0 0 10 0
+   1: 0100007F:0BB8 00000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 123457 1 0000000000000000 100 0 0 10 0
+   2: 0100007F:0BB8 0100007F:D431 01 00000000:00000000 00:00000000 00000000  1000        0 123458 1 0000000000000000 20 4 30 10 -1
+   3: garbage
+`
+
+const sampleProcNetTCP6 = `  sl  local_address                         remote_address                        st tx_queue rx_queue tr tm->when retrnsmt   uid  timeout inode
+   0: 00000000000000000000000000000000:1F91 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 223456 1 0000000000000000 100 0 0 10 0
+   1: 00000000000000000000000001000000:1F92 00000000000000000000000000000000:0000 0A 00000000:00000000 00:00000000 00000000  1000        0 223457 1 0000000000000000 100 0 0 10 0
+`
+
+func TestParseProcNetTCP(t *testing.T) {
+	sockets, err := parseProcNet(strings.NewReader(sampleProcNetTCP), "tcp")
+	if err != nil {
+		t.Fatalf("parseProcNet returned error: %v", err)
+	}
+	if len(sockets) != 3 {
+		t.Fatalf("Expected 3 sockets, got %d", len(sockets))
+	}
+
+	if sockets[0].LocalPort != 8080 || !sockets[0].LocalIP.IsUnspecified() || sockets[0].State != "LISTEN" {
+		t.Errorf("Unexpected wildcard listener: %+v", sockets[0])
+	}
+	if sockets[0].Inode != 123456 {
+		t.Errorf("Expected inode 123456, got %d", sockets[0].Inode)
+	}
+
+	if !sockets[1].LocalIP.Equal(net.IPv4(127, 0, 0, 1)) || sockets[1].LocalPort != 3000 {
+		t.Errorf("Expected 127.0.0.1:3000, got %s:%d", sockets[1].LocalIP, sockets[1].LocalPort)
+	}
+
+	if sockets[2].State != "ESTABLISHED" || sockets[2].RemotePort != 54321 {
+		t.Errorf("Unexpected established socket: %+v", sockets[2])
+	}
+}
+
+func TestParseProcNetTCP6(t *testing.T) {
+	sockets, err := parseProcNet(strings.NewReader(sampleProcNetTCP6), "tcp")
+	if err != nil {
+		t.Fatalf("parseProcNet returned error: %v", err)
+	}
+	if len(sockets) != 2 {
+		t.Fatalf("Expected 2 sockets, got %d", len(sockets))
+	}
+
+	if !sockets[0].LocalIP.IsUnspecified() || sockets[0].LocalPort != 8081 {
+		t.Errorf("Expected [::]:8081, got %s:%d", sockets[0].LocalIP, sockets[0].LocalPort)
+	}
+	if !sockets[1].LocalIP.Equal(net.IPv6loopback) || sockets[1].LocalPort != 8082 {
+		t.Errorf("Expected [::1]:8082, got %s:%d", sockets[1].LocalIP, sockets[1].LocalPort)
+	}
+}
+
+func TestParseProcNetAddrInvalid(t *testing.T) {
+	for _, addr := range []string{"", "0100007F", "XYZ:1F90", "0100007F:ZZZZ", "01007F:1F90"} {
+		if _, _, err := parseProcNetAddr(addr); err == nil {
+			t.Errorf("Expected error for %q", addr)
+		}
+	}
+}
+
+func TestFormatSocketAddr(t *testing.T) {
+	if got := formatSocketAddr(net.IPv4zero, 8080); got != "*:8080" {
+		t.Errorf("Expected *:8080, got %s", got)
+	}
+	if got := formatSocketAddr(net.IPv6loopback, 8080); got != "[::1]:8080" {
+		t.Errorf("Expected [::1]:8080, got %s", got)
+	}
+	if got := formatRemoteAddr(net.IPv4zero, 0); got != "" {
+		t.Errorf("Expected empty remote address, got %s", got)
+	}
+}
+
+func TestGetProcessesProc(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc enumeration is Linux-only")
+	}
+
+	pm := NewProcessManager()
+	processes, err := pm.getProcessesProc(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("getProcessesProc returned error: %v", err)
+	}
+
+	for _, proc := range processes {
+		if proc.PID <= 0 || proc.Port < 0 || proc.Command == "" {
+			t.Errorf("Invalid process from /proc: %+v", proc)
+		}
+	}
+}
+
+func TestSocketTableFingerprint(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "net"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	tcpPath := filepath.Join(root, "net", "tcp")
+
+	write := func(content string) uint64 {
+		t.Helper()
+		if err := os.WriteFile(tcpPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		fp, err := socketTableFingerprint(root)
+		if err != nil {
+			t.Fatalf("socketTableFingerprint returned error: %v", err)
+		}
+		return fp
+	}
+
+	base := write(sampleProcNetTCP)
+
+	// Queue sizes and timers change constantly and must not count as a change
+	noisy := strings.Replace(sampleProcNetTCP, "00000000:00000000 00:00000000", "00000010:00000020 02:000000AA", 1)
+	if write(noisy) != base {
+		t.Error("Counter-only changes should not alter the fingerprint")
+	}
+
+	// A listener closing is a real change
+	closed := strings.Replace(sampleProcNetTCP, "0A 00000000:00000000", "07 00000000:00000000", 1)
+	if write(closed) == base {
+		t.Error("State changes should alter the fingerprint")
+	}
+
+	if _, err := socketTableFingerprint(t.TempDir()); err == nil {
+		t.Error("Expected an error when no socket tables exist")
+	}
+}
+
+func TestMapSocketInodesSharedSockets(t *testing.T) {
+	root := t.TempDir()
+	links := map[string]string{
+		"123/fd/3":  "socket:[555]",
+		"123/fd/4":  "socket:[555]", // Same socket on a second descriptor
+		"45/fd/5":   "socket:[555]", // Inherited by another process
+		"45/fd/6":   "socket:[777]",
+		"45/fd/7":   "/dev/null",
+		"self/fd/8": "socket:[999]", // Not a PID
+	}
+	for name, target := range links {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("cannot create symlinks: %v", err)
+		}
+	}
+
+	inodes, err := mapSocketInodes(context.Background(), root)
+	if err != nil {
+		t.Fatalf("mapSocketInodes: %v", err)
+	}
+	want := map[uint64][]int{555: {45, 123}, 777: {45}}
+	if len(inodes) != len(want) {
+		t.Fatalf("mapSocketInodes = %v, want %v", inodes, want)
+	}
+	for inode, pids := range want {
+		if !slices.Equal(inodes[inode], pids) {
+			t.Errorf("inode %d held by %v, want %v", inode, inodes[inode], pids)
+		}
+	}
+}
+
+func TestGetSockets(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		if _, err := NewProcessManager().GetSockets(context.Background(), 0); !errors.Is(err, ErrSocketsUnsupported) {
+			t.Errorf("GetSockets error = %v, want ErrSocketsUnsupported", err)
+		}
+		return
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	port := ln.Addr().(*net.TCPAddr).Port
+
+	sockets, err := NewProcessManager().WithMetrics(false).GetSockets(context.Background(), port)
+	if err != nil {
+		t.Fatalf("GetSockets: %v", err)
+	}
+	for _, sock := range sockets {
+		if sock.PID == os.Getpid() && sock.State == "LISTEN" {
+			if sock.Inode == 0 {
+				t.Errorf("listener has no inode: %+v", sock)
+			}
+			return
+		}
+	}
+	t.Errorf("GetSockets(%d) = %+v, want this test's listener", port, sockets)
+}
+
+func TestGetProcessesProcSinglePort(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc enumeration is Linux-only")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	pm := NewProcessManager()
+	processes, err := pm.getProcessesProc(context.Background(), port)
+	if err != nil {
+		t.Fatalf("getProcessesProc returned error: %v", err)
+	}
+	found := false
+	for _, proc := range processes {
+		if proc.Port != port {
+			t.Errorf("got process on port %d, want only %d: %+v", proc.Port, port, proc)
+		}
+		if proc.PID == os.Getpid() && proc.State == "LISTEN" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("test listener on port %d not found in %+v", port, processes)
+	}
+}
+
+// benchmarkSinglePort measures looking up the owner of one listening port,
+// without the metrics enrichment both paths share
+func benchmarkSinglePort(b *testing.B, lookup func(pm *ProcessManager, port int) ([]Process, error)) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Skipf("cannot listen: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	pm := NewProcessManager()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := lookup(pm, port); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSinglePortProc(b *testing.B) {
+	if runtime.GOOS != "linux" {
+		b.Skip("/proc enumeration is Linux-only")
+	}
+	benchmarkSinglePort(b, func(pm *ProcessManager, port int) ([]Process, error) {
+		return pm.getProcessesProc(context.Background(), port)
+	})
+}
+
+func BenchmarkSinglePortLsof(b *testing.B) {
+	if _, err := exec.LookPath("lsof"); err != nil {
+		b.Skip("lsof not installed")
+	}
+	benchmarkSinglePort(b, func(pm *ProcessManager, port int) ([]Process, error) {
+		output, err := pm.runEnumeration(context.Background(), "lsof", "-i", fmt.Sprintf(":%d", port), "-P", "-n")
+		if err != nil {
+			return nil, err
+		}
+		return pm.parseUnixOutput(string(output), port)
+	})
+}
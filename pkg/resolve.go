@@ -0,0 +1,210 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultResolveTimeout bounds each reverse-DNS or ASN lookup
+const DefaultResolveTimeout = time.Second
+
+// resolveWorkers bounds how many addresses are looked up concurrently
+const resolveWorkers = 16
+
+// RemoteInfo describes who is on the other end of a connection
+type RemoteInfo struct {
+	Host    string `json:"host,omitempty"`    // Reverse-DNS name
+	ASN     string `json:"asn,omitempty"`     // e.g. "AS13335"
+	Owner   string `json:"owner,omitempty"`   // AS name, e.g. "CLOUDFLARENET - Cloudflare, Inc."
+	Country string `json:"country,omitempty"` // ISO country code of the announcing network
+}
+
+// Resolver looks up names and network owners for remote addresses. Every
+// lookup is bounded by a timeout, and results, including failures, are
+// cached so each address is queried at most once. It is safe for concurrent use.
+type Resolver struct {
+	timeout time.Duration
+	asn     bool
+
+	mu    sync.Mutex
+	cache map[string]RemoteInfo
+
+	lookupAddr func(ctx context.Context, addr string) ([]string, error)
+	lookupTXT  func(ctx context.Context, name string) ([]string, error)
+}
+
+// NewResolver creates a Resolver that gives up on each lookup after timeout
+func NewResolver(timeout time.Duration) *Resolver {
+	return &Resolver{
+		timeout:    timeout,
+		cache:      make(map[string]RemoteInfo),
+		lookupAddr: net.DefaultResolver.LookupAddr,
+		lookupTXT:  net.DefaultResolver.LookupTXT,
+	}
+}
+
+// WithASN also looks up the autonomous system announcing each public address,
+// using the Team Cymru IP-to-ASN DNS service. This sends the addresses to a
+// third-party DNS zone, so it is opt-in.
+func (r *Resolver) WithASN(enabled bool) *Resolver {
+	r.asn = enabled
+	return r
+}
+
+// Lookup returns what is known about ip. Failed lookups yield empty fields.
+func (r *Resolver) Lookup(ctx context.Context, ip string) RemoteInfo {
+	r.mu.Lock()
+	info, ok := r.cache[ip]
+	r.mu.Unlock()
+	if ok {
+		return info
+	}
+
+	info = r.lookup(ctx, ip)
+
+	r.mu.Lock()
+	r.cache[ip] = info
+	r.mu.Unlock()
+	return info
+}
+
+// lookup performs the uncached queries for ip
+func (r *Resolver) lookup(ctx context.Context, ip string) RemoteInfo {
+	var info RemoteInfo
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return info
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, r.timeout)
+	names, err := r.lookupAddr(lookupCtx, ip)
+	cancel()
+	if err == nil && len(names) > 0 {
+		info.Host = strings.TrimSuffix(names[0], ".")
+	}
+
+	if r.asn && isPublicIP(parsed) {
+		lookupCtx, cancel := context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+		r.lookupOwner(lookupCtx, parsed, &info)
+	}
+	return info
+}
+
+// lookupOwner fills in the ASN, owner and country of ip from Team Cymru
+func (r *Resolver) lookupOwner(ctx context.Context, ip net.IP, info *RemoteInfo) {
+	records, err := r.lookupTXT(ctx, cymruOriginName(ip))
+	if err != nil || len(records) == 0 {
+		return
+	}
+	// "13335 | 1.1.1.0/24 | AU | apnic | 2011-08-11"
+	fields := splitCymruRecord(records[0])
+	if len(fields) < 3 || fields[0] == "" {
+		return
+	}
+	// Multi-origin prefixes list several ASNs separated by spaces; use the first
+	asn := strings.Fields(fields[0])[0]
+	info.ASN = "AS" + asn
+	info.Country = fields[2]
+
+	records, err = r.lookupTXT(ctx, fmt.Sprintf("AS%s.asn.cymru.com", asn))
+	if err != nil || len(records) == 0 {
+		return
+	}
+	// "13335 | US | arin | 2010-07-14 | CLOUDFLARENET - Cloudflare, Inc., US"
+	if fields := splitCymruRecord(records[0]); len(fields) >= 5 {
+		info.Owner = fields[4]
+	}
+}
+
+// ResolveProcesses fills in RemoteHost and RemoteOwner for every process with
+// a remote address, looking up each distinct IP once and concurrently
+func (r *Resolver) ResolveProcesses(ctx context.Context, processes []Process) {
+	indexes := make(map[string][]int)
+	for i, proc := range processes {
+		if ip := remoteIP(proc.RemoteAddr); ip != "" {
+			indexes[ip] = append(indexes[ip], i)
+		}
+	}
+
+	// Each IP owns a disjoint set of indexes, so workers never write the same element
+	ips := make(chan string)
+	var wg sync.WaitGroup
+	for w := 0; w < min(resolveWorkers, len(indexes)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ip := range ips {
+				info := r.Lookup(ctx, ip)
+				for _, i := range indexes[ip] {
+					processes[i].RemoteHost = info.Host
+					processes[i].RemoteOwner = info.describeOwner()
+				}
+			}
+		}()
+	}
+	for ip := range indexes {
+		ips <- ip
+	}
+	close(ips)
+	wg.Wait()
+}
+
+// describeOwner formats the network owner as "AS13335 CLOUDFLARENET - Cloudflare, Inc. (US)"
+func (info RemoteInfo) describeOwner() string {
+	if info.ASN == "" {
+		return ""
+	}
+	owner := info.ASN
+	if info.Owner != "" {
+		owner += " " + info.Owner
+	}
+	if info.Country != "" {
+		owner += " (" + info.Country + ")"
+	}
+	return owner
+}
+
+// remoteIP extracts the IP from a remote address such as "1.2.3.4:443" or
+// "[2001:db8::1]:443", returning "" for empty or wildcard addresses
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil || host == "*" || net.ParseIP(host) == nil {
+		return ""
+	}
+	return host
+}
+
+// isPublicIP reports whether ip is globally routable, so worth an ASN lookup
+func isPublicIP(ip net.IP) bool {
+	return ip.IsGlobalUnicast() && !ip.IsPrivate()
+}
+
+// cymruOriginName returns the Team Cymru origin query name for ip: reversed
+// octets under origin.asn.cymru.com for IPv4, reversed nibbles under
+// origin6.asn.cymru.com for IPv6
+func cymruOriginName(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.origin.asn.cymru.com", v4[3], v4[2], v4[1], v4[0])
+	}
+
+	v6 := ip.To16()
+	nibbles := make([]string, 0, 32)
+	for i := len(v6) - 1; i >= 0; i-- {
+		nibbles = append(nibbles, fmt.Sprintf("%x", v6[i]&0x0f), fmt.Sprintf("%x", v6[i]>>4))
+	}
+	return strings.Join(nibbles, ".") + ".origin6.asn.cymru.com"
+}
+
+// splitCymruRecord splits a "|"-separated Team Cymru TXT record into trimmed fields
+func splitCymruRecord(record string) []string {
+	fields := strings.Split(record, "|")
+	for i := range fields {
+		fields[i] = strings.TrimSpace(fields[i])
+	}
+	return fields
+}
@@ -0,0 +1,123 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeResolver returns a Resolver whose lookups are answered from tables
+func fakeResolver(names map[string]string, txt map[string]string, calls *atomic.Int32) *Resolver {
+	r := NewResolver(50 * time.Millisecond)
+	r.lookupAddr = func(ctx context.Context, addr string) ([]string, error) {
+		calls.Add(1)
+		if name, ok := names[addr]; ok {
+			return []string{name}, nil
+		}
+		<-ctx.Done() // Unresolvable addresses hang until the timeout
+		return nil, ctx.Err()
+	}
+	r.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		if record, ok := txt[name]; ok {
+			return []string{record}, nil
+		}
+		return nil, errors.New("no such host")
+	}
+	return r
+}
+
+func TestResolveProcesses(t *testing.T) {
+	var calls atomic.Int32
+	r := fakeResolver(map[string]string{
+		"1.1.1.1": "one.one.one.one.",
+	}, map[string]string{
+		"1.1.1.1.origin.asn.cymru.com": "13335 | 1.1.1.0/24 | AU | apnic | 2011-08-11",
+		"AS13335.asn.cymru.com":        "13335 | US | arin | 2010-07-14 | CLOUDFLARENET - Cloudflare, Inc., US",
+	}, &calls).WithASN(true)
+
+	processes := []Process{
+		{PID: 1, RemoteAddr: "1.1.1.1:443"},
+		{PID: 2, RemoteAddr: "1.1.1.1:853"},
+		{PID: 3, RemoteAddr: "203.0.113.9:22"},
+		{PID: 4, RemoteAddr: ""},
+		{PID: 5, RemoteAddr: "*:*"},
+	}
+
+	start := time.Now()
+	r.ResolveProcesses(context.Background(), processes)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Unresolvable address blocked for %v despite the timeout", elapsed)
+	}
+
+	if processes[0].RemoteHost != "one.one.one.one" || processes[1].RemoteHost != "one.one.one.one" {
+		t.Errorf("Expected both 1.1.1.1 connections to resolve, got %q and %q", processes[0].RemoteHost, processes[1].RemoteHost)
+	}
+	if want := "AS13335 CLOUDFLARENET - Cloudflare, Inc., US (AU)"; processes[0].RemoteOwner != want {
+		t.Errorf("RemoteOwner = %q, want %q", processes[0].RemoteOwner, want)
+	}
+	if processes[2].RemoteHost != "" || processes[2].RemoteOwner != "" {
+		t.Errorf("Expected the unresolvable address to stay bare, got %+v", processes[2])
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected one reverse lookup per distinct IP (2), got %d", got)
+	}
+
+	// Failures are cached too
+	r.ResolveProcesses(context.Background(), processes)
+	if got := calls.Load(); got != 2 {
+		t.Errorf("Expected cached results on the second pass, got %d lookups", got)
+	}
+}
+
+func TestResolverSkipsASNForPrivateAddresses(t *testing.T) {
+	var calls atomic.Int32
+	r := fakeResolver(map[string]string{"10.0.0.5": "db.internal."}, nil, &calls).WithASN(true)
+
+	var txtCalls int
+	r.lookupTXT = func(ctx context.Context, name string) ([]string, error) {
+		txtCalls++
+		return nil, errors.New("unexpected")
+	}
+
+	info := r.Lookup(context.Background(), "10.0.0.5")
+	if info.Host != "db.internal" {
+		t.Errorf("Host = %q, want db.internal", info.Host)
+	}
+	if txtCalls != 0 {
+		t.Errorf("Expected no ASN lookup for a private address, got %d", txtCalls)
+	}
+}
+
+func TestCymruOriginName(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"1.2.3.4", "4.3.2.1.origin.asn.cymru.com"},
+		{"2001:db8::1", "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.origin6.asn.cymru.com"},
+	}
+	for _, tt := range tests {
+		if got := cymruOriginName(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("cymruOriginName(%s) = %s, want %s", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestRemoteIP(t *testing.T) {
+	tests := map[string]string{
+		"1.2.3.4:443":           "1.2.3.4",
+		"[2001:db8::1]:443":     "2001:db8::1",
+		"":                      "",
+		"*:*":                   "",
+		"example.com:80":        "",
+		"not an address at all": "",
+	}
+	for addr, want := range tests {
+		if got := remoteIP(addr); got != want {
+			t.Errorf("remoteIP(%q) = %q, want %q", addr, got, want)
+		}
+	}
+}
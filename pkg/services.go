@@ -0,0 +1,117 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ServiceDefinitions are user-supplied service names that extend the built-in
+// ServiceMap and command patterns. A definitions file looks like:
+//
+//	ports:
+//	  7777: BillingService
+//	  3000: Storefront        # overrides the built-in "React/Node"
+//	commands:
+//	  - pattern: "billing-(api|worker)"
+//	    service: BillingService
+type ServiceDefinitions struct {
+	Ports    map[int]string `yaml:"ports"`
+	Commands []CommandRule  `yaml:"commands"`
+}
+
+// CommandRule names the service of any process whose command matches Pattern,
+// a case-insensitive regular expression
+type CommandRule struct {
+	Pattern string `yaml:"pattern"`
+	Service string `yaml:"service"`
+
+	re *regexp.Regexp
+}
+
+// customServices holds the definitions installed with SetServiceDefinitions
+var customServices struct {
+	sync.RWMutex
+	defs *ServiceDefinitions
+}
+
+// LoadServiceDefinitions reads and validates a YAML service definitions file
+func LoadServiceDefinitions(path string) (*ServiceDefinitions, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServiceDefinitions(data)
+}
+
+// ParseServiceDefinitions parses and validates YAML service definitions
+func ParseServiceDefinitions(data []byte) (*ServiceDefinitions, error) {
+	var defs ServiceDefinitions
+	if err := yaml.Unmarshal(data, &defs); err != nil {
+		return nil, fmt.Errorf("invalid service definitions: %v", err)
+	}
+
+	ports := make([]int, 0, len(defs.Ports))
+	for port := range defs.Ports {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	for _, port := range ports {
+		if port < MinPort || port > MaxPort {
+			return nil, fmt.Errorf("invalid port %d in service definitions", port)
+		}
+		if defs.Ports[port] == "" {
+			return nil, fmt.Errorf("empty service name for port %d", port)
+		}
+	}
+
+	for i := range defs.Commands {
+		rule := &defs.Commands[i]
+		if rule.Pattern == "" || rule.Service == "" {
+			return nil, fmt.Errorf("command rule %d needs both a pattern and a service", i+1)
+		}
+		re, err := regexp.Compile("(?i)" + rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q in command rule %d: %v", rule.Pattern, i+1, err)
+		}
+		rule.re = re
+	}
+
+	return &defs, nil
+}
+
+// SetServiceDefinitions installs custom definitions used by service detection.
+// Custom port names take precedence over custom command rules, which take
+// precedence over all built-in detection. Passing nil removes them.
+func SetServiceDefinitions(defs *ServiceDefinitions) {
+	customServices.Lock()
+	defer customServices.Unlock()
+	customServices.defs = defs
+}
+
+// customServiceName returns the custom service for a port or command, if any
+func customServiceName(port int, command string) (string, bool) {
+	customServices.RLock()
+	defer customServices.RUnlock()
+
+	defs := customServices.defs
+	if defs == nil {
+		return "", false
+	}
+	if name, ok := defs.Ports[port]; ok {
+		return name, true
+	}
+	if command == "" {
+		return "", false
+	}
+	for _, rule := range defs.Commands {
+		if rule.re.MatchString(command) {
+			return rule.Service, true
+		}
+	}
+	return "", false
+}
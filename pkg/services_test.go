@@ -0,0 +1,120 @@
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testServiceDefinitions = `
+ports:
+  7777: BillingService
+  5432: CompanyPostgres
+commands:
+  - pattern: "billing-(api|worker)"
+    service: BillingWorker
+  - pattern: "^node"
+    service: Frontend
+`
+
+func TestParseServiceDefinitions(t *testing.T) {
+	defs, err := ParseServiceDefinitions([]byte(testServiceDefinitions))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if defs.Ports[7777] != "BillingService" {
+		t.Errorf("Expected port 7777 to be BillingService, got %q", defs.Ports[7777])
+	}
+	if len(defs.Commands) != 2 {
+		t.Errorf("Expected 2 command rules, got %d", len(defs.Commands))
+	}
+}
+
+func TestParseServiceDefinitionsInvalid(t *testing.T) {
+	invalid := []string{
+		"ports: [1, 2",
+		"ports:\n  70000: TooHigh\n",
+		"ports:\n  8080: \"\"\n",
+		"commands:\n  - pattern: \"(\"\n    service: Broken\n",
+		"commands:\n  - pattern: foo\n",
+	}
+
+	for _, data := range invalid {
+		if _, err := ParseServiceDefinitions([]byte(data)); err == nil {
+			t.Errorf("ParseServiceDefinitions(%q) expected error, got nil", data)
+		}
+	}
+}
+
+func TestLoadServiceDefinitions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "services.yaml")
+	if err := os.WriteFile(path, []byte(testServiceDefinitions), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	defs, err := LoadServiceDefinitions(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if defs.Ports[5432] != "CompanyPostgres" {
+		t.Errorf("Expected port 5432 to be CompanyPostgres, got %q", defs.Ports[5432])
+	}
+
+	if _, err := LoadServiceDefinitions(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+}
+
+func TestServiceDefinitionPrecedence(t *testing.T) {
+	defs, err := ParseServiceDefinitions([]byte(testServiceDefinitions))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	SetServiceDefinitions(defs)
+	defer SetServiceDefinitions(nil)
+
+	pm := NewProcessManager()
+	tests := []struct {
+		name    string
+		port    int
+		command string
+		want    string
+	}{
+		{"custom port", 7777, "java", "BillingService"},
+		{"custom port overrides built-in port", 5432, "postgres", "CompanyPostgres"},
+		{"custom port beats custom command", 7777, "billing-api", "BillingService"},
+		{"custom command matches", 4100, "billing-worker", "BillingWorker"},
+		{"custom command is case-insensitive", 4100, "Billing-API", "BillingWorker"},
+		{"custom command overrides built-in port", 3000, "node", "Frontend"},
+		{"custom command overrides built-in command", 4100, "node server.js", "Frontend"},
+		{"built-in port still applies", 6379, "redis-server", "Redis"},
+		{"built-in command still applies", 4100, "python3", "Python"},
+		{"port range still applies", 8500, "unknownd", "Development"},
+	}
+
+	for _, tt := range tests {
+		if got := pm.detectServiceType(tt.port, tt.command); got != tt.want {
+			t.Errorf("%s: detectServiceType(%d, %q) = %q, want %q", tt.name, tt.port, tt.command, got, tt.want)
+		}
+	}
+
+	if got := GetServiceName(5432); got != "CompanyPostgres" {
+		t.Errorf("GetServiceName(5432) = %q, want CompanyPostgres", got)
+	}
+	if got := GetServiceName(443); got != "HTTPS" {
+		t.Errorf("GetServiceName(443) = %q, want HTTPS", got)
+	}
+}
+
+func TestServiceDefinitionsCleared(t *testing.T) {
+	defs, err := ParseServiceDefinitions([]byte(testServiceDefinitions))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	SetServiceDefinitions(defs)
+	SetServiceDefinitions(nil)
+
+	if got := GetServiceName(7777); got != "Unknown" {
+		t.Errorf("GetServiceName(7777) after clearing = %q, want Unknown", got)
+	}
+}
@@ -0,0 +1,157 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrStillRunning is returned when a process has not exited by the end of a
+// verification window
+var ErrStillRunning = errors.New("process still running")
+
+// verifyPollInterval is how often VerifyExited probes a signalled process
+const verifyPollInterval = 50 * time.Millisecond
+
+// How a signal is delivered on the current platform
+const (
+	SignalNative      = "native"      // Sent as a real signal
+	SignalEmulated    = "emulated"    // Approximated, e.g. with taskkill on Windows
+	SignalUnsupported = "unsupported" // Cannot be sent on this platform
+)
+
+// SignalInfo describes a signal portctl can name
+type SignalInfo struct {
+	Name        string `json:"name"` // e.g. "SIGTERM"
+	Number      int    `json:"number"`
+	Description string `json:"description"`
+	Support     string `json:"support"` // SignalNative, SignalEmulated or SignalUnsupported
+}
+
+// Signals returns the signals portctl knows about, in numeric order, with
+// their numbers and support level on the current platform
+func Signals() []SignalInfo {
+	// Numbers differ between Linux and macOS, so sort rather than trust the table order
+	signals := append([]SignalInfo(nil), signalTable...)
+	sort.Slice(signals, func(i, j int) bool { return signals[i].Number < signals[j].Number })
+	return signals
+}
+
+// ParseSignal parses a signal name ("TERM", "SIGTERM", case-insensitive) or
+// number ("15"). Signals that cannot be sent on this platform are rejected.
+func ParseSignal(name string) (syscall.Signal, error) {
+	s := strings.ToUpper(strings.TrimSpace(name))
+	if s == "" {
+		return 0, fmt.Errorf("empty signal name")
+	}
+
+	n, err := strconv.Atoi(s)
+	isNumber := err == nil
+	if !isNumber && !strings.HasPrefix(s, "SIG") {
+		s = "SIG" + s
+	}
+
+	for _, info := range signalTable {
+		if (isNumber && info.Number == n) || (!isNumber && info.Name == s) {
+			if info.Support == SignalUnsupported {
+				return 0, fmt.Errorf("%s is not supported on %s", info.Name, runtime.GOOS)
+			}
+			return syscall.Signal(info.Number), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown signal %q (run 'portctl signals' for the list)", name)
+}
+
+// SignalName returns the name of sig, e.g. "SIGTERM", or its number if unknown
+func SignalName(sig syscall.Signal) string {
+	for _, info := range signalTable {
+		if info.Number == int(sig) {
+			return info.Name
+		}
+	}
+	return strconv.Itoa(int(sig))
+}
+
+// IsTerminatingSignal reports whether sig asks a process to exit (TERM, INT,
+// QUIT or KILL), so that waiting for the exit, as VerifyExited does, makes
+// sense. Signals such as HUP or USR1 are handled by a running process.
+func IsTerminatingSignal(sig syscall.Signal) bool {
+	switch sig {
+	case syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL:
+		return true
+	}
+	return false
+}
+
+// SignalProcess sends sig to a process. On Windows only SIGKILL, SIGTERM and
+// SIGINT can be sent; they are emulated with taskkill.
+func (pm *ProcessManager) SignalProcess(ctx context.Context, pid int, sig syscall.Signal) error {
+	// A cached snapshot would still list the process
+	if cache := pm.cache.Load(); cache != nil {
+		defer cache.invalidate()
+	}
+
+	if pid <= 0 {
+		return fmt.Errorf("invalid PID: %d", pid)
+	}
+	return sendSignal(ctx, pid, sig)
+}
+
+// VerifyExited waits up to timeout for pid to exit, so that a kill can be
+// reported as done only once the process is gone rather than as soon as the
+// signal was delivered. It returns an error wrapping ErrStillRunning if the
+// process outlives the window.
+func (pm *ProcessManager) VerifyExited(ctx context.Context, pid int, timeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if err := WaitForExit(waitCtx, pid, verifyPollInterval); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if waitCtx.Err() != nil {
+			return fmt.Errorf("process %d did not exit within %s: %w", pid, timeout, ErrStillRunning)
+		}
+		return err
+	}
+	return nil
+}
+
+// SignalProcessesVerified sends sig to every PID and then verifies, in
+// parallel, that each one exited within timeout. A PID maps to an error if
+// the signal failed or the process is still running.
+func (pm *ProcessManager) SignalProcessesVerified(ctx context.Context, pids []int, sig syscall.Signal, timeout time.Duration) map[int]error {
+	results := pm.SignalProcesses(ctx, pids, sig)
+
+	// Collected first: the goroutines write to results, so it must not be
+	// ranged over while they run
+	var signalled []int
+	for pid, err := range results {
+		if err == nil {
+			signalled = append(signalled, pid)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, pid := range signalled {
+		wg.Add(1)
+		go func(pid int) {
+			defer wg.Done()
+			err := pm.VerifyExited(ctx, pid, timeout)
+			mu.Lock()
+			results[pid] = err
+			mu.Unlock()
+		}(pid)
+	}
+	wg.Wait()
+
+	return results
+}
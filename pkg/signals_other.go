@@ -0,0 +1,40 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+var signalTable = []SignalInfo{
+	{"SIGHUP", int(syscall.SIGHUP), "Hangup; many daemons reload their configuration", SignalNative},
+	{"SIGINT", int(syscall.SIGINT), "Interrupt, as sent by Ctrl+C", SignalNative},
+	{"SIGQUIT", int(syscall.SIGQUIT), "Quit and dump core", SignalNative},
+	{"SIGKILL", int(syscall.SIGKILL), "Kill immediately; cannot be caught or ignored", SignalNative},
+	{"SIGUSR1", int(syscall.SIGUSR1), "User-defined signal 1", SignalNative},
+	{"SIGUSR2", int(syscall.SIGUSR2), "User-defined signal 2", SignalNative},
+	{"SIGTERM", int(syscall.SIGTERM), "Terminate gracefully (the kill default)", SignalNative},
+	{"SIGCONT", int(syscall.SIGCONT), "Continue a stopped process", SignalNative},
+	{"SIGSTOP", int(syscall.SIGSTOP), "Stop the process; cannot be caught or ignored", SignalNative},
+	{"SIGTSTP", int(syscall.SIGTSTP), "Terminal stop, as sent by Ctrl+Z", SignalNative},
+}
+
+// processAlive probes pid with signal 0, which checks that the process exists
+// without affecting it. EPERM means it exists but belongs to another user.
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
+
+// sendSignal delivers sig to pid
+func sendSignal(ctx context.Context, pid int, sig syscall.Signal) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("failed to find process %d: %v", pid, err)
+	}
+	return process.Signal(sig)
+}
@@ -0,0 +1,168 @@
+package process
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestParseSignal(t *testing.T) {
+	tests := []struct {
+		in   string
+		want syscall.Signal
+	}{
+		{"TERM", syscall.SIGTERM},
+		{"SIGTERM", syscall.SIGTERM},
+		{"sigterm", syscall.SIGTERM},
+		{" kill ", syscall.SIGKILL},
+		{"15", syscall.SIGTERM},
+		{"9", syscall.SIGKILL},
+		{"int", syscall.SIGINT},
+	}
+	for _, tt := range tests {
+		got, err := ParseSignal(tt.in)
+		if err != nil {
+			t.Errorf("ParseSignal(%q) unexpected error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseSignal(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseSignalInvalid(t *testing.T) {
+	for _, in := range []string{"", "FOO", "SIG", "0", "-1", "999", "TERM9"} {
+		if _, err := ParseSignal(in); err == nil {
+			t.Errorf("ParseSignal(%q): expected an error", in)
+		}
+	}
+}
+
+func TestParseSignalPlatformSupport(t *testing.T) {
+	_, err := ParseSignal("HUP")
+	if runtime.GOOS == "windows" && err == nil {
+		t.Error("Expected SIGHUP to be rejected on Windows")
+	}
+	if runtime.GOOS != "windows" && err != nil {
+		t.Errorf("ParseSignal(HUP) unexpected error: %v", err)
+	}
+}
+
+func TestSignalsSortedAndNamed(t *testing.T) {
+	signals := Signals()
+	if len(signals) == 0 {
+		t.Fatal("Expected at least one signal")
+	}
+	for i, info := range signals {
+		if i > 0 && signals[i-1].Number >= info.Number {
+			t.Errorf("Signals not in numeric order at %s (%d)", info.Name, info.Number)
+		}
+		if SignalName(syscall.Signal(info.Number)) != info.Name {
+			t.Errorf("SignalName(%d) = %s, want %s", info.Number, SignalName(syscall.Signal(info.Number)), info.Name)
+		}
+	}
+}
+
+func TestIsTerminatingSignal(t *testing.T) {
+	for _, sig := range []syscall.Signal{syscall.SIGTERM, syscall.SIGINT, syscall.SIGQUIT, syscall.SIGKILL} {
+		if !IsTerminatingSignal(sig) {
+			t.Errorf("Expected %s to be terminating", SignalName(sig))
+		}
+	}
+	if IsTerminatingSignal(syscall.SIGHUP) {
+		t.Error("Expected SIGHUP not to be terminating")
+	}
+}
+
+func TestSignalProcess(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are emulated with taskkill on Windows")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	pm := NewProcessManager()
+	if err := pm.SignalProcess(context.Background(), cmd.Process.Pid, syscall.SIGHUP); err != nil {
+		t.Fatalf("SignalProcess failed: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		status, ok := cmd.ProcessState.Sys().(syscall.WaitStatus)
+		if !ok || !status.Signaled() || status.Signal() != syscall.SIGHUP {
+			t.Errorf("Expected the helper to die from SIGHUP, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatal("Helper process did not exit after SIGHUP")
+	}
+
+	if err := pm.SignalProcess(context.Background(), 0, syscall.SIGTERM); err == nil {
+		t.Error("Expected an error for PID 0")
+	}
+}
+
+func TestSignalProcessesVerified(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("signals are emulated with taskkill on Windows")
+	}
+
+	// The shell ignores SIGTERM, and the disposition survives the exec
+	cmd := exec.Command("sh", "-c", `trap "" TERM; echo ready; exec sleep 30`)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("StdoutPipe failed: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	if _, err := bufio.NewReader(stdout).ReadString('\n'); err != nil {
+		t.Fatalf("helper did not start: %v", err)
+	}
+	pid := cmd.Process.Pid
+
+	pm := NewProcessManager()
+	ctx := context.Background()
+
+	results := pm.SignalProcessesVerified(ctx, []int{pid}, syscall.SIGTERM, 300*time.Millisecond)
+	if !errors.Is(results[pid], ErrStillRunning) {
+		t.Fatalf("Expected ErrStillRunning for a process ignoring SIGTERM, got %v", results[pid])
+	}
+
+	// SIGKILL cannot be ignored; the unreaped zombie counts as exited
+	results = pm.SignalProcessesVerified(ctx, []int{pid}, syscall.SIGKILL, 5*time.Second)
+	if err := results[pid]; err != nil {
+		t.Errorf("Expected SIGKILL to be verified, got %v", err)
+	}
+}
+
+func TestVerifyExitedGone(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Test requires the true command")
+	}
+
+	cmd := exec.Command("true")
+	if err := cmd.Run(); err != nil {
+		t.Skipf("cannot run helper process: %v", err)
+	}
+
+	pm := NewProcessManager()
+	if err := pm.VerifyExited(context.Background(), cmd.Process.Pid, time.Second); err != nil {
+		t.Errorf("Expected an exited process to verify, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Windows has no signals; the POSIX numbers are kept so names and numbers
+// parse the same everywhere
+var signalTable = []SignalInfo{
+	{"SIGHUP", 1, "Hangup; many daemons reload their configuration", SignalUnsupported},
+	{"SIGINT", 2, "Interrupt; emulated with taskkill (asks the process to close)", SignalEmulated},
+	{"SIGQUIT", 3, "Quit and dump core", SignalUnsupported},
+	{"SIGKILL", 9, "Kill immediately; emulated with taskkill /F", SignalEmulated},
+	{"SIGUSR1", 10, "User-defined signal 1", SignalUnsupported},
+	{"SIGUSR2", 12, "User-defined signal 2", SignalUnsupported},
+	{"SIGTERM", 15, "Terminate gracefully; emulated with taskkill (asks the process to close)", SignalEmulated},
+	{"SIGCONT", 18, "Continue a stopped process (use portctl resume)", SignalUnsupported},
+	{"SIGSTOP", 19, "Stop the process (use portctl suspend)", SignalUnsupported},
+	{"SIGTSTP", 20, "Terminal stop", SignalUnsupported},
+}
+
+// processAlive reports whether pid exists. Windows has no signal 0, so the
+// process table is queried instead.
+func processAlive(pid int) bool {
+	exists, err := process.PidExists(int32(pid))
+	return err != nil || exists
+}
+
+// sendSignal emulates sig with taskkill
+func sendSignal(ctx context.Context, pid int, sig syscall.Signal) error {
+	var cmd *exec.Cmd
+	switch sig {
+	case syscall.SIGKILL:
+		// #nosec G204: Arguments are constructed from validated integer pid, not user input
+		cmd = exec.CommandContext(ctx, "taskkill", "/F", "/PID", strconv.Itoa(pid))
+	case syscall.SIGTERM, syscall.SIGINT:
+		// #nosec G204: Arguments are constructed from validated integer pid, not user input
+		cmd = exec.CommandContext(ctx, "taskkill", "/PID", strconv.Itoa(pid))
+	default:
+		return fmt.Errorf("%s is not supported on windows", SignalName(sig))
+	}
+	return cmd.Run()
+}
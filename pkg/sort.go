@@ -0,0 +1,116 @@
+package process
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SortKey is one field to order processes by
+type SortKey struct {
+	Field string
+	Desc  bool
+}
+
+// sortFields maps each sortable field (and its aliases) to its canonical name
+// and whether it sorts descending by default
+var sortFields = map[string]struct {
+	name string
+	desc bool
+}{
+	"port":    {"port", false},
+	"pid":     {"pid", false},
+	"cpu":     {"cpu", true},
+	"memory":  {"memory", true},
+	"mem":     {"memory", true},
+	"command": {"command", false},
+	"cmd":     {"command", false},
+	"service": {"service", false},
+	"user":    {"user", false},
+}
+
+// SortFieldNames lists the canonical sortable field names
+var SortFieldNames = []string{"port", "pid", "cpu", "memory", "command", "service", "user"}
+
+// ParseSortKeys parses a comma-separated list of sort fields such as
+// "service,port". Each field uses its natural direction (cpu and memory
+// descending, everything else ascending) unless order is "asc" or "desc",
+// which applies to every key. An empty spec sorts by port.
+func ParseSortKeys(spec, order string) ([]SortKey, error) {
+	order = strings.ToLower(strings.TrimSpace(order))
+	if order != "" && order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("invalid sort order %q (must be asc or desc)", order)
+	}
+	if strings.TrimSpace(spec) == "" {
+		spec = "port"
+	}
+
+	var keys []SortKey
+	for _, name := range strings.Split(spec, ",") {
+		field, ok := sortFields[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("invalid sort field %q (must be one of: %s)",
+				strings.TrimSpace(name), strings.Join(SortFieldNames, ", "))
+		}
+
+		key := SortKey{Field: field.name, Desc: field.desc}
+		if order != "" {
+			key.Desc = order == "desc"
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// SortProcesses orders processes by each key in turn, later keys breaking
+// ties left by earlier ones. The sort is stable, so processes equal on every
+// key keep their original order.
+func (pm *ProcessManager) SortProcesses(processes []Process, keys []SortKey) []Process {
+	sort.SliceStable(processes, func(i, j int) bool {
+		for _, key := range keys {
+			c := compareProcesses(&processes[i], &processes[j], key.Field)
+			if c == 0 {
+				continue
+			}
+			if key.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+
+	return processes
+}
+
+// compareProcesses returns -1, 0 or 1 comparing a and b on field
+func compareProcesses(a, b *Process, field string) int {
+	switch field {
+	case "pid":
+		return compareOrdered(a.PID, b.PID)
+	case "cpu":
+		return compareOrdered(a.CPUPercent, b.CPUPercent)
+	case "memory":
+		return compareOrdered(a.MemoryMB, b.MemoryMB)
+	case "command":
+		return strings.Compare(a.Command, b.Command)
+	case "service":
+		return strings.Compare(a.ServiceType, b.ServiceType)
+	case "user":
+		return strings.Compare(a.User, b.User)
+	default:
+		return compareOrdered(a.Port, b.Port)
+	}
+}
+
+func compareOrdered[T int | float32 | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,88 @@
+package process
+
+import (
+	"reflect"
+	"testing"
+)
+
+func pids(processes []Process) []int {
+	out := make([]int, len(processes))
+	for i, proc := range processes {
+		out[i] = proc.PID
+	}
+	return out
+}
+
+func TestParseSortKeys(t *testing.T) {
+	tests := []struct {
+		spec  string
+		order string
+		want  []SortKey
+	}{
+		{"", "", []SortKey{{"port", false}}},
+		{"cpu", "", []SortKey{{"cpu", true}}},
+		{"mem", "", []SortKey{{"memory", true}}},
+		{"service, port", "", []SortKey{{"service", false}, {"port", false}}},
+		{"cpu,pid", "asc", []SortKey{{"cpu", false}, {"pid", false}}},
+		{"Port", "DESC", []SortKey{{"port", true}}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSortKeys(tt.spec, tt.order)
+		if err != nil {
+			t.Errorf("ParseSortKeys(%q, %q) returned error: %v", tt.spec, tt.order, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSortKeys(%q, %q) = %v, want %v", tt.spec, tt.order, got, tt.want)
+		}
+	}
+}
+
+func TestParseSortKeysInvalid(t *testing.T) {
+	invalid := []struct{ spec, order string }{
+		{"size", ""},
+		{"port,", ""},
+		{"port", "up"},
+	}
+
+	for _, tt := range invalid {
+		if _, err := ParseSortKeys(tt.spec, tt.order); err == nil {
+			t.Errorf("ParseSortKeys(%q, %q) expected error, got nil", tt.spec, tt.order)
+		}
+	}
+}
+
+func TestSortProcessesMultiKey(t *testing.T) {
+	pm := NewProcessManager()
+	processes := []Process{
+		{PID: 1, Port: 8080, ServiceType: "HTTP-Alt", CPUPercent: 5},
+		{PID: 2, Port: 3001, ServiceType: "Development", CPUPercent: 1},
+		{PID: 3, Port: 3000, ServiceType: "Development", CPUPercent: 1},
+		{PID: 4, Port: 5432, ServiceType: "PostgreSQL", CPUPercent: 9},
+		{PID: 5, Port: 3000, ServiceType: "Development", CPUPercent: 7},
+	}
+
+	tests := []struct {
+		spec  string
+		order string
+		want  []int
+	}{
+		{"service,port", "", []int{3, 5, 2, 1, 4}},
+		{"service,port", "desc", []int{4, 1, 2, 3, 5}},
+		{"cpu,port", "", []int{4, 5, 1, 3, 2}},
+		{"port,cpu", "", []int{5, 3, 2, 4, 1}},
+		{"port", "", []int{3, 5, 2, 4, 1}}, // stable for equal ports
+	}
+
+	for _, tt := range tests {
+		keys, err := ParseSortKeys(tt.spec, tt.order)
+		if err != nil {
+			t.Fatalf("ParseSortKeys(%q) returned error: %v", tt.spec, err)
+		}
+		sorted := pm.SortProcesses(append([]Process(nil), processes...), keys)
+		if got := pids(sorted); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sort %q %q: got PIDs %v, want %v", tt.spec, tt.order, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package process
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// StreamProcesses enumerates processes like GetAllProcesses (or
+// GetProcessesOnPort when port > 0), but calls emit with each process as soon
+// as it has been enriched instead of returning them all at once, so the first
+// result is available long before a large listing completes. Processes arrive
+// in completion order, not sorted, and the cache is always bypassed.
+//
+// emit is never called concurrently. A slow emit holds back the workers, so a
+// caller writing to a network stream gets backpressure for free. Enumeration
+// stops when emit returns an error or ctx is cancelled, and that error is
+// returned.
+func (pm *ProcessManager) StreamProcesses(ctx context.Context, port int, emit func(Process) error) error {
+	processes, err := pm.getBasicProcesses(ctx, port)
+	if err != nil {
+		return err
+	}
+	return pm.streamEnhanced(ctx, processes, emit)
+}
+
+// streamEnhanced enriches processes with a bounded pool of workers and passes
+// each one to emit as it completes. CPU usage is measured from a sample taken
+// before the pool starts to one taken when the process finishes, waiting out
+// the rest of CPUSampleInterval if enrichment was quicker than that.
+func (pm *ProcessManager) streamEnhanced(ctx context.Context, processes []Process, emit func(Process) error) error {
+	if !pm.enableMetrics.Load() {
+		for i := range processes {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			pm.classifyProcess(&processes[i])
+			if err := emit(processes[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sampleStart := time.Now()
+	cpuBefore := sampleCPUTimes(ctx, processes)
+
+	workers := pm.workers
+	if workers > len(processes) {
+		workers = len(processes)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan int)
+	results := make(chan Process)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				proc := processes[i]
+				pm.enhanceProcess(ctx, &proc)
+				if !waitUntil(ctx, sampleStart.Add(CPUSampleInterval)) {
+					return
+				}
+				if before, ok := cpuBefore[proc.PID]; ok {
+					if after, ok := cpuTime(ctx, proc.PID); ok {
+						proc.CPUPercent = cpuPercentBetween(before, after, time.Since(sampleStart))
+					}
+				}
+				select {
+				case results <- proc:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		for i := range processes {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var emitErr error
+	for proc := range results {
+		if emitErr != nil {
+			continue
+		}
+		if err := emit(proc); err != nil {
+			// Stop the workers, then drain anything already in flight
+			emitErr = err
+			cancel()
+		}
+	}
+	if emitErr != nil {
+		return emitErr
+	}
+	return ctx.Err()
+}
+
+// waitUntil blocks until deadline, returning false if ctx is cancelled first
+func waitUntil(ctx context.Context, deadline time.Time) bool {
+	wait := time.Until(deadline)
+	if wait <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
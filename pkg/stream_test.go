@@ -0,0 +1,66 @@
+package process
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStreamEnhancedEmitsEveryProcess(t *testing.T) {
+	pm := NewProcessManager()
+
+	var inEmit atomic.Int32
+	seen := make(map[int]bool)
+	err := pm.streamEnhanced(context.Background(), syntheticProcesses(30), func(proc Process) error {
+		if inEmit.Add(1) != 1 {
+			t.Error("emit was called concurrently")
+		}
+		defer inEmit.Add(-1)
+
+		if proc.BindScope != BindScopeAll {
+			t.Errorf("Process on port %d was not enhanced: %+v", proc.Port, proc)
+		}
+		seen[proc.Port] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("streamEnhanced failed: %v", err)
+	}
+	if len(seen) != 30 {
+		t.Errorf("Expected 30 distinct processes, got %d", len(seen))
+	}
+}
+
+func TestStreamEnhancedStopsOnEmitError(t *testing.T) {
+	stop := errors.New("client went away")
+
+	for _, metrics := range []bool{true, false} {
+		pm := NewProcessManager().WithMetrics(metrics)
+		emitted := 0
+		err := pm.streamEnhanced(context.Background(), syntheticProcesses(50), func(Process) error {
+			emitted++
+			if emitted == 3 {
+				return stop
+			}
+			return nil
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("metrics=%v: expected emit error, got %v", metrics, err)
+		}
+		if emitted != 3 {
+			t.Errorf("metrics=%v: expected emit to stop after 3 calls, got %d", metrics, emitted)
+		}
+	}
+}
+
+func TestStreamEnhancedCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	pm := NewProcessManager()
+	err := pm.streamEnhanced(ctx, syntheticProcesses(20), func(Process) error { return nil })
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package process
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// Suspend pauses a process until Resume is called: SIGSTOP on Unix,
+// NtSuspendProcess on Windows. A suspended process keeps its ports open but
+// does not run, so connections to them hang rather than being refused.
+func (pm *ProcessManager) Suspend(ctx context.Context, pid int) error {
+	if err := checkSignalTarget(ctx, pid); err != nil {
+		return err
+	}
+	if err := suspendProcess(pid); err != nil {
+		return fmt.Errorf("failed to suspend process %d: %w", pid, err)
+	}
+	return nil
+}
+
+// Resume continues a process paused by Suspend (or by SIGSTOP/Ctrl+Z):
+// SIGCONT on Unix, NtResumeProcess on Windows
+func (pm *ProcessManager) Resume(ctx context.Context, pid int) error {
+	if err := checkSignalTarget(ctx, pid); err != nil {
+		return err
+	}
+	if err := resumeProcess(pid); err != nil {
+		return fmt.Errorf("failed to resume process %d: %w", pid, err)
+	}
+	return nil
+}
+
+// checkSignalTarget verifies that pid is valid and names a running process
+func checkSignalTarget(ctx context.Context, pid int) error {
+	if pid <= 0 || pid > 2147483647 {
+		return fmt.Errorf("invalid PID: %d", pid)
+	}
+	exists, err := process.PidExistsWithContext(ctx, int32(pid))
+	if err != nil {
+		return fmt.Errorf("cannot look up PID %d: %v", pid, err)
+	}
+	if !exists {
+		return fmt.Errorf("process %d not found", pid)
+	}
+	return nil
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package process
+
+import "syscall"
+
+// suspendProcess stops pid with SIGSTOP, which cannot be caught or ignored
+func suspendProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGSTOP)
+}
+
+// resumeProcess continues pid with SIGCONT
+func resumeProcess(pid int) error {
+	return syscall.Kill(pid, syscall.SIGCONT)
+}
@@ -0,0 +1,75 @@
+package process
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+func TestSuspendAndResume(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("process status is not reported on Windows")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start helper process: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+
+	ctx := context.Background()
+	pm := NewProcessManager()
+	p, err := process.NewProcessWithContext(ctx, int32(cmd.Process.Pid))
+	if err != nil {
+		t.Fatalf("cannot inspect helper process: %v", err)
+	}
+
+	if err := pm.Suspend(ctx, cmd.Process.Pid); err != nil {
+		t.Fatalf("Suspend failed: %v", err)
+	}
+	if !waitForStopped(ctx, p, true) {
+		t.Error("Expected a stopped process after Suspend")
+	}
+
+	if err := pm.Resume(ctx, cmd.Process.Pid); err != nil {
+		t.Fatalf("Resume failed: %v", err)
+	}
+	if !waitForStopped(ctx, p, false) {
+		t.Error("Expected a running process after Resume")
+	}
+}
+
+// waitForStopped polls until p is (or is no longer) stopped, since signals are
+// delivered asynchronously
+func waitForStopped(ctx context.Context, p *process.Process, stopped bool) bool {
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if status, err := p.StatusWithContext(ctx); err == nil && slices.Contains(status, process.Stop) == stopped {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return false
+}
+
+func TestSuspendRejectsInvalidPID(t *testing.T) {
+	pm := NewProcessManager()
+	ctx := context.Background()
+
+	for _, pid := range []int{0, -1, 2147483647} {
+		if err := pm.Suspend(ctx, pid); err == nil {
+			t.Errorf("Suspend(%d): expected an error", pid)
+		}
+		if err := pm.Resume(ctx, pid); err == nil {
+			t.Errorf("Resume(%d): expected an error", pid)
+		}
+	}
+}
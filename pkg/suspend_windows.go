@@ -0,0 +1,40 @@
+package process
+
+import "golang.org/x/sys/windows"
+
+var (
+	ntdll                = windows.NewLazySystemDLL("ntdll.dll")
+	procNtSuspendProcess = ntdll.NewProc("NtSuspendProcess")
+	procNtResumeProcess  = ntdll.NewProc("NtResumeProcess")
+)
+
+// suspendProcess suspends every thread of pid
+func suspendProcess(pid int) error {
+	return callProcessNtProc(procNtSuspendProcess, pid)
+}
+
+// resumeProcess resumes every thread of pid
+func resumeProcess(pid int) error {
+	return callProcessNtProc(procNtResumeProcess, pid)
+}
+
+// callProcessNtProc opens pid and calls an ntdll function taking only the process handle
+func callProcessNtProc(proc *windows.LazyProc, pid int) error {
+	if err := proc.Find(); err != nil {
+		return err
+	}
+
+	handle, err := windows.OpenProcess(windows.PROCESS_SUSPEND_RESUME, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = windows.CloseHandle(handle)
+	}()
+
+	status, _, _ := proc.Call(uintptr(handle))
+	if status != 0 {
+		return windows.NTStatus(status)
+	}
+	return nil
+}
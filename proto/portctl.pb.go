@@ -24,9 +24,15 @@ const (
 // Request to list processes
 type ListProcessesRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Port          *int32                 `protobuf:"varint,1,opt,name=port,proto3,oneof" json:"port,omitempty"`      // Filter by specific port
-	Service       *string                `protobuf:"bytes,2,opt,name=service,proto3,oneof" json:"service,omitempty"` // Filter by service name
-	User          *string                `protobuf:"bytes,3,opt,name=user,proto3,oneof" json:"user,omitempty"`       // Filter by user
+	Port          *int32                 `protobuf:"varint,1,opt,name=port,proto3,oneof" json:"port,omitempty"`                                   // Filter by specific port
+	Service       *string                `protobuf:"bytes,2,opt,name=service,proto3,oneof" json:"service,omitempty"`                              // Filter by service name
+	User          *string                `protobuf:"bytes,3,opt,name=user,proto3,oneof" json:"user,omitempty"`                                    // Filter by user
+	Fields        []string               `protobuf:"bytes,4,rep,name=fields,proto3" json:"fields,omitempty"`                                      // Fields to include in each process (all if empty)
+	MemoryLimit   *float64               `protobuf:"fixed64,5,opt,name=memory_limit,json=memoryLimit,proto3,oneof" json:"memory_limit,omitempty"` // Only processes using more than X MB
+	CpuLimit      *float64               `protobuf:"fixed64,6,opt,name=cpu_limit,json=cpuLimit,proto3,oneof" json:"cpu_limit,omitempty"`          // Only processes using more than X% CPU
+	SortBy        *string                `protobuf:"bytes,7,opt,name=sort_by,json=sortBy,proto3,oneof" json:"sort_by,omitempty"`                  // Comma list of port, pid, cpu, memory, command, service, user
+	Limit         *int32                 `protobuf:"varint,8,opt,name=limit,proto3,oneof" json:"limit,omitempty"`                                 // Maximum number of processes to return
+	Offset        *int32                 `protobuf:"varint,9,opt,name=offset,proto3,oneof" json:"offset,omitempty"`                               // Number of processes to skip
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -82,6 +88,48 @@ func (x *ListProcessesRequest) GetUser() string {
 	return ""
 }
 
+func (x *ListProcessesRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *ListProcessesRequest) GetMemoryLimit() float64 {
+	if x != nil && x.MemoryLimit != nil {
+		return *x.MemoryLimit
+	}
+	return 0
+}
+
+func (x *ListProcessesRequest) GetCpuLimit() float64 {
+	if x != nil && x.CpuLimit != nil {
+		return *x.CpuLimit
+	}
+	return 0
+}
+
+func (x *ListProcessesRequest) GetSortBy() string {
+	if x != nil && x.SortBy != nil {
+		return *x.SortBy
+	}
+	return ""
+}
+
+func (x *ListProcessesRequest) GetLimit() int32 {
+	if x != nil && x.Limit != nil {
+		return *x.Limit
+	}
+	return 0
+}
+
+func (x *ListProcessesRequest) GetOffset() int32 {
+	if x != nil && x.Offset != nil {
+		return *x.Offset
+	}
+	return 0
+}
+
 // A single process
 type Process struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -187,6 +235,8 @@ func (x *Process) GetStartTime() int64 {
 type ListProcessesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Processes     []*Process             `protobuf:"bytes,1,rep,name=processes,proto3" json:"processes,omitempty"`
+	IgnoredFields []string               `protobuf:"bytes,2,rep,name=ignored_fields,json=ignoredFields,proto3" json:"ignored_fields,omitempty"` // Requested fields that were not recognised
+	Total         int32                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`                                     // Matching processes before pagination
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -228,6 +278,20 @@ func (x *ListProcessesResponse) GetProcesses() []*Process {
 	return nil
 }
 
+func (x *ListProcessesResponse) GetIgnoredFields() []string {
+	if x != nil {
+		return x.IgnoredFields
+	}
+	return nil
+}
+
+func (x *ListProcessesResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
 // Request to kill a process
 type KillProcessRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -549,9 +613,10 @@ func (x *ScanPortsResponse) GetResults() []*PortScanResult {
 
 // Request for system stats
 type SystemStatsRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	IncludePerCore bool                   `protobuf:"varint,1,opt,name=include_per_core,json=includePerCore,proto3" json:"include_per_core,omitempty"` // Include per-core CPU utilization
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *SystemStatsRequest) Reset() {
@@ -584,15 +649,28 @@ func (*SystemStatsRequest) Descriptor() ([]byte, []int) {
 	return file_proto_portctl_proto_rawDescGZIP(), []int{8}
 }
 
+func (x *SystemStatsRequest) GetIncludePerCore() bool {
+	if x != nil {
+		return x.IncludePerCore
+	}
+	return false
+}
+
 // System statistics
 type SystemStatsResponse struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	CpuPercent     float64                `protobuf:"fixed64,1,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
-	MemoryPercent  float64                `protobuf:"fixed64,2,opt,name=memory_percent,json=memoryPercent,proto3" json:"memory_percent,omitempty"`
-	TotalProcesses int32                  `protobuf:"varint,3,opt,name=total_processes,json=totalProcesses,proto3" json:"total_processes,omitempty"`
-	ListeningPorts int32                  `protobuf:"varint,4,opt,name=listening_ports,json=listeningPorts,proto3" json:"listening_ports,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	CpuPercent             float64                `protobuf:"fixed64,1,opt,name=cpu_percent,json=cpuPercent,proto3" json:"cpu_percent,omitempty"`
+	MemoryPercent          float64                `protobuf:"fixed64,2,opt,name=memory_percent,json=memoryPercent,proto3" json:"memory_percent,omitempty"`
+	TotalProcesses         int32                  `protobuf:"varint,3,opt,name=total_processes,json=totalProcesses,proto3" json:"total_processes,omitempty"`
+	ListeningPorts         int32                  `protobuf:"varint,4,opt,name=listening_ports,json=listeningPorts,proto3" json:"listening_ports,omitempty"`
+	PerCorePercent         []float64              `protobuf:"fixed64,5,rep,packed,name=per_core_percent,json=perCorePercent,proto3" json:"per_core_percent,omitempty"` // Only set when include_per_core is requested
+	BytesSent              uint64                 `protobuf:"varint,6,opt,name=bytes_sent,json=bytesSent,proto3" json:"bytes_sent,omitempty"`
+	BytesRecv              uint64                 `protobuf:"varint,7,opt,name=bytes_recv,json=bytesRecv,proto3" json:"bytes_recv,omitempty"`
+	SendRate               float64                `protobuf:"fixed64,8,opt,name=send_rate,json=sendRate,proto3" json:"send_rate,omitempty"` // Bytes per second
+	RecvRate               float64                `protobuf:"fixed64,9,opt,name=recv_rate,json=recvRate,proto3" json:"recv_rate,omitempty"` // Bytes per second
+	EstablishedConnections int32                  `protobuf:"varint,10,opt,name=established_connections,json=establishedConnections,proto3" json:"established_connections,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
 func (x *SystemStatsResponse) Reset() {
@@ -653,6 +731,48 @@ func (x *SystemStatsResponse) GetListeningPorts() int32 {
 	return 0
 }
 
+func (x *SystemStatsResponse) GetPerCorePercent() []float64 {
+	if x != nil {
+		return x.PerCorePercent
+	}
+	return nil
+}
+
+func (x *SystemStatsResponse) GetBytesSent() uint64 {
+	if x != nil {
+		return x.BytesSent
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetBytesRecv() uint64 {
+	if x != nil {
+		return x.BytesRecv
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetSendRate() float64 {
+	if x != nil {
+		return x.SendRate
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetRecvRate() float64 {
+	if x != nil {
+		return x.RecvRate
+	}
+	return 0
+}
+
+func (x *SystemStatsResponse) GetEstablishedConnections() int32 {
+	if x != nil {
+		return x.EstablishedConnections
+	}
+	return 0
+}
+
 // Request for server status
 type StatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -755,15 +875,28 @@ var File_proto_portctl_proto protoreflect.FileDescriptor
 
 const file_proto_portctl_proto_rawDesc = "" +
 	"\n" +
-	"\x13proto/portctl.proto\x12\aportctl\"\x85\x01\n" +
+	"\x13proto/portctl.proto\x12\aportctl\"\xfd\x02\n" +
 	"\x14ListProcessesRequest\x12\x17\n" +
 	"\x04port\x18\x01 \x01(\x05H\x00R\x04port\x88\x01\x01\x12\x1d\n" +
 	"\aservice\x18\x02 \x01(\tH\x01R\aservice\x88\x01\x01\x12\x17\n" +
-	"\x04user\x18\x03 \x01(\tH\x02R\x04user\x88\x01\x01B\a\n" +
+	"\x04user\x18\x03 \x01(\tH\x02R\x04user\x88\x01\x01\x12\x16\n" +
+	"\x06fields\x18\x04 \x03(\tR\x06fields\x12&\n" +
+	"\fmemory_limit\x18\x05 \x01(\x01H\x03R\vmemoryLimit\x88\x01\x01\x12 \n" +
+	"\tcpu_limit\x18\x06 \x01(\x01H\x04R\bcpuLimit\x88\x01\x01\x12\x1c\n" +
+	"\asort_by\x18\a \x01(\tH\x05R\x06sortBy\x88\x01\x01\x12\x19\n" +
+	"\x05limit\x18\b \x01(\x05H\x06R\x05limit\x88\x01\x01\x12\x1b\n" +
+	"\x06offset\x18\t \x01(\x05H\aR\x06offset\x88\x01\x01B\a\n" +
 	"\x05_portB\n" +
 	"\n" +
 	"\b_serviceB\a\n" +
-	"\x05_user\"\xdd\x01\n" +
+	"\x05_userB\x0f\n" +
+	"\r_memory_limitB\f\n" +
+	"\n" +
+	"_cpu_limitB\n" +
+	"\n" +
+	"\b_sort_byB\b\n" +
+	"\x06_limitB\t\n" +
+	"\a_offset\"\xdd\x01\n" +
 	"\aProcess\x12\x10\n" +
 	"\x03pid\x18\x01 \x01(\x05R\x03pid\x12\x12\n" +
 	"\x04port\x18\x02 \x01(\x05R\x04port\x12\x18\n" +
@@ -774,9 +907,11 @@ const file_proto_portctl_proto_rawDesc = "" +
 	"cpuPercent\x12\x1b\n" +
 	"\tmemory_mb\x18\a \x01(\x01R\bmemoryMb\x12\x1d\n" +
 	"\n" +
-	"start_time\x18\b \x01(\x03R\tstartTime\"G\n" +
+	"start_time\x18\b \x01(\x03R\tstartTime\"\x84\x01\n" +
 	"\x15ListProcessesResponse\x12.\n" +
-	"\tprocesses\x18\x01 \x03(\v2\x10.portctl.ProcessR\tprocesses\"^\n" +
+	"\tprocesses\x18\x01 \x03(\v2\x10.portctl.ProcessR\tprocesses\x12%\n" +
+	"\x0eignored_fields\x18\x02 \x03(\tR\rignoredFields\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x05R\x05total\"^\n" +
 	"\x12KillProcessRequest\x12\x12\n" +
 	"\x03pid\x18\x01 \x01(\x05H\x00R\x03pid\x12\x14\n" +
 	"\x04port\x18\x02 \x01(\x05H\x00R\x04port\x12\x14\n" +
@@ -796,22 +931,33 @@ const file_proto_portctl_proto_rawDesc = "" +
 	"\x06status\x18\x02 \x01(\tR\x06status\x12\x18\n" +
 	"\aservice\x18\x03 \x01(\tR\aservice\"F\n" +
 	"\x11ScanPortsResponse\x121\n" +
-	"\aresults\x18\x01 \x03(\v2\x17.portctl.PortScanResultR\aresults\"\x14\n" +
-	"\x12SystemStatsRequest\"\xaf\x01\n" +
+	"\aresults\x18\x01 \x03(\v2\x17.portctl.PortScanResultR\aresults\">\n" +
+	"\x12SystemStatsRequest\x12(\n" +
+	"\x10include_per_core\x18\x01 \x01(\bR\x0eincludePerCore\"\x8a\x03\n" +
 	"\x13SystemStatsResponse\x12\x1f\n" +
 	"\vcpu_percent\x18\x01 \x01(\x01R\n" +
 	"cpuPercent\x12%\n" +
 	"\x0ememory_percent\x18\x02 \x01(\x01R\rmemoryPercent\x12'\n" +
 	"\x0ftotal_processes\x18\x03 \x01(\x05R\x0etotalProcesses\x12'\n" +
-	"\x0flistening_ports\x18\x04 \x01(\x05R\x0elisteningPorts\"\x0f\n" +
+	"\x0flistening_ports\x18\x04 \x01(\x05R\x0elisteningPorts\x12(\n" +
+	"\x10per_core_percent\x18\x05 \x03(\x01R\x0eperCorePercent\x12\x1d\n" +
+	"\n" +
+	"bytes_sent\x18\x06 \x01(\x04R\tbytesSent\x12\x1d\n" +
+	"\n" +
+	"bytes_recv\x18\a \x01(\x04R\tbytesRecv\x12\x1b\n" +
+	"\tsend_rate\x18\b \x01(\x01R\bsendRate\x12\x1b\n" +
+	"\trecv_rate\x18\t \x01(\x01R\brecvRate\x127\n" +
+	"\x17established_connections\x18\n" +
+	" \x01(\x05R\x16establishedConnections\"\x0f\n" +
 	"\rStatusRequest\"r\n" +
 	"\x0eStatusResponse\x12\x18\n" +
 	"\aversion\x18\x01 \x01(\tR\aversion\x12%\n" +
 	"\x0euptime_seconds\x18\x02 \x01(\x03R\ruptimeSeconds\x12\x1f\n" +
 	"\vserver_type\x18\x03 \x01(\tR\n" +
-	"serverType2\xf9\x02\n" +
+	"serverType2\xc3\x03\n" +
 	"\x0ePortctlService\x12N\n" +
 	"\rListProcesses\x12\x1d.portctl.ListProcessesRequest\x1a\x1e.portctl.ListProcessesResponse\x12H\n" +
+	"\x13ListProcessesStream\x12\x1d.portctl.ListProcessesRequest\x1a\x10.portctl.Process0\x01\x12H\n" +
 	"\vKillProcess\x12\x1b.portctl.KillProcessRequest\x1a\x1c.portctl.KillProcessResponse\x12B\n" +
 	"\tScanPorts\x12\x19.portctl.ScanPortsRequest\x1a\x1a.portctl.ScanPortsResponse\x12K\n" +
 	"\x0eGetSystemStats\x12\x1b.portctl.SystemStatsRequest\x1a\x1c.portctl.SystemStatsResponse\x12<\n" +
@@ -848,17 +994,19 @@ var file_proto_portctl_proto_depIdxs = []int32{
 	1,  // 0: portctl.ListProcessesResponse.processes:type_name -> portctl.Process
 	6,  // 1: portctl.ScanPortsResponse.results:type_name -> portctl.PortScanResult
 	0,  // 2: portctl.PortctlService.ListProcesses:input_type -> portctl.ListProcessesRequest
-	3,  // 3: portctl.PortctlService.KillProcess:input_type -> portctl.KillProcessRequest
-	5,  // 4: portctl.PortctlService.ScanPorts:input_type -> portctl.ScanPortsRequest
-	8,  // 5: portctl.PortctlService.GetSystemStats:input_type -> portctl.SystemStatsRequest
-	10, // 6: portctl.PortctlService.GetStatus:input_type -> portctl.StatusRequest
-	2,  // 7: portctl.PortctlService.ListProcesses:output_type -> portctl.ListProcessesResponse
-	4,  // 8: portctl.PortctlService.KillProcess:output_type -> portctl.KillProcessResponse
-	7,  // 9: portctl.PortctlService.ScanPorts:output_type -> portctl.ScanPortsResponse
-	9,  // 10: portctl.PortctlService.GetSystemStats:output_type -> portctl.SystemStatsResponse
-	11, // 11: portctl.PortctlService.GetStatus:output_type -> portctl.StatusResponse
-	7,  // [7:12] is the sub-list for method output_type
-	2,  // [2:7] is the sub-list for method input_type
+	0,  // 3: portctl.PortctlService.ListProcessesStream:input_type -> portctl.ListProcessesRequest
+	3,  // 4: portctl.PortctlService.KillProcess:input_type -> portctl.KillProcessRequest
+	5,  // 5: portctl.PortctlService.ScanPorts:input_type -> portctl.ScanPortsRequest
+	8,  // 6: portctl.PortctlService.GetSystemStats:input_type -> portctl.SystemStatsRequest
+	10, // 7: portctl.PortctlService.GetStatus:input_type -> portctl.StatusRequest
+	2,  // 8: portctl.PortctlService.ListProcesses:output_type -> portctl.ListProcessesResponse
+	1,  // 9: portctl.PortctlService.ListProcessesStream:output_type -> portctl.Process
+	4,  // 10: portctl.PortctlService.KillProcess:output_type -> portctl.KillProcessResponse
+	7,  // 11: portctl.PortctlService.ScanPorts:output_type -> portctl.ScanPortsResponse
+	9,  // 12: portctl.PortctlService.GetSystemStats:output_type -> portctl.SystemStatsResponse
+	11, // 13: portctl.PortctlService.GetStatus:output_type -> portctl.StatusResponse
+	8,  // [8:14] is the sub-list for method output_type
+	2,  // [2:8] is the sub-list for method input_type
 	2,  // [2:2] is the sub-list for extension type_name
 	2,  // [2:2] is the sub-list for extension extendee
 	0,  // [0:2] is the sub-list for field type_name
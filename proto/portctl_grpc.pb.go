@@ -19,11 +19,12 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PortctlService_ListProcesses_FullMethodName  = "/portctl.PortctlService/ListProcesses"
-	PortctlService_KillProcess_FullMethodName    = "/portctl.PortctlService/KillProcess"
-	PortctlService_ScanPorts_FullMethodName      = "/portctl.PortctlService/ScanPorts"
-	PortctlService_GetSystemStats_FullMethodName = "/portctl.PortctlService/GetSystemStats"
-	PortctlService_GetStatus_FullMethodName      = "/portctl.PortctlService/GetStatus"
+	PortctlService_ListProcesses_FullMethodName       = "/portctl.PortctlService/ListProcesses"
+	PortctlService_ListProcessesStream_FullMethodName = "/portctl.PortctlService/ListProcessesStream"
+	PortctlService_KillProcess_FullMethodName         = "/portctl.PortctlService/KillProcess"
+	PortctlService_ScanPorts_FullMethodName           = "/portctl.PortctlService/ScanPorts"
+	PortctlService_GetSystemStats_FullMethodName      = "/portctl.PortctlService/GetSystemStats"
+	PortctlService_GetStatus_FullMethodName           = "/portctl.PortctlService/GetStatus"
 )
 
 // PortctlServiceClient is the client API for PortctlService service.
@@ -34,6 +35,10 @@ const (
 type PortctlServiceClient interface {
 	// List running processes, optionally filtered by port or service
 	ListProcesses(ctx context.Context, in *ListProcessesRequest, opts ...grpc.CallOption) (*ListProcessesResponse, error)
+	// Stream processes as each one is enumerated and enhanced, for hosts with
+	// very many listeners. Results arrive unsorted, so sort_by and offset are
+	// rejected; limit ends the stream after that many processes.
+	ListProcessesStream(ctx context.Context, in *ListProcessesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Process], error)
 	// Kill a process by PID or port
 	KillProcess(ctx context.Context, in *KillProcessRequest, opts ...grpc.CallOption) (*KillProcessResponse, error)
 	// Scan ports on a host
@@ -62,6 +67,25 @@ func (c *portctlServiceClient) ListProcesses(ctx context.Context, in *ListProces
 	return out, nil
 }
 
+func (c *portctlServiceClient) ListProcessesStream(ctx context.Context, in *ListProcessesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Process], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PortctlService_ServiceDesc.Streams[0], PortctlService_ListProcessesStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListProcessesRequest, Process]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PortctlService_ListProcessesStreamClient = grpc.ServerStreamingClient[Process]
+
 func (c *portctlServiceClient) KillProcess(ctx context.Context, in *KillProcessRequest, opts ...grpc.CallOption) (*KillProcessResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(KillProcessResponse)
@@ -110,6 +134,10 @@ func (c *portctlServiceClient) GetStatus(ctx context.Context, in *StatusRequest,
 type PortctlServiceServer interface {
 	// List running processes, optionally filtered by port or service
 	ListProcesses(context.Context, *ListProcessesRequest) (*ListProcessesResponse, error)
+	// Stream processes as each one is enumerated and enhanced, for hosts with
+	// very many listeners. Results arrive unsorted, so sort_by and offset are
+	// rejected; limit ends the stream after that many processes.
+	ListProcessesStream(*ListProcessesRequest, grpc.ServerStreamingServer[Process]) error
 	// Kill a process by PID or port
 	KillProcess(context.Context, *KillProcessRequest) (*KillProcessResponse, error)
 	// Scan ports on a host
@@ -131,6 +159,9 @@ type UnimplementedPortctlServiceServer struct{}
 func (UnimplementedPortctlServiceServer) ListProcesses(context.Context, *ListProcessesRequest) (*ListProcessesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListProcesses not implemented")
 }
+func (UnimplementedPortctlServiceServer) ListProcessesStream(*ListProcessesRequest, grpc.ServerStreamingServer[Process]) error {
+	return status.Errorf(codes.Unimplemented, "method ListProcessesStream not implemented")
+}
 func (UnimplementedPortctlServiceServer) KillProcess(context.Context, *KillProcessRequest) (*KillProcessResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method KillProcess not implemented")
 }
@@ -182,6 +213,17 @@ func _PortctlService_ListProcesses_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PortctlService_ListProcessesStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListProcessesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PortctlServiceServer).ListProcessesStream(m, &grpc.GenericServerStream[ListProcessesRequest, Process]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PortctlService_ListProcessesStreamServer = grpc.ServerStreamingServer[Process]
+
 func _PortctlService_KillProcess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(KillProcessRequest)
 	if err := dec(in); err != nil {
@@ -282,6 +324,12 @@ var PortctlService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _PortctlService_GetStatus_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListProcessesStream",
+			Handler:       _PortctlService_ListProcessesStream_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "proto/portctl.proto",
 }